@@ -10,14 +10,21 @@ package main
 // @license.name MIT
 // @license.url https://opensource.org/licenses/MIT
 // @BasePath /
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Staff access token or guest token, as "Bearer <token>"
 
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -29,12 +36,20 @@ import (
 
 	_ "restaurant/docs"
 
+	"restaurant/internal/apikey"
+	"restaurant/internal/auth"
+	"restaurant/internal/cache"
+	"restaurant/internal/media"
 	"restaurant/internal/menu"
 	"restaurant/internal/middleware"
 	"restaurant/internal/order"
 	"restaurant/internal/pool"
+	"restaurant/internal/reservation"
 	"restaurant/internal/session"
 	"restaurant/internal/shutdown"
+	"restaurant/internal/staff"
+	"restaurant/internal/storage"
+	"restaurant/internal/waitlist"
 )
 
 func main() {
@@ -98,20 +113,104 @@ func main() {
 		return db.Close()
 	})
 
+	// Media storage for uploaded images (local filesystem, served under /media)
+	mediaDir := os.Getenv("MEDIA_DIR")
+	if mediaDir == "" {
+		mediaDir = "./uploads"
+	}
+	mediaBaseURL := os.Getenv("MEDIA_BASE_URL")
+	if mediaBaseURL == "" {
+		mediaBaseURL = "/media"
+	}
+	mediaStore, err := storage.NewLocalStorage(mediaDir, mediaBaseURL)
+	if err != nil {
+		log.Fatal("Failed to initialize media storage:", err)
+	}
+	imageProcessor := media.NewImageProcessor(mediaStore, media.DefaultImageConfig())
+
 	// Initialize repositories
 	menuRepo := menu.NewMenuRepository(db)
 	orderRepo := order.NewOrderRepository(db)
 	sessionRepo := session.NewPostgresRepository(db)
+	reservationRepo := reservation.NewPostgresRepository(db)
+	waitlistRepo := waitlist.NewPostgresRepository(db)
+	staffRepo := staff.NewPostgresRepository(db)
+	authRepo := auth.NewPostgresRepository(db)
+	apiKeyRepo := apikey.NewPostgresRepository(db)
+
+	// Staff and guest tokens, revoked through the auth repository, and API keys for integrations
+	apiKeySvc := apikey.NewService(apiKeyRepo)
+	authn := middleware.NewAuthenticator(authConfig(), authRepo, apiKeySvc)
 
 	// Initialize services with proper dependency injection
-	menuSvc := menu.NewMenuService(menuRepo)
-	sessionSvc := session.NewService(sessionRepo)
+	// Menu and session reads are cached; every write through the services invalidates what it touched
+	// Caches are per replica and only invalidated locally, so TTLs bound how stale other replicas can be
+	menuCache := cache.NewCache[string, any](cacheConfig("MENU_CACHE", 30*time.Second, 10000))
+	sessionCache := cache.NewCache[string, any](cacheConfig("SESSION_CACHE", time.Minute, 5000))
+	shutdownMgr.RegisterHook(func(ctx context.Context) error {
+		log.Println("Stopping caches...")
+		return errors.Join(menuCache.Stop(ctx), sessionCache.Stop(ctx))
+	})
+
+	menuSvc := menu.NewCachedService(menu.NewMenuService(menuRepo, imageProcessor, os.Getenv("DEFAULT_LOCALE")), menuCache)
+	sessionSvc := session.NewCachedService(session.NewService(sessionRepo, sessionConfig(), authn), sessionCache)
 	orderSvc := order.NewOrderService(orderRepo, menuSvc, sessionSvc) // Inject menuService for validation and sessionService for session validation
+	reservationSvc := reservation.NewService(reservationRepo, sessionSvc, reservation.DefaultConfig())
+	waitlistSvc := waitlist.NewService(waitlistRepo, sessionSvc, waitlist.DefaultConfig())
+	staffSvc := staff.NewService(staffRepo, sessionSvc, orderSvc)
+	authSvc := auth.NewService(authRepo, authn)
+
+	// Bootstrap a manager account on a fresh deployment
+	if username, password := os.Getenv("ADMIN_USERNAME"), os.Getenv("ADMIN_PASSWORD"); username != "" && password != "" {
+		if isProduction() && placeholderSecrets[password] {
+			log.Fatal("ADMIN_PASSWORD is still the example value from .env; set a real password or unset it")
+		}
+		if err := authSvc.EnsureAdmin(context.Background(), username, password); err != nil {
+			log.Fatal("Failed to create admin account:", err)
+		}
+	}
+
+	// Apply scheduled menu price changes in the background
+	priceScheduler := menu.NewPriceScheduler(menuSvc, envDuration("PRICE_SCHEDULER_INTERVAL", time.Minute))
+	priceScheduler.Start()
+	shutdownMgr.RegisterHook(func(ctx context.Context) error {
+		log.Println("Stopping price scheduler...")
+		return priceScheduler.Stop(ctx)
+	})
+
+	// Alert on and cancel sessions left active or pending by mistake
+	sessionSweeper := session.NewSweeper(sessionSvc, sweeperConfig(), session.LogAlerter{})
+	sessionSweeper.Start()
+	shutdownMgr.RegisterHook(func(ctx context.Context) error {
+		log.Println("Stopping session sweeper...")
+		return sessionSweeper.Stop(ctx)
+	})
+
+	// Rate limit policies; handlers attach them to their routes. Replicas share buckets with RATE_LIMIT_STORE=postgres.
+	rateLimiter := middleware.NewRateLimiter(rateLimitConfig(), rateLimitStore(db))
+	shutdownMgr.RegisterHook(func(ctx context.Context) error {
+		log.Println("Stopping rate limiter...")
+		rateLimiter.Stop()
+		return nil
+	})
+
+	// Idempotency-Key records, kept in PostgreSQL so a retry reaching another replica is still recognised
+	idempotency := middleware.NewIdempotency(idempotencyConfig(), middleware.NewPostgresIdempotencyStore(db))
+	shutdownMgr.RegisterHook(func(ctx context.Context) error {
+		log.Println("Stopping idempotency key cleanup...")
+		return idempotency.Stop(ctx)
+	})
 
 	// Initialize handlers
-	menuHnd := menu.NewMenuHandler(menuSvc)
-	orderHnd := order.NewOrderHandler(orderSvc)
-	sessionHnd := session.NewHandler(sessionSvc)
+	menuHnd := menu.NewMenuHandler(menuSvc, rateLimiter)
+	orderHnd := order.NewOrderHandler(orderSvc, idempotency)
+	sessionHnd := session.NewHandler(sessionSvc, rateLimiter)
+	reservationHnd := reservation.NewHandler(reservationSvc, rateLimiter)
+	waitlistHnd := waitlist.NewHandler(waitlistSvc)
+	staffHnd := staff.NewHandler(staffSvc)
+	authHnd := auth.NewHandler(authSvc, rateLimiter)
+	apiKeyHnd := apikey.NewHandler(apiKeySvc)
+	cacheHnd := cache.NewHandler(map[string]*cache.Cache[string, any]{"menu": menuCache, "session": sessionCache})
 
 	// Setup Gin router
 	router := gin.Default()
@@ -134,12 +233,16 @@ func main() {
 	// 5. REQUEST SIZE LIMIT - Prevent DOS attacks (check size before processing)
 	router.Use(middleware.RequestSizeLimitMiddleware(1024 * 1024)) // 1MB limit
 
-	// 6. RATE LIMITING - Final security check before handlers (prevent abuse)
-	middleware.InitRateLimiter(middleware.RateLimitConfig{
-		RequestsPerSecond: 100,
-		BurstSize:         200,
-	})
-	router.Use(middleware.RateLimitMiddleware())
+	// 6. AUTHENTICATION - Resolve the staff member, guest or API key behind the request; routes enforce access themselves
+	router.Use(authn.Authenticate())
+
+	// 7. RATE LIMITING - Final security check before handlers (prevent abuse); applies the policy attached to the
+	// matched route, keyed by IP or by the caller from step 6
+	router.Use(rateLimiter.Middleware())
+
+	// 8. IDEMPOTENCY - Replay the stored response of a retried POST that carries an Idempotency-Key, scoped to the
+	// caller from step 6; comes after rate limiting so replays still count against the limits
+	router.Use(idempotency.Middleware())
 
 	// API Documentation endpoint - Swagger UI
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
@@ -149,10 +252,19 @@ func main() {
 		c.Redirect(http.StatusMovedPermanently, "/swagger/index.html")
 	})
 
+	// Serve uploaded media from local storage
+	router.Static("/media", mediaStore.BaseDir())
+
 	// Register routes from each handler
 	menuHnd.RegisterRoutes(router)
 	orderHnd.RegisterRoutes(router)
 	sessionHnd.RegisterRoutes(router)
+	reservationHnd.RegisterRoutes(router)
+	waitlistHnd.RegisterRoutes(router)
+	staffHnd.RegisterRoutes(router)
+	authHnd.RegisterRoutes(router)
+	apiKeyHnd.RegisterRoutes(router)
+	cacheHnd.RegisterRoutes(router)
 
 	// Create HTTP server with graceful shutdown support
 	server := &http.Server{
@@ -179,3 +291,157 @@ func main() {
 	<-shutdownMgr.Done()
 	log.Println("Application shutdown complete")
 }
+
+// sessionConfig reads table QR code settings from the environment
+func sessionConfig() session.Config {
+	config := session.DefaultConfig()
+	config.QRBaseURL = os.Getenv("QR_BASE_URL")
+	checkSecret("QR_TOKEN_SECRET")
+	if secret := os.Getenv("QR_TOKEN_SECRET"); secret != "" {
+		config.TokenSecret = []byte(secret)
+	} else {
+		log.Println("Warning: QR_TOKEN_SECRET is not set, table QR codes will stop working on restart")
+	}
+	return config
+}
+
+// authConfig reads token signing settings from the environment
+func authConfig() middleware.AuthConfig {
+	config := middleware.DefaultAuthConfig()
+	checkSecret("JWT_SECRET")
+	if secret := os.Getenv("JWT_SECRET"); secret != "" {
+		config.Secret = []byte(secret)
+	} else {
+		log.Println("Warning: JWT_SECRET is not set, staff and guest tokens will stop working on restart")
+	}
+	config.AccessTTL = envDuration("JWT_ACCESS_TTL", config.AccessTTL)
+	config.RefreshTTL = envDuration("JWT_REFRESH_TTL", config.RefreshTTL)
+	config.GuestTTL = envDuration("GUEST_CREDENTIAL_TTL", config.GuestTTL)
+	return config
+}
+
+// minSecretBytes is the shortest signing secret accepted in production
+const minSecretBytes = 32
+
+// placeholderSecrets are the example values shipped in .env, which anyone can read
+var placeholderSecrets = map[string]bool{
+	"change-me-in-production": true,
+	"change-me-now":           true,
+}
+
+// isProduction reports whether APP_ENV=production, where weak secrets stop the server from starting
+func isProduction() bool {
+	return strings.EqualFold(os.Getenv("APP_ENV"), "production")
+}
+
+// checkSecret stops a production start if the signing secret in key is unset, an example value or shorter than
+// minSecretBytes, since tokens signed with it could be forged
+func checkSecret(key string) {
+	if !isProduction() {
+		return
+	}
+	secret := os.Getenv(key)
+	if placeholderSecrets[secret] {
+		log.Fatalf("%s is still the example value from .env; set a random secret of at least %d bytes", key, minSecretBytes)
+	}
+	if len(secret) < minSecretBytes {
+		log.Fatalf("%s must be at least %d bytes in production", key, minSecretBytes)
+	}
+}
+
+// sweeperConfig reads idle session thresholds from the environment
+func sweeperConfig() session.SweeperConfig {
+	config := session.DefaultSweeperConfig()
+	config.Interval = envDuration("SESSION_SWEEP_INTERVAL", config.Interval)
+	for status, prefix := range map[session.SessionStatus]string{
+		session.StatusActive:  "SESSION_ACTIVE",
+		session.StatusPending: "SESSION_PENDING",
+	} {
+		timeout := config.Timeouts[status]
+		timeout.WarnAfter = envDuration(prefix+"_WARN_AFTER", timeout.WarnAfter)
+		timeout.CancelAfter = envDuration(prefix+"_CANCEL_AFTER", timeout.CancelAfter)
+		config.Timeouts[status] = timeout
+	}
+	return config
+}
+
+// rateLimitConfig reads rate limit policies such as RATE_LIMIT_GUEST_ENTRY=120/1m from the environment
+func rateLimitConfig() middleware.RateLimitConfig {
+	config := middleware.DefaultRateLimitConfig()
+	for name, policy := range config.Policies {
+		key := "RATE_LIMIT_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+		v := os.Getenv(key)
+		if v == "" {
+			continue
+		}
+		limit, period, err := middleware.ParseRateLimit(v)
+		if err != nil {
+			log.Printf("Warning: invalid %s: %v, using %d/%s", key, err, policy.Limit, policy.Period)
+			continue
+		}
+		policy.Limit, policy.Period = limit, period
+		policy.Burst = envInt(key+"_BURST", policy.Burst)
+		config.Policies[name] = policy
+	}
+	return config
+}
+
+// idempotencyConfig reads how long Idempotency-Key records are kept from the environment
+func idempotencyConfig() middleware.IdempotencyConfig {
+	config := middleware.DefaultIdempotencyConfig()
+	config.TTL = envDuration("IDEMPOTENCY_KEY_TTL", config.TTL)
+	config.CleanupInterval = envDuration("IDEMPOTENCY_CLEANUP_INTERVAL", config.CleanupInterval)
+	return config
+}
+
+// rateLimitStore picks where rate limit buckets are kept from RATE_LIMIT_STORE: "memory" (the default) or
+// "postgres", shared by every replica using the database
+func rateLimitStore(db *sql.DB) middleware.RateLimitStore {
+	switch store := os.Getenv("RATE_LIMIT_STORE"); store {
+	case "", "memory":
+		return middleware.NewMemoryRateLimitStore()
+	case "postgres":
+		return middleware.NewPostgresRateLimitStore(db)
+	default:
+		log.Printf("Warning: unknown RATE_LIMIT_STORE %q, keeping rate limits in memory", store)
+		return middleware.NewMemoryRateLimitStore()
+	}
+}
+
+// cacheConfig reads the TTL, entry limit and byte budget of a service cache from <prefix>_TTL,
+// <prefix>_MAX_ENTRIES and <prefix>_MAX_BYTES
+func cacheConfig(prefix string, ttl time.Duration, maxEntries int) cache.Config[any] {
+	return cache.Config[any]{
+		TTL:        envDuration(prefix+"_TTL", ttl),
+		MaxEntries: envInt(prefix+"_MAX_ENTRIES", maxEntries),
+		MaxBytes:   int64(envInt(prefix+"_MAX_BYTES", 0)),
+	}
+}
+
+// envDuration reads a duration such as "90m" from the environment, keeping the fallback if unset or invalid
+func envDuration(key string, fallback time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		log.Printf("Warning: invalid %s %q, using %s", key, v, fallback)
+		return fallback
+	}
+	return d
+}
+
+// envInt reads a positive integer from the environment, keeping the fallback if unset or invalid
+func envInt(key string, fallback int) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 {
+		log.Printf("Warning: invalid %s %q, using %d", key, v, fallback)
+		return fallback
+	}
+	return n
+}
@@ -0,0 +1,262 @@
+package apikey
+
+import (
+	"restaurant/internal/errors"
+	"restaurant/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Handler handles HTTP requests for API keys
+type Handler struct {
+	svc APIKeyService
+}
+
+// NewHandler creates a new API key handler
+func NewHandler(svc APIKeyService) *Handler {
+	return &Handler{svc: svc}
+}
+
+// apiKeyPermissions declares what each API key route needs
+var apiKeyPermissions = middleware.RoutePermissions{
+	"GET /api-keys":             middleware.PermAPIKeysManage,
+	"POST /api-keys":            middleware.PermAPIKeysManage,
+	"GET /api-keys/:id":         middleware.PermAPIKeysManage,
+	"PUT /api-keys/:id":         middleware.PermAPIKeysManage,
+	"POST /api-keys/:id/rotate": middleware.PermAPIKeysManage,
+	"DELETE /api-keys/:id":      middleware.PermAPIKeysManage,
+}
+
+// RegisterRoutes registers all API key routes with the Gin router
+func (h *Handler) RegisterRoutes(router *gin.Engine) {
+	keyGroup := router.Group("/api-keys", apiKeyPermissions.Authorize())
+	{
+		keyGroup.GET("", h.ListKeys)
+		keyGroup.POST("", h.CreateKey)
+		keyGroup.GET("/:id", h.GetKey)
+		keyGroup.PUT("/:id", h.UpdateKey)
+		keyGroup.POST("/:id/rotate", h.RotateKey)
+		keyGroup.DELETE("/:id", h.RevokeKey)
+	}
+}
+
+// CreateKey handles POST /api-keys
+// @Summary Issue an API key
+// @Description Create a key for a machine integration, sent in the X-API-Key header. The key is only returned now.
+// @Tags API Keys
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateAPIKeyRequest true "Name, scopes, optional quota and expiry"
+// @Success 201 {object} IssuedKey
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /api-keys [post]
+func (h *Handler) CreateKey(c *gin.Context) {
+	var req CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	if err := ValidateCreateAPIKey(req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	key, err := h.svc.CreateKey(c.Request.Context(), issuer(c), &req)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.JSON(201, key)
+}
+
+// ListKeys handles GET /api-keys
+// @Summary List API keys
+// @Description List API keys, newest first, with their scopes, quotas and when they were last used
+// @Tags API Keys
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param include_revoked query bool false "Include revoked keys"
+// @Success 200 {array} APIKey
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /api-keys [get]
+func (h *Handler) ListKeys(c *gin.Context) {
+	var req ListAPIKeysRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	keys, err := h.svc.ListKeys(c.Request.Context(), req.IncludeRevoked)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.JSON(200, keys)
+}
+
+// GetKey handles GET /api-keys/:id
+// @Summary Get API key by ID
+// @Description Retrieve an API key; the key itself is never returned again
+// @Tags API Keys
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "API key ID (UUID)"
+// @Success 200 {object} APIKey
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /api-keys/{id} [get]
+func (h *Handler) GetKey(c *gin.Context) {
+	id, ok := middleware.UUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	key, err := h.svc.GetKey(c.Request.Context(), id)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.JSON(200, key)
+}
+
+// UpdateKey handles PUT /api-keys/:id
+// @Summary Update an API key
+// @Description Rename a key or change its scopes, quota or expiry; omitted fields are kept, and a quota of 0 returns to the default
+// @Tags API Keys
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "API key ID (UUID)"
+// @Param request body UpdateAPIKeyRequest true "Fields to change"
+// @Success 200 {object} APIKey
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /api-keys/{id} [put]
+func (h *Handler) UpdateKey(c *gin.Context) {
+	id, ok := middleware.UUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req UpdateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	if err := ValidateUpdateAPIKey(req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	key, err := h.svc.UpdateKey(c.Request.Context(), id, &req)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.JSON(200, key)
+}
+
+// RotateKey handles POST /api-keys/:id/rotate
+// @Summary Rotate an API key
+// @Description Issue a replacement with the same scopes, quota and expiry; the old key keeps working for grace_minutes, then expires
+// @Tags API Keys
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "API key ID (UUID)"
+// @Param request body RotateAPIKeyRequest false "Grace period for the old key"
+// @Success 201 {object} IssuedKey
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 409 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /api-keys/{id}/rotate [post]
+func (h *Handler) RotateKey(c *gin.Context) {
+	id, ok := middleware.UUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req RotateAPIKeyRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			middleware.HandleError(c, errors.NewValidationError(err.Error()))
+			return
+		}
+	}
+
+	if err := ValidateRotateAPIKey(req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	key, err := h.svc.RotateKey(c.Request.Context(), id, issuer(c), &req)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.JSON(201, key)
+}
+
+// RevokeKey handles DELETE /api-keys/:id
+// @Summary Revoke an API key
+// @Description Stop accepting a key immediately; it stays listed with include_revoked
+// @Tags API Keys
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "API key ID (UUID)"
+// @Success 204 "No Content"
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /api-keys/{id} [delete]
+func (h *Handler) RevokeKey(c *gin.Context) {
+	id, ok := middleware.UUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.svc.RevokeKey(c.Request.Context(), id); err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.Status(204)
+}
+
+// issuer returns the staff member making the request, recorded as the creator of new keys
+func issuer(c *gin.Context) *uuid.UUID {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok || !principal.IsStaff() {
+		return nil
+	}
+	return &principal.StaffID
+}
@@ -0,0 +1,37 @@
+package apikey
+
+import (
+	"time"
+
+	"restaurant/internal/middleware"
+
+	"github.com/google/uuid"
+)
+
+// APIKey is a long-lived credential for a machine integration, such as a POS bridge or delivery aggregator
+type APIKey struct {
+	ID                uuid.UUID               `json:"id"`
+	Name              string                  `json:"name"`                // what the key is for
+	Prefix            string                  `json:"prefix"`              // start of the key, to tell keys apart without revealing them
+	Scopes            []middleware.Permission `json:"scopes"`              // permissions the key is granted
+	RequestsPerSecond *int                    `json:"requests_per_second"` // the key's own rate limit; nil uses the default
+	BurstSize         *int                    `json:"burst_size"`          // the key's own burst; nil is twice its rate
+	ExpiresAt         *time.Time              `json:"expires_at"`          // nil never expires
+	LastUsedAt        *time.Time              `json:"last_used_at"`        // updated at most once a minute
+	RevokedAt         *time.Time              `json:"revoked_at"`
+	RotatedFrom       *uuid.UUID              `json:"rotated_from"` // key this one replaced
+	CreatedBy         *uuid.UUID              `json:"created_by"`   // staff member who issued the key
+	CreatedAt         time.Time               `json:"created_at"`
+	KeyHash           string                  `json:"-"` // hex SHA-256 of the full key
+}
+
+// Live reports whether the key can still be used
+func (k *APIKey) Live(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+// IssuedKey is returned when a key is created or rotated. The plaintext key is only ever shown then.
+type IssuedKey struct {
+	*APIKey
+	Key string `json:"key"`
+}
@@ -0,0 +1,250 @@
+package apikey
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"restaurant/internal/errors"
+	"restaurant/internal/middleware"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// apiKeyColumns is the column list scanned by scanAPIKey
+const apiKeyColumns = "id, name, prefix, key_hash, scopes, requests_per_second, burst_size, expires_at, last_used_at, revoked_at, rotated_from, created_by, created_at"
+
+// Repository defines methods for API key database operations
+type Repository interface {
+	// CreateKey inserts a new API key
+	CreateKey(ctx context.Context, key *APIKey) error
+
+	// GetKey retrieves an API key by ID
+	GetKey(ctx context.Context, id uuid.UUID) (*APIKey, error)
+
+	// GetKeyByPrefix retrieves an API key by the prefix of the key
+	GetKeyByPrefix(ctx context.Context, prefix string) (*APIKey, error)
+
+	// ListKeys lists API keys, newest first, optionally including revoked ones
+	ListKeys(ctx context.Context, includeRevoked bool) ([]*APIKey, error)
+
+	// UpdateKey rewrites the name, scopes, quota and expiry of an API key
+	UpdateKey(ctx context.Context, key *APIKey) error
+
+	// RotateKey inserts the replacement of a live key and makes the old key expire at graceEnd, unless it
+	// expires sooner
+	RotateKey(ctx context.Context, replacement *APIKey, graceEnd time.Time) error
+
+	// RevokeKey revokes an API key; revoking a revoked key is a no-op
+	RevokeKey(ctx context.Context, id uuid.UUID) error
+
+	// TouchKey records that a key was used, at most once a minute
+	TouchKey(ctx context.Context, id uuid.UUID) error
+}
+
+// postgresRepository implements Repository using PostgreSQL
+type postgresRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresRepository creates a new PostgreSQL-based API key repository
+func NewPostgresRepository(db *sql.DB) Repository {
+	return &postgresRepository{db: db}
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanAPIKey(row rowScanner) (*APIKey, error) {
+	var k APIKey
+	var scopes []string
+	var requestsPerSecond, burstSize sql.NullInt64
+	var expiresAt, lastUsedAt, revokedAt sql.NullTime
+	var rotatedFrom, createdBy sql.NullString
+	if err := row.Scan(&k.ID, &k.Name, &k.Prefix, &k.KeyHash, pq.Array(&scopes), &requestsPerSecond, &burstSize,
+		&expiresAt, &lastUsedAt, &revokedAt, &rotatedFrom, &createdBy, &k.CreatedAt); err != nil {
+		return nil, err
+	}
+	for _, scope := range scopes {
+		k.Scopes = append(k.Scopes, middleware.Permission(scope))
+	}
+	if requestsPerSecond.Valid {
+		value := int(requestsPerSecond.Int64)
+		k.RequestsPerSecond = &value
+	}
+	if burstSize.Valid {
+		value := int(burstSize.Int64)
+		k.BurstSize = &value
+	}
+	if expiresAt.Valid {
+		k.ExpiresAt = &expiresAt.Time
+	}
+	if lastUsedAt.Valid {
+		k.LastUsedAt = &lastUsedAt.Time
+	}
+	if revokedAt.Valid {
+		k.RevokedAt = &revokedAt.Time
+	}
+	if rotatedFrom.Valid {
+		if id, err := uuid.Parse(rotatedFrom.String); err == nil {
+			k.RotatedFrom = &id
+		}
+	}
+	if createdBy.Valid {
+		if id, err := uuid.Parse(createdBy.String); err == nil {
+			k.CreatedBy = &id
+		}
+	}
+	return &k, nil
+}
+
+// scopeStrings converts scopes for storage in a TEXT[] column
+func scopeStrings(scopes []middleware.Permission) []string {
+	values := make([]string, len(scopes))
+	for i, scope := range scopes {
+		values[i] = string(scope)
+	}
+	return values
+}
+
+// insertKey inserts an API key with any executor
+func insertKey(ctx context.Context, exec interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}, key *APIKey) error {
+	_, err := exec.ExecContext(ctx, "INSERT INTO api_keys ("+apiKeyColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)",
+		key.ID, key.Name, key.Prefix, key.KeyHash, pq.Array(scopeStrings(key.Scopes)), key.RequestsPerSecond, key.BurstSize,
+		key.ExpiresAt, key.LastUsedAt, key.RevokedAt, key.RotatedFrom, key.CreatedBy, key.CreatedAt)
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+		return errors.ErrStaffNotFound
+	}
+	return err
+}
+
+// CreateKey inserts a new API key
+func (r *postgresRepository) CreateKey(ctx context.Context, key *APIKey) error {
+	return insertKey(ctx, r.db, key)
+}
+
+// GetKey retrieves an API key by ID
+func (r *postgresRepository) GetKey(ctx context.Context, id uuid.UUID) (*APIKey, error) {
+	key, err := scanAPIKey(r.db.QueryRowContext(ctx, "SELECT "+apiKeyColumns+" FROM api_keys WHERE id = $1", id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ErrAPIKeyNotFound
+		}
+		return nil, errors.NewInternalError("failed to get API key", err)
+	}
+	return key, nil
+}
+
+// GetKeyByPrefix retrieves an API key by the prefix of the key
+func (r *postgresRepository) GetKeyByPrefix(ctx context.Context, prefix string) (*APIKey, error) {
+	key, err := scanAPIKey(r.db.QueryRowContext(ctx, "SELECT "+apiKeyColumns+" FROM api_keys WHERE prefix = $1", prefix))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ErrAPIKeyNotFound
+		}
+		return nil, errors.NewInternalError("failed to get API key", err)
+	}
+	return key, nil
+}
+
+// ListKeys lists API keys, newest first, optionally including revoked ones
+func (r *postgresRepository) ListKeys(ctx context.Context, includeRevoked bool) ([]*APIKey, error) {
+	query := "SELECT " + apiKeyColumns + " FROM api_keys"
+	if !includeRevoked {
+		query += " WHERE revoked_at IS NULL"
+	}
+	rows, err := r.db.QueryContext(ctx, query+" ORDER BY created_at DESC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []*APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+// UpdateKey rewrites the name, scopes, quota and expiry of an API key
+func (r *postgresRepository) UpdateKey(ctx context.Context, key *APIKey) error {
+	result, err := r.db.ExecContext(ctx, "UPDATE api_keys SET name = $1, scopes = $2, requests_per_second = $3, burst_size = $4, expires_at = $5 WHERE id = $6",
+		key.Name, pq.Array(scopeStrings(key.Scopes)), key.RequestsPerSecond, key.BurstSize, key.ExpiresAt, key.ID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+// RotateKey inserts the replacement of a live key and makes the old key expire at graceEnd, unless it
+// expires sooner
+func (r *postgresRepository) RotateKey(ctx context.Context, replacement *APIKey, graceEnd time.Time) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.WrapError(500, "failed to begin transaction", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `UPDATE api_keys SET expires_at = LEAST(COALESCE(expires_at, $2), $2)
+		WHERE id = $1 AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > NOW())`,
+		replacement.RotatedFrom, graceEnd)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		var exists bool
+		if err := tx.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM api_keys WHERE id = $1)", replacement.RotatedFrom).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			return errors.ErrAPIKeyNotFound
+		}
+		return errors.NewConflictError("only live API keys can be rotated")
+	}
+
+	if err := insertKey(ctx, tx, replacement); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// RevokeKey revokes an API key; revoking a revoked key is a no-op
+func (r *postgresRepository) RevokeKey(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, "UPDATE api_keys SET revoked_at = COALESCE(revoked_at, NOW()) WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+// TouchKey records that a key was used, at most once a minute
+func (r *postgresRepository) TouchKey(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, "UPDATE api_keys SET last_used_at = NOW() WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute')", id)
+	return err
+}
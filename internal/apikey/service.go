@@ -0,0 +1,227 @@
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	apperrors "restaurant/internal/errors"
+	"restaurant/internal/middleware"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// keyPrefix starts every API key, so leaked keys are easy to recognise and scan for
+const keyPrefix = "rk_"
+
+// APIKeyService defines business logic for API keys. It implements middleware.APIKeyVerifier.
+type APIKeyService interface {
+	CreateKey(ctx context.Context, createdBy *uuid.UUID, req *CreateAPIKeyRequest) (*IssuedKey, error)
+	GetKey(ctx context.Context, id uuid.UUID) (*APIKey, error)
+	ListKeys(ctx context.Context, includeRevoked bool) ([]*APIKey, error)
+	UpdateKey(ctx context.Context, id uuid.UUID, req *UpdateAPIKeyRequest) (*APIKey, error)
+	RotateKey(ctx context.Context, id uuid.UUID, createdBy *uuid.UUID, req *RotateAPIKeyRequest) (*IssuedKey, error)
+	RevokeKey(ctx context.Context, id uuid.UUID) error
+
+	// VerifyAPIKey resolves a key presented by an integration
+	VerifyAPIKey(ctx context.Context, key string) (*middleware.Principal, error)
+}
+
+// apiKeyService implements APIKeyService
+type apiKeyService struct {
+	repo Repository
+}
+
+// NewService creates a new API key service
+func NewService(repo Repository) APIKeyService {
+	return &apiKeyService{repo: repo}
+}
+
+// CreateKey issues a new API key
+func (s *apiKeyService) CreateKey(ctx context.Context, createdBy *uuid.UUID, req *CreateAPIKeyRequest) (*IssuedKey, error) {
+	// Shape validation (name, scopes, quota, expiry) already done by handler
+	key := &APIKey{
+		ID:                uuid.New(),
+		Name:              strings.TrimSpace(req.Name),
+		Scopes:            req.Scopes,
+		RequestsPerSecond: req.RequestsPerSecond,
+		BurstSize:         req.BurstSize,
+		ExpiresAt:         req.ExpiresAt,
+		CreatedBy:         createdBy,
+		CreatedAt:         time.Now(),
+	}
+	plaintext, err := generateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateKey(ctx, key); err != nil {
+		return nil, apperrors.WrapError(500, "failed to create API key", err)
+	}
+	return &IssuedKey{APIKey: key, Key: plaintext}, nil
+}
+
+// GetKey retrieves an API key
+func (s *apiKeyService) GetKey(ctx context.Context, id uuid.UUID) (*APIKey, error) {
+	key, err := s.repo.GetKey(ctx, id)
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to get API key", err)
+	}
+	return key, nil
+}
+
+// ListKeys lists API keys, newest first
+func (s *apiKeyService) ListKeys(ctx context.Context, includeRevoked bool) ([]*APIKey, error) {
+	keys, err := s.repo.ListKeys(ctx, includeRevoked)
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to list API keys", err)
+	}
+	return keys, nil
+}
+
+// UpdateKey renames an API key or changes its scopes, quota or expiry; omitted fields are kept
+func (s *apiKeyService) UpdateKey(ctx context.Context, id uuid.UUID, req *UpdateAPIKeyRequest) (*APIKey, error) {
+	key, err := s.repo.GetKey(ctx, id)
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to get API key", err)
+	}
+	if req.Name != "" {
+		key.Name = strings.TrimSpace(req.Name)
+	}
+	if len(req.Scopes) > 0 {
+		key.Scopes = req.Scopes
+	}
+	if req.RequestsPerSecond != nil {
+		key.RequestsPerSecond = positiveOrNil(*req.RequestsPerSecond)
+	}
+	if req.BurstSize != nil {
+		key.BurstSize = positiveOrNil(*req.BurstSize)
+	}
+	if req.ExpiresAt != nil {
+		key.ExpiresAt = req.ExpiresAt
+	}
+	if err := s.repo.UpdateKey(ctx, key); err != nil {
+		return nil, apperrors.WrapError(500, "failed to update API key", err)
+	}
+	return key, nil
+}
+
+// RotateKey replaces a live API key with a new one with the same name, scopes, quota and expiry. The old key
+// keeps working for the grace period, then expires.
+func (s *apiKeyService) RotateKey(ctx context.Context, id uuid.UUID, createdBy *uuid.UUID, req *RotateAPIKeyRequest) (*IssuedKey, error) {
+	old, err := s.repo.GetKey(ctx, id)
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to get API key", err)
+	}
+	now := time.Now()
+	replacement := &APIKey{
+		ID:                uuid.New(),
+		Name:              old.Name,
+		Scopes:            old.Scopes,
+		RequestsPerSecond: old.RequestsPerSecond,
+		BurstSize:         old.BurstSize,
+		ExpiresAt:         old.ExpiresAt,
+		RotatedFrom:       &old.ID,
+		CreatedBy:         createdBy,
+		CreatedAt:         now,
+	}
+	plaintext, err := generateKey(replacement)
+	if err != nil {
+		return nil, err
+	}
+	graceEnd := now.Add(time.Duration(req.GraceMinutes) * time.Minute)
+	if err := s.repo.RotateKey(ctx, replacement, graceEnd); err != nil {
+		return nil, apperrors.WrapError(500, "failed to rotate API key", err)
+	}
+	return &IssuedKey{APIKey: replacement, Key: plaintext}, nil
+}
+
+// RevokeKey revokes an API key immediately
+func (s *apiKeyService) RevokeKey(ctx context.Context, id uuid.UUID) error {
+	if err := s.repo.RevokeKey(ctx, id); err != nil {
+		return apperrors.WrapError(500, "failed to revoke API key", err)
+	}
+	return nil
+}
+
+// VerifyAPIKey resolves a key presented by an integration, recording that it was used
+func (s *apiKeyService) VerifyAPIKey(ctx context.Context, plaintext string) (*middleware.Principal, error) {
+	prefix, ok := parsePrefix(plaintext)
+	if !ok {
+		return nil, apperrors.ErrInvalidAPIKey
+	}
+	key, err := s.repo.GetKeyByPrefix(ctx, prefix)
+	if err != nil {
+		if err == apperrors.ErrAPIKeyNotFound {
+			return nil, apperrors.ErrInvalidAPIKey
+		}
+		return nil, apperrors.WrapError(500, "failed to verify API key", err)
+	}
+	if subtle.ConstantTimeCompare([]byte(hashKey(plaintext)), []byte(key.KeyHash)) != 1 || !key.Live(time.Now()) {
+		return nil, apperrors.ErrInvalidAPIKey
+	}
+	if err := s.repo.TouchKey(ctx, key.ID); err != nil {
+		return nil, apperrors.WrapError(500, "failed to record API key use", err)
+	}
+
+	principal := &middleware.Principal{
+		Kind:     middleware.PrincipalAPIKey,
+		TokenID:  key.ID.String(),
+		APIKeyID: key.ID,
+		Scopes:   key.Scopes,
+	}
+	if key.ExpiresAt != nil {
+		principal.ExpiresAt = *key.ExpiresAt
+	}
+	if key.RequestsPerSecond != nil || key.BurstSize != nil {
+		principal.Quota = &middleware.Quota{}
+		if key.RequestsPerSecond != nil {
+			principal.Quota.RequestsPerSecond = *key.RequestsPerSecond
+		}
+		if key.BurstSize != nil {
+			principal.Quota.BurstSize = *key.BurstSize
+		}
+	}
+	return principal, nil
+}
+
+// generateKey creates a random key of the form rk_<prefix>_<secret>, setting the prefix and hash of an API key
+func generateKey(key *APIKey) (string, error) {
+	random := make([]byte, 4+32)
+	if _, err := rand.Read(random); err != nil {
+		return "", apperrors.NewInternalError("failed to generate API key", err)
+	}
+	key.Prefix = keyPrefix + hex.EncodeToString(random[:4])
+	plaintext := key.Prefix + "_" + base64.RawURLEncoding.EncodeToString(random[4:])
+	key.KeyHash = hashKey(plaintext)
+	return plaintext, nil
+}
+
+// parsePrefix returns the prefix a key is looked up by
+func parsePrefix(plaintext string) (string, bool) {
+	if !strings.HasPrefix(plaintext, keyPrefix) {
+		return "", false
+	}
+	end := strings.IndexByte(plaintext[len(keyPrefix):], '_')
+	if end <= 0 {
+		return "", false
+	}
+	return plaintext[:len(keyPrefix)+end], true
+}
+
+// hashKey returns the hex SHA-256 of a key. Keys carry 256 random bits, so a fast hash is enough.
+func hashKey(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
+
+// positiveOrNil turns a quota of 0 back into "use the default"
+func positiveOrNil(value int) *int {
+	if value <= 0 {
+		return nil
+	}
+	return &value
+}
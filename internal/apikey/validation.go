@@ -0,0 +1,82 @@
+package apikey
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"restaurant/internal/middleware"
+)
+
+// CreateAPIKeyRequest represents the request to issue an API key
+type CreateAPIKeyRequest struct {
+	Name              string                  `json:"name" validate:"required,min=1,max=100"`
+	Scopes            []middleware.Permission `json:"scopes" validate:"required,min=1,dive,required"`
+	RequestsPerSecond *int                    `json:"requests_per_second" validate:"omitempty,min=1,max=10000"`
+	BurstSize         *int                    `json:"burst_size" validate:"omitempty,min=1,max=20000"`
+	ExpiresAt         *time.Time              `json:"expires_at"`
+}
+
+// UpdateAPIKeyRequest represents a partial change to an API key; omitted fields are kept, and a rate limit or
+// burst of 0 returns the key to the default
+type UpdateAPIKeyRequest struct {
+	Name              string                  `json:"name" validate:"omitempty,min=1,max=100"`
+	Scopes            []middleware.Permission `json:"scopes" validate:"omitempty,min=1,dive,required"`
+	RequestsPerSecond *int                    `json:"requests_per_second" validate:"omitempty,min=0,max=10000"`
+	BurstSize         *int                    `json:"burst_size" validate:"omitempty,min=0,max=20000"`
+	ExpiresAt         *time.Time              `json:"expires_at"`
+}
+
+// RotateAPIKeyRequest represents the request to replace an API key with a new one. The old key keeps working
+// for the grace period so the integration can be switched over.
+type RotateAPIKeyRequest struct {
+	GraceMinutes int `json:"grace_minutes" validate:"min=0,max=10080"`
+}
+
+// ListAPIKeysRequest represents the API key list query
+type ListAPIKeysRequest struct {
+	IncludeRevoked bool `form:"include_revoked"`
+}
+
+// ValidateCreateAPIKey validates the create API key request
+func ValidateCreateAPIKey(req CreateAPIKeyRequest) error {
+	if err := ValidateStruct(req); err != nil {
+		return err
+	}
+	if err := validateScopes(req.Scopes); err != nil {
+		return err
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return errors.New("expires_at must be in the future")
+	}
+	return nil
+}
+
+// ValidateUpdateAPIKey validates the update API key request
+func ValidateUpdateAPIKey(req UpdateAPIKeyRequest) error {
+	if err := ValidateStruct(req); err != nil {
+		return err
+	}
+	if err := validateScopes(req.Scopes); err != nil {
+		return err
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return errors.New("expires_at must be in the future")
+	}
+	return nil
+}
+
+// ValidateRotateAPIKey validates the rotate API key request
+func ValidateRotateAPIKey(req RotateAPIKeyRequest) error {
+	return ValidateStruct(req)
+}
+
+// validateScopes checks that every scope is a permission API keys may be granted
+func validateScopes(scopes []middleware.Permission) error {
+	for _, scope := range scopes {
+		if !middleware.ValidScope(scope) {
+			return fmt.Errorf("unknown or ungrantable scope %q", scope)
+		}
+	}
+	return nil
+}
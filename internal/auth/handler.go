@@ -0,0 +1,238 @@
+package auth
+
+import (
+	"restaurant/internal/errors"
+	"restaurant/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler handles HTTP requests for staff authentication
+type Handler struct {
+	svc     AuthService
+	limiter *middleware.RateLimiter
+}
+
+// NewHandler creates a new auth handler. Its rate limit policies are attached to limiter, which may be nil.
+func NewHandler(svc AuthService, limiter *middleware.RateLimiter) *Handler {
+	return &Handler{svc: svc, limiter: limiter}
+}
+
+// authPermissions declares what each auth route needs
+var authPermissions = middleware.RoutePermissions{
+	"POST /auth/login":                middleware.PermPublic,
+	"POST /auth/refresh":              middleware.PermPublic,
+	"POST /auth/logout":               middleware.PermAccount,
+	"GET /auth/me":                    middleware.PermAccount,
+	"GET /auth/roles":                 middleware.PermAccount,
+	"PUT /auth/credentials/:staff_id": middleware.PermStaffManage,
+}
+
+// authRateLimits puts the routes that check credentials under a tight limit per IP address, slowing down
+// password guessing
+var authRateLimits = middleware.RouteRateLimits{
+	"POST /auth/login":   middleware.PolicyLogin,
+	"POST /auth/refresh": middleware.PolicyLogin,
+}
+
+// RegisterRoutes registers all auth routes with the Gin router
+func (h *Handler) RegisterRoutes(router *gin.Engine) {
+	if h.limiter != nil {
+		h.limiter.Attach(authRateLimits)
+	}
+
+	authGroup := router.Group("/auth", authPermissions.Authorize())
+	{
+		authGroup.POST("/login", h.Login)
+		authGroup.POST("/refresh", h.Refresh)
+		authGroup.POST("/logout", h.Logout)
+		authGroup.GET("/me", h.GetMe)
+		authGroup.GET("/roles", h.ListRoles)
+		authGroup.PUT("/credentials/:staff_id", h.SetCredentials)
+	}
+}
+
+// Login handles POST /auth/login
+// @Summary Log in
+// @Description Exchange a staff username and password for an access token and a refresh token
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body LoginRequest true "Credentials"
+// @Success 200 {object} LoginResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /auth/login [post]
+func (h *Handler) Login(c *gin.Context) {
+	var req LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	if err := ValidateLogin(req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	resp, err := h.svc.Login(c.Request.Context(), &req)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.JSON(200, resp)
+}
+
+// Refresh handles POST /auth/refresh
+// @Summary Refresh tokens
+// @Description Exchange a refresh token for a new token pair; each refresh token can only be used once
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body RefreshRequest true "Refresh token"
+// @Success 200 {object} LoginResponse
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /auth/refresh [post]
+func (h *Handler) Refresh(c *gin.Context) {
+	var req RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	if err := ValidateRefresh(req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	resp, err := h.svc.Refresh(c.Request.Context(), &req)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.JSON(200, resp)
+}
+
+// Logout handles POST /auth/logout
+// @Summary Log out
+// @Description Revoke the access token the request is made with, and the refresh token if one is sent
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body LogoutRequest false "Refresh token to revoke"
+// @Success 204 "No Content"
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /auth/logout [post]
+func (h *Handler) Logout(c *gin.Context) {
+	var req LogoutRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			middleware.HandleError(c, errors.NewValidationError(err.Error()))
+			return
+		}
+	}
+
+	if err := ValidateLogout(req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	principal, _ := middleware.GetPrincipal(c) // set by authPermissions
+	if err := h.svc.Logout(c.Request.Context(), principal, &req); err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.Status(204)
+}
+
+// GetMe handles GET /auth/me
+// @Summary Current account
+// @Description The staff account the request is authenticated as
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} Account
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /auth/me [get]
+func (h *Handler) GetMe(c *gin.Context) {
+	principal, _ := middleware.GetPrincipal(c) // set by authPermissions
+
+	account, err := h.svc.GetAccount(c.Request.Context(), principal.StaffID)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.JSON(200, account)
+}
+
+// ListRoles handles GET /auth/roles
+// @Summary List roles
+// @Description Every role with the permissions it grants; guest tokens have the guest role
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} RoleGrant
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Router /auth/roles [get]
+func (h *Handler) ListRoles(c *gin.Context) {
+	c.JSON(200, h.svc.ListRoles())
+}
+
+// SetCredentials handles PUT /auth/credentials/:staff_id
+// @Summary Set login credentials
+// @Description Set the username and password a staff member logs in with, replacing any existing ones
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param staff_id path string true "Staff ID (UUID)"
+// @Param request body SetCredentialsRequest true "Username and password"
+// @Success 200 {object} Account
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 409 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /auth/credentials/{staff_id} [put]
+func (h *Handler) SetCredentials(c *gin.Context) {
+	staffID, ok := middleware.UUIDParam(c, "staff_id")
+	if !ok {
+		return
+	}
+
+	var req SetCredentialsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	if err := ValidateSetCredentials(req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	account, err := h.svc.SetCredentials(c.Request.Context(), staffID, &req)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.JSON(200, account)
+}
@@ -0,0 +1,36 @@
+package auth
+
+import (
+	"restaurant/internal/middleware"
+
+	"github.com/google/uuid"
+)
+
+// Account is a staff member as seen by authentication
+type Account struct {
+	StaffID  uuid.UUID `json:"staff_id"`
+	Name     string    `json:"name"`
+	Username string    `json:"username,omitempty"` // empty if the staff member cannot log in
+	Role     string    `json:"role"`
+	Active   bool      `json:"active"` // inactive staff cannot log in or refresh their tokens
+
+	Permissions []middleware.Permission `json:"permissions"` // granted by the role
+}
+
+// Credentials is an account with the bcrypt hash of its password
+type Credentials struct {
+	Account
+	PasswordHash string
+}
+
+// LoginResponse is returned by login and refresh: a new token pair and the account it was issued for
+type LoginResponse struct {
+	*middleware.TokenPair
+	Account *Account `json:"account"`
+}
+
+// RoleGrant is a role with the permissions it grants
+type RoleGrant struct {
+	Role        string                  `json:"role"`
+	Permissions []middleware.Permission `json:"permissions"`
+}
@@ -0,0 +1,175 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"restaurant/internal/errors"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// accountColumns is the column list scanned by scanAccount, over staff s LEFT JOIN staff_credentials sc
+const accountColumns = "s.id, s.name, COALESCE(sc.username, ''), s.role, s.active"
+
+// Repository defines methods for staff credential and token revocation database operations.
+// It implements middleware.RevocationStore.
+type Repository interface {
+	// FindCredentials retrieves the account and password hash for a username
+	FindCredentials(ctx context.Context, username string) (*Credentials, error)
+
+	// GetAccount retrieves the account of a staff member
+	GetAccount(ctx context.Context, staffID uuid.UUID) (*Account, error)
+
+	// SetCredentials creates or replaces the username and password hash of a staff member
+	SetCredentials(ctx context.Context, staffID uuid.UUID, username string, passwordHash string) error
+
+	// HasCredentials reports whether any staff member can log in
+	HasCredentials(ctx context.Context) (bool, error)
+
+	// CreateAccount inserts a staff member together with their credentials
+	CreateAccount(ctx context.Context, account *Account, passwordHash string) error
+
+	// RevokeToken records that a token may no longer be used
+	RevokeToken(ctx context.Context, id string, expiresAt time.Time) error
+
+	// ConsumeToken revokes a token, reporting false if it was already revoked
+	ConsumeToken(ctx context.Context, id string, expiresAt time.Time) (bool, error)
+
+	// IsTokenRevoked reports whether a token was revoked
+	IsTokenRevoked(ctx context.Context, id string) (bool, error)
+}
+
+// postgresRepository implements Repository using PostgreSQL
+type postgresRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresRepository creates a new PostgreSQL-based auth repository
+func NewPostgresRepository(db *sql.DB) Repository {
+	return &postgresRepository{db: db}
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanAccount scans accountColumns, followed by any extra destinations, into an Account
+func scanAccount(row rowScanner, extra ...interface{}) (*Account, error) {
+	var a Account
+	dest := append([]interface{}{&a.StaffID, &a.Name, &a.Username, &a.Role, &a.Active}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// FindCredentials retrieves the account and password hash for a username
+func (r *postgresRepository) FindCredentials(ctx context.Context, username string) (*Credentials, error) {
+	var hash string
+	account, err := scanAccount(r.db.QueryRowContext(ctx, "SELECT "+accountColumns+", sc.password_hash FROM staff_credentials sc JOIN staff s ON s.id = sc.staff_id WHERE sc.username = $1", username), &hash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ErrInvalidCredentials
+		}
+		return nil, errors.NewInternalError("failed to get credentials", err)
+	}
+	return &Credentials{Account: *account, PasswordHash: hash}, nil
+}
+
+// GetAccount retrieves the account of a staff member
+func (r *postgresRepository) GetAccount(ctx context.Context, staffID uuid.UUID) (*Account, error) {
+	account, err := scanAccount(r.db.QueryRowContext(ctx, "SELECT "+accountColumns+" FROM staff s LEFT JOIN staff_credentials sc ON sc.staff_id = s.id WHERE s.id = $1", staffID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ErrStaffNotFound
+		}
+		return nil, errors.NewInternalError("failed to get account", err)
+	}
+	return account, nil
+}
+
+// SetCredentials creates or replaces the username and password hash of a staff member
+func (r *postgresRepository) SetCredentials(ctx context.Context, staffID uuid.UUID, username string, passwordHash string) error {
+	_, err := r.db.ExecContext(ctx, `INSERT INTO staff_credentials (staff_id, username, password_hash, updated_at) VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (staff_id) DO UPDATE SET username = EXCLUDED.username, password_hash = EXCLUDED.password_hash, updated_at = EXCLUDED.updated_at`,
+		staffID, username, passwordHash)
+	return credentialsError(err)
+}
+
+// HasCredentials reports whether any staff member can log in
+func (r *postgresRepository) HasCredentials(ctx context.Context) (bool, error) {
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM staff_credentials)").Scan(&exists); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+// CreateAccount inserts a staff member together with their credentials
+func (r *postgresRepository) CreateAccount(ctx context.Context, account *Account, passwordHash string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.WrapError(500, "failed to begin transaction", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, "INSERT INTO staff (id, name, role, active, created_at) VALUES ($1, $2, $3, $4, NOW())",
+		account.StaffID, account.Name, account.Role, account.Active)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, "INSERT INTO staff_credentials (staff_id, username, password_hash, updated_at) VALUES ($1, $2, $3, NOW())",
+		account.StaffID, account.Username, passwordHash)
+	if err != nil {
+		return credentialsError(err)
+	}
+	return tx.Commit()
+}
+
+// RevokeToken records that a token may no longer be used
+func (r *postgresRepository) RevokeToken(ctx context.Context, id string, expiresAt time.Time) error {
+	_, err := r.ConsumeToken(ctx, id, expiresAt)
+	return err
+}
+
+// ConsumeToken revokes a token, reporting false if it was already revoked. Revocations of tokens that have
+// since expired are purged on the way.
+func (r *postgresRepository) ConsumeToken(ctx context.Context, id string, expiresAt time.Time) (bool, error) {
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM revoked_tokens WHERE expires_at < NOW()"); err != nil {
+		return false, err
+	}
+	result, err := r.db.ExecContext(ctx, "INSERT INTO revoked_tokens (id, expires_at) VALUES ($1, $2) ON CONFLICT (id) DO NOTHING", id, expiresAt)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+// IsTokenRevoked reports whether a token was revoked
+func (r *postgresRepository) IsTokenRevoked(ctx context.Context, id string) (bool, error) {
+	var revoked bool
+	if err := r.db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM revoked_tokens WHERE id = $1)", id).Scan(&revoked); err != nil {
+		return false, err
+	}
+	return revoked, nil
+}
+
+// credentialsError maps constraint violations on staff_credentials to API errors
+func credentialsError(err error) error {
+	if pqErr, ok := err.(*pq.Error); ok {
+		switch pqErr.Code {
+		case "23505":
+			return errors.NewConflictError("username is already taken")
+		case "23503":
+			return errors.ErrStaffNotFound
+		}
+	}
+	return err
+}
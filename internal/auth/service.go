@@ -0,0 +1,198 @@
+package auth
+
+import (
+	"context"
+	apperrors "restaurant/internal/errors"
+	"restaurant/internal/middleware"
+	"restaurant/internal/staff"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// AuthService defines business logic for staff login, token refresh and logout
+type AuthService interface {
+	Login(ctx context.Context, req *LoginRequest) (*LoginResponse, error)
+	Refresh(ctx context.Context, req *RefreshRequest) (*LoginResponse, error)
+	Logout(ctx context.Context, principal *middleware.Principal, req *LogoutRequest) error
+	GetAccount(ctx context.Context, staffID uuid.UUID) (*Account, error)
+	ListRoles() []RoleGrant
+	SetCredentials(ctx context.Context, staffID uuid.UUID, req *SetCredentialsRequest) (*Account, error)
+
+	// EnsureAdmin creates a manager account with the given credentials if no staff member can log in yet
+	EnsureAdmin(ctx context.Context, username string, password string) error
+}
+
+// authService implements AuthService
+type authService struct {
+	repo  Repository
+	authn *middleware.Authenticator
+
+	dummyOnce sync.Once
+	dummyHash []byte
+}
+
+// NewService creates a new auth service
+func NewService(repo Repository, authn *middleware.Authenticator) AuthService {
+	return &authService{repo: repo, authn: authn}
+}
+
+// Login checks a username and password and issues a token pair. Unknown usernames, wrong passwords and
+// inactive staff all get the same error.
+func (s *authService) Login(ctx context.Context, req *LoginRequest) (*LoginResponse, error) {
+	creds, err := s.repo.FindCredentials(ctx, normalizeUsername(req.Username))
+	if err != nil {
+		if err == apperrors.ErrInvalidCredentials {
+			// Compare against a dummy hash so unknown usernames take as long as wrong passwords
+			bcrypt.CompareHashAndPassword(s.dummy(), []byte(req.Password))
+			return nil, apperrors.ErrInvalidCredentials
+		}
+		return nil, apperrors.WrapError(500, "failed to log in", err)
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(creds.PasswordHash), []byte(req.Password)); err != nil {
+		return nil, apperrors.ErrInvalidCredentials
+	}
+	if !creds.Active {
+		return nil, apperrors.ErrInvalidCredentials
+	}
+	return s.issue(&creds.Account)
+}
+
+// Refresh exchanges a refresh token for a new token pair. Refresh tokens are single use: the one presented
+// is revoked, so a stolen token stops working once either party has used it.
+func (s *authService) Refresh(ctx context.Context, req *RefreshRequest) (*LoginResponse, error) {
+	claims, err := s.authn.VerifyToken(ctx, req.RefreshToken, middleware.TokenRefresh)
+	if err != nil {
+		return nil, err
+	}
+	staffID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return nil, apperrors.ErrInvalidToken
+	}
+
+	consumed, err := s.repo.ConsumeToken(ctx, claims.ID, claims.Expiry())
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to revoke refresh token", err)
+	}
+	if !consumed {
+		return nil, apperrors.ErrInvalidToken
+	}
+
+	account, err := s.repo.GetAccount(ctx, staffID)
+	if err != nil {
+		if err == apperrors.ErrStaffNotFound {
+			return nil, apperrors.ErrInvalidToken
+		}
+		return nil, apperrors.WrapError(500, "failed to get account", err)
+	}
+	if !account.Active {
+		return nil, apperrors.ErrInvalidToken
+	}
+	return s.issue(account)
+}
+
+// Logout revokes the access token the request was made with, and the refresh token if one is sent
+func (s *authService) Logout(ctx context.Context, principal *middleware.Principal, req *LogoutRequest) error {
+	if req.RefreshToken != "" {
+		claims, err := s.authn.VerifyToken(ctx, req.RefreshToken, middleware.TokenRefresh)
+		if err != nil {
+			return err
+		}
+		if claims.Subject != principal.StaffID.String() {
+			return apperrors.ErrInvalidToken
+		}
+		if err := s.authn.RevokeToken(ctx, claims.ID, claims.Expiry()); err != nil {
+			return err
+		}
+	}
+	return s.authn.RevokeToken(ctx, principal.TokenID, principal.ExpiresAt)
+}
+
+// GetAccount retrieves the account of a staff member
+func (s *authService) GetAccount(ctx context.Context, staffID uuid.UUID) (*Account, error) {
+	account, err := s.repo.GetAccount(ctx, staffID)
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to get account", err)
+	}
+	account.Permissions = middleware.RolePermissions(account.Role)
+	return account, nil
+}
+
+// ListRoles returns every role with the permissions it grants
+func (s *authService) ListRoles() []RoleGrant {
+	var roles []RoleGrant
+	for _, role := range middleware.RoleNames() {
+		roles = append(roles, RoleGrant{Role: role, Permissions: middleware.RolePermissions(role)})
+	}
+	return roles
+}
+
+// SetCredentials sets the username and password a staff member logs in with, replacing any existing ones.
+// Tokens already issued stay valid until they expire or are revoked.
+func (s *authService) SetCredentials(ctx context.Context, staffID uuid.UUID, req *SetCredentialsRequest) (*Account, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to hash password", err)
+	}
+	if err := s.repo.SetCredentials(ctx, staffID, normalizeUsername(req.Username), string(hash)); err != nil {
+		return nil, apperrors.WrapError(500, "failed to set credentials", err)
+	}
+	return s.GetAccount(ctx, staffID)
+}
+
+// EnsureAdmin creates a manager account with the given credentials if no staff member can log in yet, so a
+// fresh deployment can be bootstrapped
+func (s *authService) EnsureAdmin(ctx context.Context, username string, password string) error {
+	exists, err := s.repo.HasCredentials(ctx)
+	if err != nil {
+		return apperrors.WrapError(500, "failed to check credentials", err)
+	}
+	if exists {
+		return nil
+	}
+
+	req := SetCredentialsRequest{Username: username, Password: password}
+	if err := ValidateSetCredentials(req); err != nil {
+		return apperrors.NewValidationError(err.Error())
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return apperrors.NewInternalError("failed to hash password", err)
+	}
+	account := &Account{
+		StaffID:  uuid.New(),
+		Name:     "Administrator",
+		Username: normalizeUsername(username),
+		Role:     string(staff.RoleManager),
+		Active:   true,
+	}
+	if err := s.repo.CreateAccount(ctx, account, string(hash)); err != nil {
+		return apperrors.WrapError(500, "failed to create admin account", err)
+	}
+	return nil
+}
+
+// issue creates a token pair for an account
+func (s *authService) issue(account *Account) (*LoginResponse, error) {
+	tokens, err := s.authn.IssueStaffTokens(account.StaffID, account.Role)
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to issue tokens", err)
+	}
+	account.Permissions = middleware.RolePermissions(account.Role)
+	return &LoginResponse{TokenPair: tokens, Account: account}, nil
+}
+
+// dummy returns a bcrypt hash of a random password, built on first use
+func (s *authService) dummy() []byte {
+	s.dummyOnce.Do(func() {
+		s.dummyHash, _ = bcrypt.GenerateFromPassword([]byte(uuid.NewString()), bcrypt.DefaultCost)
+	})
+	return s.dummyHash
+}
+
+// normalizeUsername makes usernames case-insensitive
+func normalizeUsername(username string) string {
+	return strings.ToLower(strings.TrimSpace(username))
+}
@@ -0,0 +1,58 @@
+package auth
+
+import (
+	"errors"
+	"regexp"
+)
+
+// usernamePattern allows letters, digits, dots, dashes and underscores, starting with a letter or digit
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// LoginRequest represents a staff login
+type LoginRequest struct {
+	Username string `json:"username" validate:"required,max=50"`
+	Password string `json:"password" validate:"required,max=72"`
+}
+
+// RefreshRequest represents the exchange of a refresh token for a new token pair
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required,max=1000"`
+}
+
+// LogoutRequest represents a logout; the refresh token is revoked along with the access token if sent
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"omitempty,max=1000"`
+}
+
+// SetCredentialsRequest represents the request to set the username and password a staff member logs in with.
+// Passwords are capped at 72 bytes, the most bcrypt uses.
+type SetCredentialsRequest struct {
+	Username string `json:"username" validate:"required,min=3,max=50"`
+	Password string `json:"password" validate:"required,min=8,max=72"`
+}
+
+// ValidateLogin validates the login request
+func ValidateLogin(req LoginRequest) error {
+	return ValidateStruct(req)
+}
+
+// ValidateRefresh validates the refresh request
+func ValidateRefresh(req RefreshRequest) error {
+	return ValidateStruct(req)
+}
+
+// ValidateLogout validates the logout request
+func ValidateLogout(req LogoutRequest) error {
+	return ValidateStruct(req)
+}
+
+// ValidateSetCredentials validates the set credentials request
+func ValidateSetCredentials(req SetCredentialsRequest) error {
+	if err := ValidateStruct(req); err != nil {
+		return err
+	}
+	if !usernamePattern.MatchString(req.Username) {
+		return errors.New("username may only contain letters, digits, dots, dashes and underscores")
+	}
+	return nil
+}
@@ -1,52 +1,82 @@
 package cache
 
 import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
 	"sync"
 	"time"
+
+	"golang.org/x/sync/singleflight"
 )
 
-// CacheEntry represents a cached value with expiration
-type CacheEntry struct {
-	Value     interface{}
-	ExpiresAt time.Time
+// Config sets the lifetime and size limits of a cache
+type Config[V any] struct {
+	TTL             time.Duration // lifetime of entries stored without their own TTL; 5 minutes if zero
+	CleanupInterval time.Duration // how often expired entries are removed; 1 minute if zero
+	MaxEntries      int           // entries kept before the least recently used is evicted; 0 for no limit
+	MaxBytes        int64         // total size of values kept, as measured by SizeOf; 0 for no limit
+	SizeOf          func(V) int64 // size of a value for MaxBytes; JSONSize if nil
+}
+
+// entry is a cached value in the LRU list
+type entry[K comparable, V any] struct {
+	key       K
+	value     V
+	size      int64
+	expiresAt time.Time
 }
 
-// IsExpired checks if the cache entry has expired
-func (ce *CacheEntry) IsExpired() bool {
-	return time.Now().After(ce.ExpiresAt)
+// Cache is an in-memory cache with per-entry TTL, bounded by entry count and optionally by bytes, evicting the
+// least recently used entries first. Services use it through caching decorators, such as
+// menu.NewCachedService, which invalidate entries on every write.
+type Cache[K comparable, V any] struct {
+	config Config[V]
+
+	mu         sync.Mutex
+	entries    map[K]*list.Element // of *entry[K, V]
+	lru        *list.List          // most recently used at the front
+	bytes      int64
+	generation uint64 // bumped by every invalidation
+	stats      Stats
+
+	loads    singleflight.Group
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
 }
 
-// Cache provides a simple in-memory caching with TTL
-// TODO: IMPLEMENTATION - Integrate this cache into services for:
-//   - Menu items (long TTL: 30-60 min) - rarely change
-//   - Active sessions (medium TTL: 2-10 min) - change moderately
-//   - Paginated results (short TTL: 1-5 min) - frequently accessed
-//
-// Usage: Initialize cache in main.go and pass to service constructors
-type Cache struct {
-	data    map[string]*CacheEntry
-	mu      sync.RWMutex
-	ttl     time.Duration
-	cleanup time.Duration
-	ticker  *time.Ticker
-	stop    chan struct{}
+// Stats are the counters of a cache
+type Stats struct {
+	Hits        uint64  `json:"hits"`
+	Misses      uint64  `json:"misses"`
+	HitRate     float64 `json:"hit_rate"`    // hits over lookups, 0 before the first lookup
+	Evictions   uint64  `json:"evictions"`   // entries dropped to stay within MaxEntries or MaxBytes
+	Expirations uint64  `json:"expirations"` // entries dropped because their TTL passed
+	Entries     int     `json:"entries"`
+	Bytes       int64   `json:"bytes"` // total size of values, if the cache has a byte budget
 }
 
-// NewCache creates a new cache with the specified TTL
-func NewCache(ttl time.Duration, cleanupInterval time.Duration) *Cache {
-	if ttl == 0 {
-		ttl = 5 * time.Minute
+// NewCache creates a cache and starts removing expired entries in the background; Stop ends that
+func NewCache[K comparable, V any](config Config[V]) *Cache[K, V] {
+	if config.TTL <= 0 {
+		config.TTL = 5 * time.Minute
 	}
-	if cleanupInterval == 0 {
-		cleanupInterval = 1 * time.Minute
+	if config.CleanupInterval <= 0 {
+		config.CleanupInterval = time.Minute
+	}
+	if config.MaxBytes > 0 && config.SizeOf == nil {
+		config.SizeOf = JSONSize[V]
 	}
 
-	c := &Cache{
-		data:    make(map[string]*CacheEntry),
-		ttl:     ttl,
-		cleanup: cleanupInterval,
-		ticker:  time.NewTicker(cleanupInterval),
+	c := &Cache[K, V]{
+		config:  config,
+		entries: make(map[K]*list.Element),
+		lru:     list.New(),
 		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
 	}
 
 	// Start cleanup goroutine
@@ -55,87 +85,247 @@ func NewCache(ttl time.Duration, cleanupInterval time.Duration) *Cache {
 	return c
 }
 
+// JSONSize estimates the size of a value as the length of its JSON encoding
+func JSONSize[V any](value V) int64 {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return 0
+	}
+	return int64(len(data))
+}
+
 // Set stores a value in cache with default TTL
-func (c *Cache) Set(key string, value interface{}) {
+func (c *Cache[K, V]) Set(key K, value V) {
+	c.SetWithTTL(key, value, c.config.TTL)
+}
+
+// SetWithTTL stores a value in cache with custom TTL
+func (c *Cache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	c.data[key] = &CacheEntry{
-		Value:     value,
-		ExpiresAt: time.Now().Add(c.ttl),
+	c.store(key, value, ttl)
+}
+
+// store adds or replaces an entry, then evicts the least recently used entries until the cache fits its
+// limits. A value larger than the whole byte budget is not stored. Callers hold mu.
+func (c *Cache[K, V]) store(key K, value V, ttl time.Duration) {
+	var size int64
+	if c.config.MaxBytes > 0 {
+		size = c.config.SizeOf(value)
+		if size > c.config.MaxBytes {
+			c.remove(key)
+			return
+		}
+	}
+
+	if element, exists := c.entries[key]; exists {
+		e := element.Value.(*entry[K, V])
+		c.bytes += size - e.size
+		e.value, e.size, e.expiresAt = value, size, time.Now().Add(ttl)
+		c.lru.MoveToFront(element)
+	} else {
+		c.entries[key] = c.lru.PushFront(&entry[K, V]{key: key, value: value, size: size, expiresAt: time.Now().Add(ttl)})
+		c.bytes += size
+	}
+
+	for (c.config.MaxEntries > 0 && len(c.entries) > c.config.MaxEntries) ||
+		(c.config.MaxBytes > 0 && c.bytes > c.config.MaxBytes) {
+		c.removeElement(c.lru.Back())
+		c.stats.Evictions++
 	}
 }
 
-// SetWithTTL stores a value in cache with custom TTL
-func (c *Cache) SetWithTTL(key string, value interface{}, ttl time.Duration) {
+// Get retrieves a value from cache if it exists and hasn't expired, marking it as recently used
+func (c *Cache[K, V]) Get(key K) (V, bool) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	c.data[key] = &CacheEntry{
-		Value:     value,
-		ExpiresAt: time.Now().Add(ttl),
+	value, ok := c.lookup(key)
+	if ok {
+		c.stats.Hits++
+	} else {
+		c.stats.Misses++
 	}
+	return value, ok
 }
 
-// Get retrieves a value from cache if it exists and hasn't expired
-func (c *Cache) Get(key string) (interface{}, bool) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-
-	entry, exists := c.data[key]
+// lookup retrieves a live value without counting a hit or miss. Callers hold mu.
+func (c *Cache[K, V]) lookup(key K) (V, bool) {
+	var zero V
+	element, exists := c.entries[key]
 	if !exists {
-		return nil, false
+		return zero, false
 	}
 
-	if entry.IsExpired() {
-		return nil, false
+	e := element.Value.(*entry[K, V])
+	if time.Now().After(e.expiresAt) {
+		c.removeElement(element)
+		c.stats.Expirations++
+		return zero, false
+	}
+
+	c.lru.MoveToFront(element)
+	return e.value, true
+}
+
+// GetOrLoad retrieves a value from cache, or loads and stores it with the given TTL (0 for the default).
+// Concurrent misses on a key share one load, so an expired entry does not send a stampede to the database.
+// Errors are not cached, and a load that overlaps an invalidation is returned but not stored, since it may
+// have read the data before the write.
+func (c *Cache[K, V]) GetOrLoad(key K, ttl time.Duration, load func() (V, error)) (V, error) {
+	if value, ok := c.Get(key); ok {
+		return value, nil
 	}
 
-	return entry.Value, true
+	c.mu.Lock()
+	generation := c.generation
+	c.mu.Unlock()
+
+	// Loads started after an invalidation must not join one started before it
+	value, err, _ := c.loads.Do(fmt.Sprintf("%v@%d", key, generation), func() (interface{}, error) {
+		value, err := load()
+		if err != nil {
+			return nil, err
+		}
+		if ttl <= 0 {
+			ttl = c.config.TTL
+		}
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if c.generation == generation {
+			c.store(key, value, ttl)
+		}
+		return value, nil
+	})
+	if err != nil {
+		var zero V
+		return zero, err
+	}
+	return value.(V), nil
+}
+
+// Load is GetOrLoad for caches holding values of several types
+func Load[T any, K comparable](c *Cache[K, any], key K, load func() (T, error)) (T, error) {
+	value, err := c.GetOrLoad(key, 0, func() (any, error) { return load() })
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return value.(T), nil
 }
 
 // Delete removes a value from cache
-func (c *Cache) Delete(key string) {
+func (c *Cache[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.remove(key)
+	c.generation++
+}
+
+// DeleteFunc removes every value whose key matches
+func (c *Cache[K, V]) DeleteFunc(match func(K) bool) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	delete(c.data, key)
+	for key, element := range c.entries {
+		if match(key) {
+			c.removeElement(element)
+		}
+	}
+	c.generation++
+}
+
+// DeletePrefix removes every value whose key starts with prefix, such as all pages of a list
+func DeletePrefix[V any](c *Cache[string, V], prefix string) {
+	c.DeleteFunc(func(key string) bool { return strings.HasPrefix(key, prefix) })
 }
 
 // Clear removes all values from cache
-func (c *Cache) Clear() {
+func (c *Cache[K, V]) Clear() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	c.data = make(map[string]*CacheEntry)
+	c.entries = make(map[K]*list.Element)
+	c.lru.Init()
+	c.bytes = 0
+	c.generation++
 }
 
-// cleanupExpired periodically removes expired entries
-func (c *Cache) cleanupExpired() {
-	for range c.ticker.C {
-		c.mu.Lock()
-		now := time.Now()
-		for key, entry := range c.data {
-			if now.After(entry.ExpiresAt) {
-				delete(c.data, key)
+// remove drops the entry under key, if any. Callers hold mu.
+func (c *Cache[K, V]) remove(key K) {
+	if element, exists := c.entries[key]; exists {
+		c.removeElement(element)
+	}
+}
+
+// removeElement drops an entry. Callers hold mu.
+func (c *Cache[K, V]) removeElement(element *list.Element) {
+	e := c.lru.Remove(element).(*entry[K, V])
+	delete(c.entries, e.key)
+	c.bytes -= e.size
+}
+
+// Stats returns the counters of the cache
+func (c *Cache[K, V]) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats
+	stats.Entries = len(c.entries)
+	stats.Bytes = c.bytes
+	if lookups := stats.Hits + stats.Misses; lookups > 0 {
+		stats.HitRate = float64(stats.Hits) / float64(lookups)
+	}
+	return stats
+}
+
+// cleanupExpired periodically removes expired entries until the cache is stopped
+func (c *Cache[K, V]) cleanupExpired() {
+	defer close(c.done)
+
+	ticker := time.NewTicker(c.config.CleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+			c.mu.Lock()
+			now := time.Now()
+			for element := c.lru.Back(); element != nil; {
+				prev := element.Prev()
+				if now.After(element.Value.(*entry[K, V]).expiresAt) {
+					c.removeElement(element)
+					c.stats.Expirations++
+				}
+				element = prev
 			}
+			c.mu.Unlock()
 		}
-		c.mu.Unlock()
 	}
 }
 
-// Stop stops the cache cleanup goroutine
-func (c *Cache) Stop() {
-	c.ticker.Stop()
-	close(c.stop)
+// Stop halts the cleanup goroutine and waits for it to exit. It can be called more than once; the cache keeps
+// working, but expired entries are only removed when looked up.
+func (c *Cache[K, V]) Stop(ctx context.Context) error {
+	c.stopOnce.Do(func() { close(c.stop) })
+	select {
+	case <-c.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 // Size returns the number of entries in cache
-func (c *Cache) Size() int {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+func (c *Cache[K, V]) Size() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-	return len(c.data)
+	return len(c.entries)
 }
 
 // CacheKey generates a cache key for database queries
@@ -163,6 +353,11 @@ func OrderCacheKey(id string) string {
 	return CacheKey("order", id)
 }
 
+// TableCacheKey generates cache key for tables
+func TableCacheKey(id string) string {
+	return CacheKey("table", id)
+}
+
 // ListCacheKey generates cache key for list queries
 func ListCacheKey(resource string, filters string) string {
 	if filters == "" {
@@ -170,3 +365,8 @@ func ListCacheKey(resource string, filters string) string {
 	}
 	return resource + ":list:" + filters
 }
+
+// ListCachePrefix is the prefix shared by every list cache key of a resource, for invalidating them together
+func ListCachePrefix(resource string) string {
+	return resource + ":list:"
+}
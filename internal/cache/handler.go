@@ -0,0 +1,49 @@
+package cache
+
+import (
+	"restaurant/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler handles HTTP requests for cache statistics
+type Handler struct {
+	caches map[string]*Cache[string, any]
+}
+
+// NewHandler creates a new cache handler reporting on the named caches
+func NewHandler(caches map[string]*Cache[string, any]) *Handler {
+	return &Handler{caches: caches}
+}
+
+// cachePermissions declares what each cache route needs
+var cachePermissions = middleware.RoutePermissions{
+	"GET /cache/stats": middleware.PermReportsRead,
+}
+
+// RegisterRoutes registers all cache routes with the Gin router
+func (h *Handler) RegisterRoutes(router *gin.Engine) {
+	cacheGroup := router.Group("/cache", cachePermissions.Authorize())
+	{
+		cacheGroup.GET("/stats", h.GetStats)
+	}
+}
+
+// GetStats handles GET /cache/stats
+// @Summary Get cache statistics
+// @Description Hit, miss, eviction and expiration counters, entry counts and sizes of each service cache since the process started
+// @Tags Cache
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]Stats
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Router /cache/stats [get]
+func (h *Handler) GetStats(c *gin.Context) {
+	stats := make(map[string]Stats, len(h.caches))
+	for name, cache := range h.caches {
+		stats[name] = cache.Stats()
+	}
+	c.JSON(200, stats)
+}
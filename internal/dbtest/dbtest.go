@@ -0,0 +1,64 @@
+// Package dbtest sets up the PostgreSQL database that repository tests run against.
+//
+// Tests that need a database call Open, which skips them unless TEST_DATABASE_URL names a migrated database.
+// Fixtures are created under keys no other test or real record holds, and removed when the test ends.
+package dbtest
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+
+	_ "github.com/lib/pq"
+)
+
+// Open opens the migrated database in TEST_DATABASE_URL, skipping the test if it is not set
+func Open(t *testing.T) *sql.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// NewTable creates a free table seating up to four and returns its ID. The table and any sessions left on it
+// are removed when the test ends; call it before creating rows that reference those sessions, so their
+// cleanups run first.
+func NewTable(t *testing.T, db *sql.DB) int {
+	t.Helper()
+	ctx := context.Background()
+
+	// Table IDs are chosen by the caller, so take the next one free. A concurrent test that claims the same ID
+	// first leaves this insert with no row, and it tries the one after.
+	var id int
+	for {
+		err := db.QueryRowContext(ctx, `INSERT INTO tables (id, min_covers, max_covers, zone)
+			SELECT COALESCE(MAX(id), 0) + 1, 1, 4, 'test' FROM tables
+			ON CONFLICT (id) DO NOTHING RETURNING id`).Scan(&id)
+		if err == nil {
+			break
+		}
+		if err != sql.ErrNoRows {
+			t.Fatal(err)
+		}
+	}
+
+	t.Cleanup(func() {
+		for _, query := range []string{
+			"DELETE FROM sessions WHERE table_id = $1",
+			"DELETE FROM tables WHERE id = $1",
+		} {
+			if _, err := db.ExecContext(ctx, query, id); err != nil {
+				t.Errorf("failed to remove test table %d: %v", id, err)
+			}
+		}
+	})
+	return id
+}
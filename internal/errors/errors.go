@@ -48,6 +48,48 @@ var (
 		Message: "validation failed",
 	}
 
+	// 401 Unauthorized
+	ErrUnauthorized = &AppError{
+		Code:    http.StatusUnauthorized,
+		Message: "authentication required",
+	}
+
+	ErrInvalidTableToken = &AppError{
+		Code:    http.StatusUnauthorized,
+		Message: "invalid or revoked table token",
+	}
+
+	ErrInvalidGuestCredential = &AppError{
+		Code:    http.StatusUnauthorized,
+		Message: "invalid or expired guest credential",
+	}
+
+	ErrInvalidToken = &AppError{
+		Code:    http.StatusUnauthorized,
+		Message: "invalid, expired or revoked token",
+	}
+
+	ErrInvalidCredentials = &AppError{
+		Code:    http.StatusUnauthorized,
+		Message: "invalid username or password",
+	}
+
+	ErrInvalidAPIKey = &AppError{
+		Code:    http.StatusUnauthorized,
+		Message: "invalid, expired or revoked API key",
+	}
+
+	// 403 Forbidden
+	ErrForbidden = &AppError{
+		Code:    http.StatusForbidden,
+		Message: "access denied",
+	}
+
+	ErrTableTokenMismatch = &AppError{
+		Code:    http.StatusForbidden,
+		Message: "table token was issued for a different table",
+	}
+
 	// 404 Not Found
 	ErrNotFound = &AppError{
 		Code:    http.StatusNotFound,
@@ -74,11 +116,41 @@ var (
 		Message: "category not found",
 	}
 
+	ErrTableNotFound = &AppError{
+		Code:    http.StatusNotFound,
+		Message: "table not found",
+	}
+
 	ErrOrderItemNotFound = &AppError{
 		Code:    http.StatusNotFound,
 		Message: "order item not found",
 	}
 
+	ErrReservationNotFound = &AppError{
+		Code:    http.StatusNotFound,
+		Message: "reservation not found",
+	}
+
+	ErrWaitlistEntryNotFound = &AppError{
+		Code:    http.StatusNotFound,
+		Message: "waitlist entry not found",
+	}
+
+	ErrStaffNotFound = &AppError{
+		Code:    http.StatusNotFound,
+		Message: "staff member not found",
+	}
+
+	ErrShiftNotFound = &AppError{
+		Code:    http.StatusNotFound,
+		Message: "shift not found",
+	}
+
+	ErrAPIKeyNotFound = &AppError{
+		Code:    http.StatusNotFound,
+		Message: "API key not found",
+	}
+
 	// 409 Conflict
 	ErrConflict = &AppError{
 		Code:    http.StatusConflict,
@@ -95,12 +167,55 @@ var (
 		Message: "menu item already exists",
 	}
 
+	ErrReservationConflict = &AppError{
+		Code:    http.StatusConflict,
+		Message: "table is already booked or occupied for that time",
+	}
+
+	ErrTableNotAvailable = &AppError{
+		Code:    http.StatusConflict,
+		Message: "table is not available (deleted or has active or pending session)",
+	}
+
+	ErrVersionConflict = &AppError{
+		Code:    http.StatusConflict,
+		Message: "resource was changed by someone else; reload it and retry",
+	}
+
+	ErrIdempotencyKeyInUse = &AppError{
+		Code:    http.StatusConflict,
+		Message: "a request with this Idempotency-Key is still being processed; retry shortly",
+	}
+
+	// 412 Precondition Failed
+	ErrPreconditionFailed = &AppError{
+		Code:    http.StatusPreconditionFailed,
+		Message: "If-Match does not match the current version",
+	}
+
+	// 422 Unprocessable Entity
+	ErrIdempotencyKeyReused = &AppError{
+		Code:    http.StatusUnprocessableEntity,
+		Message: "Idempotency-Key was already used for a different request",
+	}
+
+	// 428 Precondition Required
+	ErrPreconditionRequired = &AppError{
+		Code:    http.StatusPreconditionRequired,
+		Message: "updates need an If-Match header or a version in the body",
+	}
+
 	// 400 Bad Request - Business Logic
 	ErrOutOfStock = &AppError{
 		Code:    http.StatusBadRequest,
 		Message: "item is out of stock",
 	}
 
+	ErrMenuItemDeleted = &AppError{
+		Code:    http.StatusBadRequest,
+		Message: "menu item is no longer on the menu",
+	}
+
 	ErrInsufficientStock = &AppError{
 		Code:    http.StatusBadRequest,
 		Message: "insufficient stock available",
@@ -121,6 +236,18 @@ var (
 		Message: "invalid session status",
 	}
 
+	// 413 Payload Too Large
+	ErrPayloadTooLarge = &AppError{
+		Code:    http.StatusRequestEntityTooLarge,
+		Message: "payload too large",
+	}
+
+	// 415 Unsupported Media Type
+	ErrUnsupportedMediaType = &AppError{
+		Code:    http.StatusUnsupportedMediaType,
+		Message: "unsupported media type",
+	}
+
 	// 500 Internal Server Error
 	ErrInternal = &AppError{
 		Code:    http.StatusInternalServerError,
@@ -164,6 +291,22 @@ func NewValidationError(message string) *AppError {
 	}
 }
 
+// NewUnauthorizedError creates a 401 unauthorized error
+func NewUnauthorizedError(message string) *AppError {
+	return &AppError{
+		Code:    http.StatusUnauthorized,
+		Message: message,
+	}
+}
+
+// NewForbiddenError creates a 403 forbidden error
+func NewForbiddenError(message string) *AppError {
+	return &AppError{
+		Code:    http.StatusForbidden,
+		Message: message,
+	}
+}
+
 // NewConflictError creates a 409 conflict error
 func NewConflictError(message string) *AppError {
 	return &AppError{
@@ -0,0 +1,203 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif" // register GIF decoder
+	"image/jpeg"
+	_ "image/png" // register PNG decoder
+	"io"
+	"net/http"
+	"time"
+
+	apperrors "restaurant/internal/errors"
+	"restaurant/internal/storage"
+)
+
+// ImageConfig holds image upload configuration
+type ImageConfig struct {
+	MaxBytes      int64 // largest accepted upload
+	MaxPixels     int   // largest accepted width times height, checked before the image is decoded
+	ThumbnailSize int   // longest edge of generated thumbnails, in pixels
+	JPEGQuality   int   // quality used when encoding thumbnails
+}
+
+// DefaultImageConfig returns sensible defaults for menu photos
+func DefaultImageConfig() ImageConfig {
+	return ImageConfig{
+		MaxBytes:      5 * 1024 * 1024,
+		MaxPixels:     40_000_000,
+		ThumbnailSize: 320,
+		JPEGQuality:   80,
+	}
+}
+
+// allowedTypes maps accepted content types to the file extension they are stored with
+var allowedTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+}
+
+// Image holds the public URLs of a stored image and its thumbnail
+type Image struct {
+	URL          string `json:"image_url"`
+	ThumbnailURL string `json:"thumbnail_url"`
+}
+
+// ImageProcessor validates uploaded images, generates thumbnails and writes both to storage
+type ImageProcessor struct {
+	store  storage.Storage
+	config ImageConfig
+}
+
+// NewImageProcessor creates a new image processor
+func NewImageProcessor(store storage.Storage, config ImageConfig) *ImageProcessor {
+	if config.MaxBytes == 0 {
+		config.MaxBytes = DefaultImageConfig().MaxBytes
+	}
+	if config.MaxPixels == 0 {
+		config.MaxPixels = DefaultImageConfig().MaxPixels
+	}
+	if config.ThumbnailSize == 0 {
+		config.ThumbnailSize = DefaultImageConfig().ThumbnailSize
+	}
+	if config.JPEGQuality == 0 {
+		config.JPEGQuality = DefaultImageConfig().JPEGQuality
+	}
+	return &ImageProcessor{store: store, config: config}
+}
+
+// MaxBytes returns the largest accepted upload size
+func (p *ImageProcessor) MaxBytes() int64 {
+	return p.config.MaxBytes
+}
+
+// Store validates the image read from r and stores it with a thumbnail under prefix, replacing any previous image
+func (p *ImageProcessor) Store(ctx context.Context, prefix string, r io.Reader) (*Image, error) {
+	data, err := io.ReadAll(io.LimitReader(r, p.config.MaxBytes+1))
+	if err != nil {
+		return nil, apperrors.NewValidationError("failed to read image: " + err.Error())
+	}
+	if int64(len(data)) > p.config.MaxBytes {
+		return nil, apperrors.NewAppError(http.StatusRequestEntityTooLarge,
+			fmt.Sprintf("image must be at most %d bytes", p.config.MaxBytes), nil)
+	}
+
+	// Sniff the content type from the bytes rather than trusting the client
+	contentType := http.DetectContentType(data)
+	ext, ok := allowedTypes[contentType]
+	if !ok {
+		return nil, apperrors.NewAppError(http.StatusUnsupportedMediaType,
+			"image must be JPEG, PNG or GIF, got "+contentType, nil)
+	}
+
+	// A small compressed file can declare huge dimensions, so check them before decoding allocates the pixels
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, apperrors.NewValidationError("invalid image: " + err.Error())
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width > p.config.MaxPixels/cfg.Height {
+		return nil, apperrors.NewValidationError(
+			fmt.Sprintf("image is %dx%d pixels, at most %d pixels are allowed", cfg.Width, cfg.Height, p.config.MaxPixels))
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, apperrors.NewValidationError("invalid image: " + err.Error())
+	}
+
+	var thumb bytes.Buffer
+	if err := jpeg.Encode(&thumb, thumbnail(src, p.config.ThumbnailSize), &jpeg.Options{Quality: p.config.JPEGQuality}); err != nil {
+		return nil, apperrors.NewInternalError("failed to encode thumbnail", err)
+	}
+
+	// The new files replace the old ones under the same keys, so a failed write leaves the previous image in place
+	imageKey := prefix + "/image" + ext
+	thumbKey := prefix + "/thumbnail.jpg"
+	if err := p.store.Put(ctx, imageKey, bytes.NewReader(data), contentType); err != nil {
+		return nil, apperrors.NewInternalError("failed to store image", err)
+	}
+	if err := p.store.Put(ctx, thumbKey, &thumb, "image/jpeg"); err != nil {
+		return nil, apperrors.NewInternalError("failed to store thumbnail", err)
+	}
+
+	// Only a previous image stored with a different extension is left behind
+	for _, staleExt := range allowedTypes {
+		if staleExt == ext {
+			continue
+		}
+		if err := p.store.Delete(ctx, prefix+"/image"+staleExt); err != nil {
+			return nil, apperrors.NewInternalError("failed to delete previous image", err)
+		}
+	}
+
+	// Version query parameter busts client caches when an image is replaced under the same key
+	version := fmt.Sprintf("?v=%d", time.Now().Unix())
+	return &Image{
+		URL:          p.store.URL(imageKey) + version,
+		ThumbnailURL: p.store.URL(thumbKey) + version,
+	}, nil
+}
+
+// Remove deletes the image and thumbnail stored under prefix
+func (p *ImageProcessor) Remove(ctx context.Context, prefix string) error {
+	keys := []string{prefix + "/thumbnail.jpg"}
+	for _, ext := range allowedTypes {
+		keys = append(keys, prefix+"/image"+ext)
+	}
+	for _, key := range keys {
+		if err := p.store.Delete(ctx, key); err != nil {
+			return apperrors.NewInternalError("failed to delete image", err)
+		}
+	}
+	return nil
+}
+
+// thumbnail scales src to fit within maxEdge pixels using a box filter, flattened onto white for JPEG output
+func thumbnail(src image.Image, maxEdge int) image.Image {
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	tw, th := w, h
+	if w > maxEdge || h > maxEdge {
+		if w >= h {
+			tw, th = maxEdge, max(1, h*maxEdge/w)
+		} else {
+			tw, th = max(1, w*maxEdge/h), maxEdge
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, tw, th))
+	for y := 0; y < th; y++ {
+		sy0 := bounds.Min.Y + y*h/th
+		sy1 := max(sy0+1, bounds.Min.Y+(y+1)*h/th)
+		for x := 0; x < tw; x++ {
+			sx0 := bounds.Min.X + x*w/tw
+			sx1 := max(sx0+1, bounds.Min.X+(x+1)*w/tw)
+
+			// Average every source pixel that maps onto this destination pixel
+			var r, g, b, a, n uint64
+			for sy := sy0; sy < sy1; sy++ {
+				for sx := sx0; sx < sx1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(cr), g+uint64(cg), b+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+			r, g, b, a = r/n, g/n, b/n, a/n
+
+			// Colors are alpha-premultiplied, so compositing over white adds the uncovered share
+			white := 0xffff - a
+			dst.Set(x, y, color.RGBA64{
+				R: uint16(r + white),
+				G: uint16(g + white),
+				B: uint16(b + white),
+				A: 0xffff,
+			})
+		}
+	}
+	return dst
+}
@@ -0,0 +1,256 @@
+package menu
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"restaurant/internal/cache"
+
+	"github.com/google/uuid"
+)
+
+// cachedMenuService decorates a MenuService, caching menu item, category and tree reads. Menu item writes
+// invalidate the item, every item list and the tree; category and bulk writes clear the whole cache, since
+// they can change any list. Reads return copies, because handlers localize results in place.
+type cachedMenuService struct {
+	MenuService
+	cache *cache.Cache[string, any]
+}
+
+// menuTreeCacheKey is the cache key of the menu tree
+var menuTreeCacheKey = cache.CacheKey("menu_tree", "all")
+
+// NewCachedService wraps a menu service with a cache. The cache should be used by this service only.
+func NewCachedService(svc MenuService, c *cache.Cache[string, any]) MenuService {
+	return &cachedMenuService{MenuService: svc, cache: c}
+}
+
+// GetMenuItem retrieves a menu item through the cache
+func (s *cachedMenuService) GetMenuItem(ctx context.Context, id uuid.UUID) (*MenuItem, error) {
+	item, err := cache.Load(s.cache, cache.MenuItemCacheKey(id.String()), func() (*MenuItem, error) {
+		return s.MenuService.GetMenuItem(ctx, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return copyMenuItem(item), nil
+}
+
+// ListMenuItems lists menu items through the cache
+func (s *cachedMenuService) ListMenuItems(ctx context.Context, offset int, limit int, includeDeleted bool) ([]*MenuItem, error) {
+	key := cache.ListCacheKey("menu_item", fmt.Sprintf("offset=%d&limit=%d&deleted=%t", offset, limit, includeDeleted))
+	items, err := cache.Load(s.cache, key, func() ([]*MenuItem, error) {
+		return s.MenuService.ListMenuItems(ctx, offset, limit, includeDeleted)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return copyMenuItems(items), nil
+}
+
+// GetMenuItemsByCategory lists the menu items of a category through the cache
+func (s *cachedMenuService) GetMenuItemsByCategory(ctx context.Context, category string, includeDeleted bool) ([]*MenuItem, error) {
+	key := cache.ListCacheKey("menu_item", fmt.Sprintf("category=%s&deleted=%t", category, includeDeleted))
+	items, err := cache.Load(s.cache, key, func() ([]*MenuItem, error) {
+		return s.MenuService.GetMenuItemsByCategory(ctx, category, includeDeleted)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return copyMenuItems(items), nil
+}
+
+// ListCategories lists categories through the cache
+func (s *cachedMenuService) ListCategories(ctx context.Context, includeDeleted bool) ([]Category, error) {
+	key := cache.ListCacheKey("category", fmt.Sprintf("deleted=%t", includeDeleted))
+	categories, err := cache.Load(s.cache, key, func() ([]Category, error) {
+		return s.MenuService.ListCategories(ctx, includeDeleted)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append([]Category(nil), categories...), nil
+}
+
+// GetCategoryByID retrieves a category through the cache
+func (s *cachedMenuService) GetCategoryByID(ctx context.Context, id uuid.UUID) (*Category, error) {
+	category, err := cache.Load(s.cache, cache.CategoryCacheKey(id.String()), func() (*Category, error) {
+		return s.MenuService.GetCategoryByID(ctx, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	c := *category
+	return &c, nil
+}
+
+// GetMenuTree builds the menu tree through the cache
+func (s *cachedMenuService) GetMenuTree(ctx context.Context) ([]*CategoryNode, error) {
+	tree, err := cache.Load(s.cache, menuTreeCacheKey, func() ([]*CategoryNode, error) {
+		return s.MenuService.GetMenuTree(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return copyMenuTree(tree), nil
+}
+
+// invalidateItem removes a menu item and everything listing menu items
+func (s *cachedMenuService) invalidateItem(id uuid.UUID) {
+	s.cache.Delete(cache.MenuItemCacheKey(id.String()))
+	cache.DeletePrefix(s.cache, cache.ListCachePrefix("menu_item"))
+	s.cache.Delete(menuTreeCacheKey)
+}
+
+// CreateMenuItem creates a menu item and invalidates item lists
+func (s *cachedMenuService) CreateMenuItem(ctx context.Context, Name string, Description string, Price float64, Category string, AvalabilityStatus ItemStatus) (*MenuItem, error) {
+	item, err := s.MenuService.CreateMenuItem(ctx, Name, Description, Price, Category, AvalabilityStatus)
+	if item != nil {
+		s.invalidateItem(item.ID)
+	}
+	return item, err
+}
+
+// UpdateMenuItem updates a menu item and invalidates it
+func (s *cachedMenuService) UpdateMenuItem(ctx context.Context, id uuid.UUID, name string, desc string, category string, price float64, avalabilityStatus ItemStatus, version int) (*MenuItem, error) {
+	defer s.invalidateItem(id)
+	return s.MenuService.UpdateMenuItem(ctx, id, name, desc, category, price, avalabilityStatus, version)
+}
+
+// DeleteMenuItem deletes a menu item and invalidates it
+func (s *cachedMenuService) DeleteMenuItem(ctx context.Context, id uuid.UUID) error {
+	defer s.invalidateItem(id)
+	return s.MenuService.DeleteMenuItem(ctx, id)
+}
+
+// RestoreMenuItem restores a menu item and invalidates it
+func (s *cachedMenuService) RestoreMenuItem(ctx context.Context, id uuid.UUID) (*MenuItem, error) {
+	defer s.invalidateItem(id)
+	return s.MenuService.RestoreMenuItem(ctx, id)
+}
+
+// UploadMenuItemImage sets the photo of a menu item and invalidates it
+func (s *cachedMenuService) UploadMenuItemImage(ctx context.Context, id uuid.UUID, r io.Reader) (*MenuItem, error) {
+	defer s.invalidateItem(id)
+	return s.MenuService.UploadMenuItemImage(ctx, id, r)
+}
+
+// DeleteMenuItemImage removes the photo of a menu item and invalidates it
+func (s *cachedMenuService) DeleteMenuItemImage(ctx context.Context, id uuid.UUID) error {
+	defer s.invalidateItem(id)
+	return s.MenuService.DeleteMenuItemImage(ctx, id)
+}
+
+// CreateCategory creates a category and clears the cache
+func (s *cachedMenuService) CreateCategory(ctx context.Context, name string, parentID *uuid.UUID, sortOrder int) (*Category, error) {
+	defer s.cache.Clear()
+	return s.MenuService.CreateCategory(ctx, name, parentID, sortOrder)
+}
+
+// DeleteCategory deletes a category and clears the cache
+func (s *cachedMenuService) DeleteCategory(ctx context.Context, name string) error {
+	defer s.cache.Clear()
+	return s.MenuService.DeleteCategory(ctx, name)
+}
+
+// RestoreCategory restores a category and clears the cache
+func (s *cachedMenuService) RestoreCategory(ctx context.Context, id uuid.UUID) (*Category, error) {
+	defer s.cache.Clear()
+	return s.MenuService.RestoreCategory(ctx, id)
+}
+
+// UpdateCategory renames a category and clears the cache
+func (s *cachedMenuService) UpdateCategory(ctx context.Context, old_name string, new_name string, version int) (*Category, error) {
+	defer s.cache.Clear()
+	return s.MenuService.UpdateCategory(ctx, old_name, new_name, version)
+}
+
+// UploadCategoryImage sets the image of a category and clears the cache
+func (s *cachedMenuService) UploadCategoryImage(ctx context.Context, id uuid.UUID, r io.Reader) (*Category, error) {
+	defer s.cache.Clear()
+	return s.MenuService.UploadCategoryImage(ctx, id, r)
+}
+
+// DeleteCategoryImage removes the image of a category and clears the cache
+func (s *cachedMenuService) DeleteCategoryImage(ctx context.Context, id uuid.UUID) error {
+	defer s.cache.Clear()
+	return s.MenuService.DeleteCategoryImage(ctx, id)
+}
+
+// Reorder moves categories and menu items and clears the cache
+func (s *cachedMenuService) Reorder(ctx context.Context, moves []Move) error {
+	defer s.cache.Clear()
+	return s.MenuService.Reorder(ctx, moves)
+}
+
+// ImportMenu imports menu items and clears the cache unless it is a dry run
+func (s *cachedMenuService) ImportMenu(ctx context.Context, rows []CreateMenuItemRequest, dryRun bool) (*ImportResult, error) {
+	if !dryRun {
+		defer s.cache.Clear()
+	}
+	return s.MenuService.ImportMenu(ctx, rows, dryRun)
+}
+
+// UpsertTranslations stores translations and clears the cache. Cached entries hold base content only, but
+// clearing keeps every write path uniform.
+func (s *cachedMenuService) UpsertTranslations(ctx context.Context, items []MenuItemTranslation, categories []CategoryTranslation) error {
+	defer s.cache.Clear()
+	return s.MenuService.UpsertTranslations(ctx, items, categories)
+}
+
+// DeleteMenuItemTranslation removes a translation and invalidates the menu item
+func (s *cachedMenuService) DeleteMenuItemTranslation(ctx context.Context, itemID uuid.UUID, locale string) error {
+	defer s.invalidateItem(itemID)
+	return s.MenuService.DeleteMenuItemTranslation(ctx, itemID, locale)
+}
+
+// SchedulePriceChange schedules a price and invalidates the menu item, in case the price takes effect at once
+func (s *cachedMenuService) SchedulePriceChange(ctx context.Context, itemID uuid.UUID, price float64, effectiveFrom time.Time) (*MenuItemPrice, error) {
+	defer s.invalidateItem(itemID)
+	return s.MenuService.SchedulePriceChange(ctx, itemID, price, effectiveFrom)
+}
+
+// CancelPriceChange cancels a scheduled price and invalidates the menu item
+func (s *cachedMenuService) CancelPriceChange(ctx context.Context, itemID uuid.UUID, priceID uuid.UUID) error {
+	defer s.invalidateItem(itemID)
+	return s.MenuService.CancelPriceChange(ctx, itemID, priceID)
+}
+
+// ApplyDuePrices applies scheduled prices and clears the cache if any changed
+func (s *cachedMenuService) ApplyDuePrices(ctx context.Context) (int, error) {
+	applied, err := s.MenuService.ApplyDuePrices(ctx)
+	if applied > 0 {
+		s.cache.Clear()
+	}
+	return applied, err
+}
+
+// copyMenuItem returns a copy of a cached menu item
+func copyMenuItem(item *MenuItem) *MenuItem {
+	c := *item
+	return &c
+}
+
+// copyMenuItems returns copies of cached menu items
+func copyMenuItems(items []*MenuItem) []*MenuItem {
+	copies := make([]*MenuItem, len(items))
+	for i, item := range items {
+		copies[i] = copyMenuItem(item)
+	}
+	return copies
+}
+
+// copyMenuTree returns a deep copy of a cached menu tree
+func copyMenuTree(nodes []*CategoryNode) []*CategoryNode {
+	copies := make([]*CategoryNode, len(nodes))
+	for i, node := range nodes {
+		copies[i] = &CategoryNode{
+			Category: node.Category,
+			Children: copyMenuTree(node.Children),
+			Items:    copyMenuItems(node.Items),
+		}
+	}
+	return copies
+}
@@ -1,41 +1,131 @@
 package menu
 
 import (
+	stderrors "errors"
 	"fmt"
+	"mime/multipart"
+	"net/http"
 	"restaurant/internal/errors"
 	"restaurant/internal/middleware"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// imageUploadLimit caps multipart image uploads; the exact image size limit is enforced by the service
+const imageUploadLimit = 10 * 1024 * 1024
+
+// importUploadLimit caps bulk menu import bodies
+const importUploadLimit = 5 * 1024 * 1024
+
 // MenuHandler handles HTTP requests for menu items
 type MenuHandler struct {
-	svc MenuService
+	svc     MenuService
+	limiter *middleware.RateLimiter
+}
+
+// NewMenuHandler creates a new menu handler. Its rate limit policies are attached to limiter, which may be nil.
+func NewMenuHandler(svc MenuService, limiter *middleware.RateLimiter) *MenuHandler {
+	return &MenuHandler{svc: svc, limiter: limiter}
+}
+
+// menuPermissions declares what each menu and category route needs. Reads are public so guests can browse the
+// menu; changes, exports and price history need a manager.
+var menuPermissions = middleware.RoutePermissions{
+	"GET /menu":                             middleware.PermPublic,
+	"POST /menu":                            middleware.PermMenuManage,
+	"GET /menu/tree":                        middleware.PermPublic,
+	"POST /menu/reorder":                    middleware.PermMenuManage,
+	"POST /menu/import":                     middleware.PermMenuManage,
+	"GET /menu/export":                      middleware.PermMenuManage,
+	"PUT /menu/translations":                middleware.PermMenuManage,
+	"GET /menu/translations/missing":        middleware.PermMenuManage,
+	"GET /menu/:id":                         middleware.PermPublic,
+	"GET /menu/category/:name":              middleware.PermPublic,
+	"PUT /menu/:id":                         middleware.PermMenuManage,
+	"DELETE /menu/:id":                      middleware.PermMenuManage,
+	"POST /menu/:id/restore":                middleware.PermMenuManage,
+	"PUT /menu/:id/image":                   middleware.PermMenuManage,
+	"DELETE /menu/:id/image":                middleware.PermMenuManage,
+	"DELETE /menu/:id/translations/:locale": middleware.PermMenuManage,
+	"GET /menu/:id/prices":                  middleware.PermMenuManage,
+	"POST /menu/:id/prices":                 middleware.PermMenuManage,
+	"DELETE /menu/:id/prices/:price_id":     middleware.PermMenuManage,
+	"GET /menu/:id/price":                   middleware.PermPublic,
+	"GET /categories":                       middleware.PermPublic,
+	"POST /categories":                      middleware.PermMenuManage,
+	"GET /categories/:name":                 middleware.PermPublic,
+	"GET /categories/id/:id":                middleware.PermPublic,
+	"POST /categories/id/:id/restore":       middleware.PermMenuManage,
+	"PUT /categories/id/:id/image":          middleware.PermMenuManage,
+	"DELETE /categories/id/:id/image":       middleware.PermMenuManage,
+	"PUT /categories/:name":                 middleware.PermMenuManage,
+	"DELETE /categories/:name":              middleware.PermMenuManage,
+	"GET /categories/:name/id":              middleware.PermPublic,
+}
+
+// menuRateLimits puts menu and category routes under the loose browse policy, since guests page through them
+var menuRateLimits = middleware.RouteRateLimits{
+	"/menu":       middleware.PolicyBrowse,
+	"/categories": middleware.PolicyBrowse,
 }
 
-// NewMenuHandler creates a new menu handler
-func NewMenuHandler(svc MenuService) *MenuHandler {
-	return &MenuHandler{svc: svc}
+// menuCacheControl declares the Cache-Control of menu and category reads. Public reads may be stored by shared
+// caches; staff reads only by the browser. Both are revalidated with their ETag on every use.
+var menuCacheControl = middleware.RouteCacheControl{
+	"GET /menu":                      middleware.CacheRevalidate,
+	"GET /menu/tree":                 middleware.CacheRevalidate,
+	"GET /menu/export":               middleware.CacheRevalidatePrivate,
+	"GET /menu/translations/missing": middleware.CacheRevalidatePrivate,
+	"GET /menu/:id":                  middleware.CacheRevalidate,
+	"GET /menu/category/:name":       middleware.CacheRevalidate,
+	"GET /menu/:id/prices":           middleware.CacheRevalidatePrivate,
+	"GET /menu/:id/price":            middleware.CacheRevalidate,
+	"GET /categories":                middleware.CacheRevalidate,
+	"GET /categories/:name":          middleware.CacheRevalidate,
+	"GET /categories/id/:id":         middleware.CacheRevalidate,
+	"GET /categories/:name/id":       middleware.CacheRevalidate,
 }
 
 // RegisterRoutes registers all menu routes with the Gin router
 func (h *MenuHandler) RegisterRoutes(router *gin.Engine) {
-	menuGroup := router.Group("/menu")
+	if h.limiter != nil {
+		h.limiter.Attach(menuRateLimits)
+	}
+
+	menuGroup := router.Group("/menu", menuPermissions.Authorize(), menuCacheControl.Conditional("Accept-Language"))
 	{
 		menuGroup.GET("", h.ListMenuItems)
 		menuGroup.POST("", h.CreateMenuItem)
+		menuGroup.GET("/tree", h.GetMenuTree)
+		menuGroup.POST("/reorder", h.Reorder)
+		menuGroup.POST("/import", middleware.MaxBodySize(importUploadLimit), h.ImportMenu)
+		menuGroup.GET("/export", h.ExportMenu)
+		menuGroup.PUT("/translations", h.UpsertTranslations)
+		menuGroup.GET("/translations/missing", h.GetMissingTranslations)
 		menuGroup.GET("/:id", h.GetMenuItem)
 		menuGroup.GET("/category/:name", h.GetMenuItemsByCategory)
 		menuGroup.PUT("/:id", h.UpdateMenuItem)
 		menuGroup.DELETE("/:id", h.DeleteMenuItem)
+		menuGroup.POST("/:id/restore", h.RestoreMenuItem)
+		menuGroup.PUT("/:id/image", middleware.MaxBodySize(imageUploadLimit), h.UploadMenuItemImage)
+		menuGroup.DELETE("/:id/image", h.DeleteMenuItemImage)
+		menuGroup.DELETE("/:id/translations/:locale", h.DeleteMenuItemTranslation)
+		menuGroup.GET("/:id/prices", h.GetPriceHistory)
+		menuGroup.POST("/:id/prices", h.SchedulePriceChange)
+		menuGroup.DELETE("/:id/prices/:price_id", h.CancelPriceChange)
+		menuGroup.GET("/:id/price", h.GetPriceAt)
 	}
-	categoryGroup := router.Group("/categories")
+	categoryGroup := router.Group("/categories", menuPermissions.Authorize(), menuCacheControl.Conditional("Accept-Language"))
 	{
 		categoryGroup.GET("", h.ListCategories)
 		categoryGroup.POST("", h.CreateCategory)
 		categoryGroup.GET("/:name", h.GetCategoryByName)
 		categoryGroup.GET("/id/:id", h.GetCategoryByID)
+		categoryGroup.POST("/id/:id/restore", h.RestoreCategory)
+		categoryGroup.PUT("/id/:id/image", middleware.MaxBodySize(imageUploadLimit), h.UploadCategoryImage)
+		categoryGroup.DELETE("/id/:id/image", h.DeleteCategoryImage)
 		categoryGroup.PUT("/:name", h.UpdateCategory)
 		categoryGroup.DELETE("/:name", h.DeleteCategory)
 		categoryGroup.GET("/:name/id", h.CategoryIDByName)
@@ -94,6 +184,7 @@ func (h *MenuHandler) CreateMenuItem(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path string true "Menu Item ID (UUID)"
+// @Param lang query string false "Locale, overrides Accept-Language"
 // @Success 200 {object} MenuItem
 // @Failure 404 {object} middleware.ErrorResponse
 // @Failure 500 {object} middleware.ErrorResponse
@@ -110,6 +201,12 @@ func (h *MenuHandler) GetMenuItem(c *gin.Context) {
 		return
 	}
 
+	if err := h.svc.LocalizeMenuItems(c.Request.Context(), middleware.PreferredLocales(c), []*MenuItem{item}); err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	middleware.SetVersionETag(c, item.Version, item.Locale)
 	c.JSON(200, item)
 }
 
@@ -120,7 +217,11 @@ func (h *MenuHandler) GetMenuItem(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param name path string true "Category name"
+// @Param include_deleted query bool false "Include soft-deleted menu items"
+// @Param lang query string false "Locale, overrides Accept-Language"
 // @Success 200 {array} MenuItem
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
 // @Failure 404 {object} middleware.ErrorResponse
 // @Failure 500 {object} middleware.ErrorResponse
 // @Router /menu/category/{name} [get]
@@ -131,12 +232,22 @@ func (h *MenuHandler) GetMenuItemsByCategory(c *gin.Context) {
 		return
 	}
 
-	items, err := h.svc.GetMenuItemsByCategory(c.Request.Context(), name)
+	includeDeleted, ok := middleware.IncludeDeleted(c, middleware.PermMenuManage)
+	if !ok {
+		return
+	}
+
+	items, err := h.svc.GetMenuItemsByCategory(c.Request.Context(), name, includeDeleted)
 	if err != nil {
 		middleware.HandleError(c, err)
 		return
 	}
 
+	if err := h.svc.LocalizeMenuItems(c.Request.Context(), middleware.PreferredLocales(c), items); err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
 	c.JSON(200, items)
 }
 
@@ -149,8 +260,12 @@ func (h *MenuHandler) GetMenuItemsByCategory(c *gin.Context) {
 // @Param offset query int false "Offset (default 0)"
 // @Param limit query int false "Limit (default 10, max 100)"
 // @Param category query string false "Filter by category"
+// @Param include_deleted query bool false "Include soft-deleted menu items"
+// @Param lang query string false "Locale, overrides Accept-Language"
 // @Success 200 {array} MenuItem
 // @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
 // @Failure 500 {object} middleware.ErrorResponse
 // @Router /menu [get]
 func (h *MenuHandler) ListMenuItems(c *gin.Context) {
@@ -171,25 +286,108 @@ func (h *MenuHandler) ListMenuItems(c *gin.Context) {
 		return
 	}
 
-	items, err := h.svc.ListMenuItems(c.Request.Context(), req.Offset, req.Limit)
+	includeDeleted, ok := middleware.IncludeDeleted(c, middleware.PermMenuManage)
+	if !ok {
+		return
+	}
+
+	items, err := h.svc.ListMenuItems(c.Request.Context(), req.Offset, req.Limit, includeDeleted)
 	if err != nil {
 		middleware.HandleError(c, err)
 		return
 	}
 
+	if err := h.svc.LocalizeMenuItems(c.Request.Context(), middleware.PreferredLocales(c), items); err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
 	c.JSON(200, items)
 }
 
+// GetMenuTree handles GET /menu/tree
+// @Summary Get menu tree
+// @Description Get the full menu as nested categories with their menu items, in display order
+// @Tags Menu
+// @Accept json
+// @Produce json
+// @Param lang query string false "Locale, overrides Accept-Language"
+// @Success 200 {array} CategoryNode
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /menu/tree [get]
+func (h *MenuHandler) GetMenuTree(c *gin.Context) {
+	tree, err := h.svc.GetMenuTree(c.Request.Context())
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	if err := h.svc.LocalizeMenuTree(c.Request.Context(), middleware.PreferredLocales(c), tree); err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.JSON(200, tree)
+}
+
+// Reorder handles POST /menu/reorder
+// @Summary Reorder categories and menu items
+// @Description Apply a batch of moves that reparent categories, move items between categories and set display order
+// @Tags Menu
+// @Accept json
+// @Produce json
+// @Param request body ReorderRequest true "Batch of moves"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /menu/reorder [post]
+func (h *MenuHandler) Reorder(c *gin.Context) {
+	var req ReorderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	if err := ValidateReorder(req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	moves := make([]Move, len(req.Moves))
+	for i, m := range req.Moves {
+		moves[i] = Move{
+			Type:       MoveType(m.Type),
+			ID:         m.ID,
+			ParentID:   m.ParentID,
+			CategoryID: m.CategoryID,
+			SortOrder:  m.SortOrder,
+		}
+	}
+
+	if err := h.svc.Reorder(c.Request.Context(), moves); err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "Menu reordered successfully"})
+}
+
 // UpdateMenuItem handles PUT /menu/:id
 // @Summary Update menu item
-// @Description Update an existing menu item
+// @Description Update an existing menu item. The version read is sent as If-Match, or as version in the body; if the item has changed since, nothing is updated.
 // @Tags Menu
 // @Accept json
 // @Produce json
 // @Param id path string true "Menu Item ID (UUID)"
+// @Param If-Match header string false "ETag of the version being updated, from the last read"
 // @Param request body UpdateMenuItemRequest true "Menu item update request"
-// @Success 200 {object} map[string]string
+// @Success 200 {object} MenuItem
 // @Failure 400 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 409 {object} middleware.ErrorResponse "Body version is out of date"
+// @Failure 412 {object} middleware.ErrorResponse "If-Match is out of date"
+// @Failure 428 {object} middleware.ErrorResponse "Neither If-Match nor a body version was sent"
 // @Failure 500 {object} middleware.ErrorResponse
 // @Router /menu/{id} [put]
 func (h *MenuHandler) UpdateMenuItem(c *gin.Context) {
@@ -214,19 +412,24 @@ func (h *MenuHandler) UpdateMenuItem(c *gin.Context) {
 		return
 	}
 
-	var err error
-	err = h.svc.UpdateMenuItem(c.Request.Context(), id, req.Name, req.Description, req.Category, req.Price, ItemStatus(req.Status))
+	precondition, ok := middleware.RequireVersion(c, req.Version)
+	if !ok {
+		return
+	}
+
+	item, err := h.svc.UpdateMenuItem(c.Request.Context(), id, req.Name, req.Description, req.Category, req.Price, ItemStatus(req.Status), precondition.Version)
 	if err != nil {
-		middleware.HandleError(c, err)
+		precondition.HandleError(c, err)
 		return
 	}
 
-	c.JSON(200, gin.H{"message": "Menu item updated successfully"})
+	middleware.SetVersionETag(c, item.Version, "")
+	c.JSON(200, item)
 }
 
 // DeleteMenuItem handles DELETE /menu/:id
 // @Summary Delete menu item
-// @Description Delete a menu item
+// @Description Soft delete a menu item; it disappears from listings but past orders still resolve it
 // @Tags Menu
 // @Accept json
 // @Produce json
@@ -250,22 +453,63 @@ func (h *MenuHandler) DeleteMenuItem(c *gin.Context) {
 	c.JSON(204, gin.H{"message": "Menu item deleted successfully"})
 }
 
+// RestoreMenuItem handles POST /menu/:id/restore
+// @Summary Restore menu item
+// @Description Restore a soft-deleted menu item
+// @Tags Menu
+// @Accept json
+// @Produce json
+// @Param id path string true "Menu Item ID (UUID)"
+// @Success 200 {object} MenuItem
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 409 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /menu/{id}/restore [post]
+func (h *MenuHandler) RestoreMenuItem(c *gin.Context) {
+	id, ok := middleware.UUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	item, err := h.svc.RestoreMenuItem(c.Request.Context(), id)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.JSON(200, item)
+}
+
 // ListCategories handles GET /menu/categories
 // @Summary List categories
 // @Description List all menu categories
 // @Tags Menu
 // @Accept json
 // @Produce json
+// @Param include_deleted query bool false "Include soft-deleted categories"
+// @Param lang query string false "Locale, overrides Accept-Language"
 // @Success 200 {array} Category
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
 // @Failure 500 {object} middleware.ErrorResponse
 // @Router /menu/categories [get]
 func (h *MenuHandler) ListCategories(c *gin.Context) {
-	categories, err := h.svc.ListCategories(c.Request.Context())
+	includeDeleted, ok := middleware.IncludeDeleted(c, middleware.PermMenuManage)
+	if !ok {
+		return
+	}
+
+	categories, err := h.svc.ListCategories(c.Request.Context(), includeDeleted)
 	if err != nil {
 		middleware.HandleError(c, err)
 		return
 	}
 
+	if err := h.svc.LocalizeCategories(c.Request.Context(), middleware.PreferredLocales(c), categories); err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
 	c.JSON(200, categories)
 }
 
@@ -305,6 +549,7 @@ func (h *MenuHandler) GetCategoryByName(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path string true "Category ID (UUID)"
+// @Param lang query string false "Locale, overrides Accept-Language"
 // @Success 200 {object} Category
 // @Failure 400 {object} middleware.ErrorResponse
 // @Failure 404 {object} middleware.ErrorResponse
@@ -322,6 +567,40 @@ func (h *MenuHandler) GetCategoryByID(c *gin.Context) {
 		return
 	}
 
+	categories := []Category{*category}
+	if err := h.svc.LocalizeCategories(c.Request.Context(), middleware.PreferredLocales(c), categories); err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	middleware.SetVersionETag(c, categories[0].Version, categories[0].Locale)
+	c.JSON(200, categories[0])
+}
+
+// RestoreCategory handles POST /categories/id/:id/restore
+// @Summary Restore category
+// @Description Restore a soft-deleted category
+// @Tags Menu
+// @Accept json
+// @Produce json
+// @Param id path string true "Category ID (UUID)"
+// @Success 200 {object} Category
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 409 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /categories/id/{id}/restore [post]
+func (h *MenuHandler) RestoreCategory(c *gin.Context) {
+	id, ok := middleware.UUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	category, err := h.svc.RestoreCategory(c.Request.Context(), id)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
 	c.JSON(200, category)
 }
 
@@ -348,7 +627,7 @@ func (h *MenuHandler) CreateCategory(c *gin.Context) {
 		return
 	}
 
-	category, err := h.svc.CreateCategory(c.Request.Context(), req.Name)
+	category, err := h.svc.CreateCategory(c.Request.Context(), req.Name, req.ParentID, req.SortOrder)
 	if err != nil {
 		middleware.HandleError(c, err)
 		return
@@ -359,15 +638,19 @@ func (h *MenuHandler) CreateCategory(c *gin.Context) {
 
 // UpdateCategory handles PUT /menu/categories/:name
 // @Summary Update category
-// @Description Update an existing category
+// @Description Update an existing category. The version read is sent as If-Match, or as version in the body; if the category has changed since, nothing is updated.
 // @Tags Menu
 // @Accept json
 // @Produce json
 // @Param name path string true "Current category name"
+// @Param If-Match header string false "ETag of the version being updated, from the last read"
 // @Param request body UpdateCategoryRequest true "Category update request"
 // @Success 200 {object} Category
 // @Failure 400 {object} middleware.ErrorResponse
 // @Failure 404 {object} middleware.ErrorResponse
+// @Failure 409 {object} middleware.ErrorResponse "Body version is out of date, or the name is taken"
+// @Failure 412 {object} middleware.ErrorResponse "If-Match is out of date"
+// @Failure 428 {object} middleware.ErrorResponse "Neither If-Match nor a body version was sent"
 // @Failure 500 {object} middleware.ErrorResponse
 // @Router /menu/categories/{name} [put]
 func (h *MenuHandler) UpdateCategory(c *gin.Context) {
@@ -385,18 +668,24 @@ func (h *MenuHandler) UpdateCategory(c *gin.Context) {
 		return
 	}
 
-	category, err := h.svc.UpdateCategory(c.Request.Context(), old_name, req.Name)
+	precondition, ok := middleware.RequireVersion(c, req.Version)
+	if !ok {
+		return
+	}
+
+	category, err := h.svc.UpdateCategory(c.Request.Context(), old_name, req.Name, precondition.Version)
 	if err != nil {
-		middleware.HandleError(c, err)
+		precondition.HandleError(c, err)
 		return
 	}
 
+	middleware.SetVersionETag(c, category.Version, "")
 	c.JSON(200, category)
 }
 
 // DeleteCategory handles DELETE /menu/categories/:name
 // @Summary Delete category
-// @Description Delete a menu category
+// @Description Soft delete a menu category that no longer holds live items or subcategories
 // @Tags Menu
 // @Accept json
 // @Produce json
@@ -444,3 +733,505 @@ func (h *MenuHandler) CategoryIDByName(c *gin.Context) {
 	}
 	c.JSON(200, gin.H{"id": id})
 }
+
+// openUploadedImage opens the "image" file of a multipart upload
+func openUploadedImage(c *gin.Context) (multipart.File, bool) {
+	header, err := c.FormFile("image")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if stderrors.As(err, &maxBytesErr) {
+			middleware.HandleError(c, errors.ErrPayloadTooLarge)
+			return nil, false
+		}
+		middleware.HandleError(c, errors.NewValidationError("image file is required: "+err.Error()))
+		return nil, false
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		middleware.HandleError(c, errors.NewValidationError("failed to open image: "+err.Error()))
+		return nil, false
+	}
+	return file, true
+}
+
+// UploadMenuItemImage handles PUT /menu/:id/image
+// @Summary Upload menu item image
+// @Description Upload a JPEG, PNG or GIF photo for a menu item; a thumbnail is generated automatically
+// @Tags Menu
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Menu Item ID (UUID)"
+// @Param image formData file true "Image file"
+// @Success 200 {object} MenuItem
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 413 {object} middleware.ErrorResponse
+// @Failure 415 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /menu/{id}/image [put]
+func (h *MenuHandler) UploadMenuItemImage(c *gin.Context) {
+	id, ok := middleware.UUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	file, ok := openUploadedImage(c)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	item, err := h.svc.UploadMenuItemImage(c.Request.Context(), id, file)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.JSON(200, item)
+}
+
+// DeleteMenuItemImage handles DELETE /menu/:id/image
+// @Summary Delete menu item image
+// @Description Remove the photo of a menu item
+// @Tags Menu
+// @Accept json
+// @Produce json
+// @Param id path string true "Menu Item ID (UUID)"
+// @Success 204 "No Content"
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /menu/{id}/image [delete]
+func (h *MenuHandler) DeleteMenuItemImage(c *gin.Context) {
+	id, ok := middleware.UUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.svc.DeleteMenuItemImage(c.Request.Context(), id); err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.Status(204)
+}
+
+// UploadCategoryImage handles PUT /categories/id/:id/image
+// @Summary Upload category image
+// @Description Upload a JPEG, PNG or GIF image for a category; a thumbnail is generated automatically
+// @Tags Menu
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Category ID (UUID)"
+// @Param image formData file true "Image file"
+// @Success 200 {object} Category
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 413 {object} middleware.ErrorResponse
+// @Failure 415 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /categories/id/{id}/image [put]
+func (h *MenuHandler) UploadCategoryImage(c *gin.Context) {
+	id, ok := middleware.UUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	file, ok := openUploadedImage(c)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	category, err := h.svc.UploadCategoryImage(c.Request.Context(), id, file)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.JSON(200, category)
+}
+
+// DeleteCategoryImage handles DELETE /categories/id/:id/image
+// @Summary Delete category image
+// @Description Remove the image of a category
+// @Tags Menu
+// @Accept json
+// @Produce json
+// @Param id path string true "Category ID (UUID)"
+// @Success 204 "No Content"
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /categories/id/{id}/image [delete]
+func (h *MenuHandler) DeleteCategoryImage(c *gin.Context) {
+	id, ok := middleware.UUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.svc.DeleteCategoryImage(c.Request.Context(), id); err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.Status(204)
+}
+
+// UpsertTranslations handles PUT /menu/translations
+// @Summary Bulk upsert translations
+// @Description Create or replace menu item and category translations in one transaction
+// @Tags Menu
+// @Accept json
+// @Produce json
+// @Param request body UpsertTranslationsRequest true "Translations"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /menu/translations [put]
+func (h *MenuHandler) UpsertTranslations(c *gin.Context) {
+	var req UpsertTranslationsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	if err := ValidateUpsertTranslations(req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	items := make([]MenuItemTranslation, len(req.MenuItems))
+	for i, t := range req.MenuItems {
+		items[i] = MenuItemTranslation{
+			MenuItemID:  t.MenuItemID,
+			Locale:      t.Locale,
+			Name:        strings.TrimSpace(t.Name),
+			Description: strings.TrimSpace(t.Description),
+		}
+	}
+	categories := make([]CategoryTranslation, len(req.Categories))
+	for i, t := range req.Categories {
+		categories[i] = CategoryTranslation{
+			CategoryID: t.CategoryID,
+			Locale:     t.Locale,
+			Name:       strings.TrimSpace(t.Name),
+		}
+	}
+
+	if err := h.svc.UpsertTranslations(c.Request.Context(), items, categories); err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.JSON(200, gin.H{"menu_items": len(items), "categories": len(categories)})
+}
+
+// GetMissingTranslations handles GET /menu/translations/missing
+// @Summary Report missing translations
+// @Description List live menu items and categories that have no translation for a locale
+// @Tags Menu
+// @Accept json
+// @Produce json
+// @Param locale query string true "Locale, e.g. fr or pt-BR"
+// @Success 200 {object} MissingTranslations
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /menu/translations/missing [get]
+func (h *MenuHandler) GetMissingTranslations(c *gin.Context) {
+	locale, ok := middleware.GetStringQueryParam(c, "locale", true)
+	if !ok {
+		return
+	}
+
+	if err := ValidateLocale(locale); err != nil {
+		middleware.HandleError(c, errors.NewValidationError("invalid locale: "+locale))
+		return
+	}
+
+	missing, err := h.svc.GetMissingTranslations(c.Request.Context(), locale)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.JSON(200, missing)
+}
+
+// DeleteMenuItemTranslation handles DELETE /menu/:id/translations/:locale
+// @Summary Delete menu item translation
+// @Description Delete one locale of a menu item's translations
+// @Tags Menu
+// @Accept json
+// @Produce json
+// @Param id path string true "Menu Item ID (UUID)"
+// @Param locale path string true "Locale"
+// @Success 204 "No Content"
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /menu/{id}/translations/{locale} [delete]
+func (h *MenuHandler) DeleteMenuItemTranslation(c *gin.Context) {
+	id, ok := middleware.UUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.svc.DeleteMenuItemTranslation(c.Request.Context(), id, c.Param("locale")); err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.Status(204)
+}
+
+// ImportMenu handles POST /menu/import
+// @Summary Bulk import menu items
+// @Description Import menu items from CSV (columns name, description, price, category, status) or JSON.
+// @Description Every row is validated like POST /menu; rows are only applied if all of them are valid, in one transaction,
+// @Description and missing categories are created. With dry_run=true nothing is written and the per-row report is returned.
+// @Tags Menu
+// @Accept json
+// @Accept text/csv
+// @Produce json
+// @Param format query string false "csv or json, defaults to the request Content-Type"
+// @Param dry_run query bool false "Validate and report without writing"
+// @Success 200 {object} ImportResult
+// @Success 201 {object} ImportResult
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 409 {object} middleware.ErrorResponse
+// @Failure 413 {object} middleware.ErrorResponse
+// @Failure 422 {object} ImportResult
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /menu/import [post]
+func (h *MenuHandler) ImportMenu(c *gin.Context) {
+	dryRun, ok := middleware.GetBoolQueryParam(c, "dry_run")
+	if !ok {
+		return
+	}
+
+	format, ok := transferFormat(c, strings.HasPrefix(c.ContentType(), "text/csv"))
+	if !ok {
+		return
+	}
+
+	rows, parseErrors, err := ParseMenuImport(format, c.Request.Body)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if stderrors.As(err, &maxBytesErr) {
+			middleware.HandleError(c, errors.ErrPayloadTooLarge)
+			return
+		}
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	if rowErrors := ValidateImportRows(rows, parseErrors); len(rowErrors) > 0 {
+		result := &ImportResult{DryRun: dryRun, Rows: len(rows), CategoriesCreated: []string{}, Errors: rowErrors}
+		if dryRun {
+			c.JSON(200, result)
+			return
+		}
+		c.JSON(http.StatusUnprocessableEntity, result)
+		return
+	}
+
+	result, err := h.svc.ImportMenu(c.Request.Context(), rows, dryRun)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	if dryRun {
+		c.JSON(200, result)
+		return
+	}
+	c.JSON(201, result)
+}
+
+// ExportMenu handles GET /menu/export
+// @Summary Export menu items
+// @Description Export every live menu item in the format accepted by POST /menu/import
+// @Tags Menu
+// @Produce json
+// @Produce text/csv
+// @Param format query string false "csv or json (default json)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /menu/export [get]
+func (h *MenuHandler) ExportMenu(c *gin.Context) {
+	format, ok := transferFormat(c, false)
+	if !ok {
+		return
+	}
+
+	rows, err := h.svc.ExportMenu(c.Request.Context())
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	if format == ImportFormatCSV {
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Header("Content-Disposition", `attachment; filename="menu.csv"`)
+	} else {
+		c.Header("Content-Type", "application/json; charset=utf-8")
+	}
+	c.Status(200)
+	if err := WriteMenuExport(format, c.Writer, rows); err != nil {
+		_ = c.Error(err)
+	}
+}
+
+// transferFormat reads the format query parameter, falling back to CSV when isCSV is set and JSON otherwise
+func transferFormat(c *gin.Context, isCSV bool) (ImportFormat, bool) {
+	switch strings.ToLower(c.Query("format")) {
+	case "":
+		if isCSV {
+			return ImportFormatCSV, true
+		}
+		return ImportFormatJSON, true
+	case string(ImportFormatCSV):
+		return ImportFormatCSV, true
+	case string(ImportFormatJSON):
+		return ImportFormatJSON, true
+	default:
+		middleware.HandleError(c, errors.NewValidationError("format must be csv or json"))
+		return "", false
+	}
+}
+
+// GetPriceHistory handles GET /menu/:id/prices
+// @Summary Get menu item price history
+// @Description List past, current and scheduled prices of a menu item, oldest first
+// @Tags Menu
+// @Accept json
+// @Produce json
+// @Param id path string true "Menu Item ID (UUID)"
+// @Success 200 {array} MenuItemPrice
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /menu/{id}/prices [get]
+func (h *MenuHandler) GetPriceHistory(c *gin.Context) {
+	id, ok := middleware.UUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	prices, err := h.svc.GetPriceHistory(c.Request.Context(), id)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.JSON(200, prices)
+}
+
+// SchedulePriceChange handles POST /menu/:id/prices
+// @Summary Schedule a price change
+// @Description Schedule a future price for a menu item; it is applied automatically at effective_from
+// @Tags Menu
+// @Accept json
+// @Produce json
+// @Param id path string true "Menu Item ID (UUID)"
+// @Param request body SchedulePriceRequest true "Scheduled price"
+// @Success 201 {object} MenuItemPrice
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 409 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /menu/{id}/prices [post]
+func (h *MenuHandler) SchedulePriceChange(c *gin.Context) {
+	id, ok := middleware.UUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req SchedulePriceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	if err := ValidateSchedulePrice(req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	price, err := h.svc.SchedulePriceChange(c.Request.Context(), id, req.Price, req.EffectiveFrom)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.JSON(201, price)
+}
+
+// CancelPriceChange handles DELETE /menu/:id/prices/:price_id
+// @Summary Cancel a scheduled price change
+// @Description Remove a price change that has not taken effect yet
+// @Tags Menu
+// @Accept json
+// @Produce json
+// @Param id path string true "Menu Item ID (UUID)"
+// @Param price_id path string true "Price ID (UUID)"
+// @Success 204 "No Content"
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /menu/{id}/prices/{price_id} [delete]
+func (h *MenuHandler) CancelPriceChange(c *gin.Context) {
+	id, ok := middleware.UUIDParam(c, "id")
+	if !ok {
+		return
+	}
+	priceID, ok := middleware.UUIDParam(c, "price_id")
+	if !ok {
+		return
+	}
+
+	if err := h.svc.CancelPriceChange(c.Request.Context(), id, priceID); err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.Status(204)
+}
+
+// GetPriceAt handles GET /menu/:id/price
+// @Summary Get menu item price at a point in time
+// @Description Get the price of a menu item that was, is or will be in effect at the given time
+// @Tags Menu
+// @Accept json
+// @Produce json
+// @Param id path string true "Menu Item ID (UUID)"
+// @Param at query string false "RFC 3339 timestamp, defaults to now"
+// @Success 200 {object} MenuItemPrice
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /menu/{id}/price [get]
+func (h *MenuHandler) GetPriceAt(c *gin.Context) {
+	id, ok := middleware.UUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	at := time.Now()
+	if value := c.Query("at"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			middleware.HandleError(c, errors.NewValidationError("at must be an RFC 3339 timestamp"))
+			return
+		}
+		at = parsed
+	}
+
+	price, err := h.svc.GetPriceAt(c.Request.Context(), id, at)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.JSON(200, price)
+}
@@ -7,13 +7,19 @@ import (
 )
 
 type MenuItem struct {
-	ID                uuid.UUID  `json:"id"`                  // unique menu item ID
-	Name              string     `json:"name"`                // name of the menu item
-	Description       string     `json:"description"`         // description of the menu item
-	Price             float64    `json:"price"`               // price of the menu item
-	CategoryID        uuid.UUID  `json:"category_id"`         // category of the menu item
-	AvalabilityStatus ItemStatus `json:"availability_status"` // status of the menu item in stock (e.g., "in_stock", "out_of_stock")
-	CreatedAt         time.Time  `json:"created_at"`          // when the menu item was created
+	ID                uuid.UUID  `json:"id"`                      // unique menu item ID
+	Name              string     `json:"name"`                    // name of the menu item
+	Description       string     `json:"description"`             // description of the menu item
+	Price             float64    `json:"price"`                   // price of the menu item
+	CategoryID        uuid.UUID  `json:"category_id"`             // category of the menu item
+	AvalabilityStatus ItemStatus `json:"availability_status"`     // status of the menu item in stock (e.g., "in_stock", "out_of_stock")
+	SortOrder         int        `json:"sort_order"`              // display position within its category
+	ImageURL          string     `json:"image_url,omitempty"`     // public URL of the item photo, empty if none
+	ThumbnailURL      string     `json:"thumbnail_url,omitempty"` // public URL of the item photo thumbnail, empty if none
+	Locale            string     `json:"locale,omitempty"`        // locale the name and description are in, set on localized responses
+	CreatedAt         time.Time  `json:"created_at"`              // when the menu item was created
+	DeletedAt         *time.Time `json:"deleted_at,omitempty"`    // when the menu item was soft deleted, nil if live
+	Version           int        `json:"version"`                 // bumped by every write, for optimistic concurrency
 }
 
 type ItemStatus string
@@ -29,6 +35,101 @@ type category struct {
 }
 
 type Category struct {
-	ID   uuid.UUID `json:"id"`   // unique category ID
-	Name string    `json:"name"` // name of the category
+	ID           uuid.UUID  `json:"id"`                      // unique category ID
+	Name         string     `json:"name"`                    // name of the category
+	ParentID     *uuid.UUID `json:"parent_id"`               // parent category ID, nil for top-level categories
+	SortOrder    int        `json:"sort_order"`              // display position among sibling categories
+	ImageURL     string     `json:"image_url,omitempty"`     // public URL of the category image, empty if none
+	ThumbnailURL string     `json:"thumbnail_url,omitempty"` // public URL of the category image thumbnail, empty if none
+	Locale       string     `json:"locale,omitempty"`        // locale the name is in, set on localized responses
+	DeletedAt    *time.Time `json:"deleted_at,omitempty"`    // when the category was soft deleted, nil if live
+	Version      int        `json:"version"`                 // bumped by every write, for optimistic concurrency
+}
+
+// CategoryNode is a category with its subcategories and menu items, used for tree-shaped menu responses
+type CategoryNode struct {
+	Category
+	Children []*CategoryNode `json:"children"` // subcategories in display order
+	Items    []*MenuItem     `json:"items"`    // menu items in display order
+}
+
+// MoveType identifies what kind of entity a reorder move applies to
+type MoveType string
+
+const (
+	MoveTypeCategory MoveType = "category"
+	MoveTypeMenuItem MoveType = "menu_item"
+)
+
+// Move repositions a category or menu item. For categories ParentID is the new parent (nil for top level);
+// for menu items CategoryID is the new category (nil keeps the current one).
+type Move struct {
+	Type       MoveType   `json:"type"`
+	ID         uuid.UUID  `json:"id"`
+	ParentID   *uuid.UUID `json:"parent_id,omitempty"`
+	CategoryID *uuid.UUID `json:"category_id,omitempty"`
+	SortOrder  int        `json:"sort_order"`
+}
+
+// MenuItemTranslation holds a menu item's name and description in one locale
+type MenuItemTranslation struct {
+	MenuItemID  uuid.UUID `json:"menu_item_id"`
+	Locale      string    `json:"locale"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+}
+
+// CategoryTranslation holds a category's name in one locale
+type CategoryTranslation struct {
+	CategoryID uuid.UUID `json:"category_id"`
+	Locale     string    `json:"locale"`
+	Name       string    `json:"name"`
+}
+
+// TranslationGap identifies a menu item or category without a translation
+type TranslationGap struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"` // name in the default language
+}
+
+// MissingTranslations reports what still needs translating into a locale
+type MissingTranslations struct {
+	Locale     string           `json:"locale"`
+	MenuItems  []TranslationGap `json:"menu_items"`
+	Categories []TranslationGap `json:"categories"`
+}
+
+// ImportFormat is the wire format of a bulk menu import or export
+type ImportFormat string
+
+const (
+	ImportFormatCSV  ImportFormat = "csv"
+	ImportFormatJSON ImportFormat = "json"
+)
+
+// ImportRowError lists what is wrong with one row of an import; Row is 1-based and excludes the CSV header
+type ImportRowError struct {
+	Row    int      `json:"row"`
+	Errors []string `json:"errors"`
+}
+
+// ImportResult reports the outcome of a bulk menu import
+type ImportResult struct {
+	DryRun            bool             `json:"dry_run"`
+	Applied           bool             `json:"applied"`            // true once every row has been committed
+	Rows              int              `json:"rows"`               // number of rows in the upload
+	ItemsCreated      int              `json:"items_created"`      // menu items created, or that would be created on a dry run
+	CategoriesCreated []string         `json:"categories_created"` // categories created, or that would be created on a dry run
+	Errors            []ImportRowError `json:"errors"`
+}
+
+// MenuItemPrice is the price of a menu item over [EffectiveFrom, EffectiveTo); a nil EffectiveTo is open-ended
+type MenuItemPrice struct {
+	ID            uuid.UUID  `json:"id"`
+	MenuItemID    uuid.UUID  `json:"menu_item_id"`
+	Price         float64    `json:"price"`
+	EffectiveFrom time.Time  `json:"effective_from"`
+	EffectiveTo   *time.Time `json:"effective_to"`
+	AppliedAt     *time.Time `json:"applied_at"` // when menu_items.price took this value, nil while the change is scheduled
+	CreatedAt     time.Time  `json:"created_at"`
 }
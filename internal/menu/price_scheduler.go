@@ -0,0 +1,74 @@
+package menu
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// PriceScheduler periodically applies scheduled menu price changes once they come into effect
+type PriceScheduler struct {
+	svc      MenuService
+	interval time.Duration
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewPriceScheduler creates a scheduler that checks for due price changes every interval
+func NewPriceScheduler(svc MenuService, interval time.Duration) *PriceScheduler {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	return &PriceScheduler{
+		svc:      svc,
+		interval: interval,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start runs the scheduler in the background. Changes that fell due while the server was down are applied right away.
+func (p *PriceScheduler) Start() {
+	go p.run()
+}
+
+// Stop halts the scheduler and waits for an in-flight run to finish
+func (p *PriceScheduler) Stop(ctx context.Context) error {
+	close(p.stop)
+	select {
+	case <-p.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (p *PriceScheduler) run() {
+	defer close(p.done)
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		p.applyDue()
+		select {
+		case <-ticker.C:
+		case <-p.stop:
+			return
+		}
+	}
+}
+
+func (p *PriceScheduler) applyDue() {
+	ctx, cancel := context.WithTimeout(context.Background(), p.interval)
+	defer cancel()
+
+	applied, err := p.svc.ApplyDuePrices(ctx)
+	if err != nil {
+		log.Printf("Price scheduler: %v", err)
+		return
+	}
+	if applied > 0 {
+		log.Printf("Price scheduler: applied %d scheduled price change(s)", applied)
+	}
+}
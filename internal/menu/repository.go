@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"restaurant/internal/errors"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/lib/pq"
@@ -17,35 +18,87 @@ type MenuRepository interface {
 	// GetMenuItem retrieves a menu item by ID
 	GetMenuItem(ctx context.Context, id uuid.UUID) (*MenuItem, error)
 
-	// ListMenuItems lists all menu items
-	ListMenuItems(ctx context.Context, offset int, limit int) ([]*MenuItem, error)
+	// ListMenuItems lists menu items, optionally including soft-deleted ones
+	ListMenuItems(ctx context.Context, offset int, limit int, includeDeleted bool) ([]*MenuItem, error)
 
-	// GetMenuItemsByCategory retrieves menu items by category
-	GetMenuItemsByCategory(ctx context.Context, category string) ([]*MenuItem, error)
+	// GetMenuItemsByCategory retrieves menu items by category, optionally including soft-deleted ones
+	GetMenuItemsByCategory(ctx context.Context, category string, includeDeleted bool) ([]*MenuItem, error)
 
-	// UpdateMenuItem updates a menu item
-	UpdateMenuItem(ctx context.Context, item *MenuItem) error
+	// UpdateMenuItem updates a menu item if it is still at item.Version, which is then set to the new version.
+	// A non-nil price is recorded in the price history in the same transaction.
+	UpdateMenuItem(ctx context.Context, item *MenuItem, price *MenuItemPrice) error
 
-	// DeleteMenuItem deletes a menu item by ID
+	// DeleteMenuItem soft deletes a menu item by ID
 	DeleteMenuItem(ctx context.Context, id uuid.UUID) error
 
-	// ListCategories lists all unique categories
-	ListCategories(ctx context.Context) ([]Category, error)
+	// RestoreMenuItem clears the soft delete marker of a menu item
+	RestoreMenuItem(ctx context.Context, id uuid.UUID) error
+
+	// ListCategories lists categories, optionally including soft-deleted ones
+	ListCategories(ctx context.Context, includeDeleted bool) ([]Category, error)
 
 	// CreateCategory creates a new category
-	CreateCategory(ctx context.Context, name string, id uuid.UUID) error
+	CreateCategory(ctx context.Context, category *Category) error
 
 	// GetCategoryByID retrieves a category by ID
 	GetCategoryByID(ctx context.Context, id uuid.UUID) (*Category, error)
 
-	// DeleteCategory deletes a category
+	// DeleteCategory soft deletes a category
 	DeleteCategory(ctx context.Context, name string) error
 
-	UpdateCategory(ctx context.Context, old_name string, new_name string) error
+	// RestoreCategory clears the soft delete marker of a category
+	RestoreCategory(ctx context.Context, id uuid.UUID) error
+
+	UpdateCategory(ctx context.Context, old_name string, new_name string, version int) error
 
 	CategoryIDByName(ctx context.Context, name string) (uuid.UUID, error)
 
 	CategoryIDByNameCreateIfNotPresent(ctx context.Context, name string) (uuid.UUID, error)
+
+	// SetMenuItemImage stores the image URLs of a menu item, empty strings clear them
+	SetMenuItemImage(ctx context.Context, id uuid.UUID, imageURL string, thumbnailURL string) error
+
+	// SetCategoryImage stores the image URLs of a category, empty strings clear them
+	SetCategoryImage(ctx context.Context, id uuid.UUID, imageURL string, thumbnailURL string) error
+
+	// GetMenuItemTranslations retrieves translations of the given menu items in any of the given locales
+	GetMenuItemTranslations(ctx context.Context, itemIDs []uuid.UUID, locales []string) ([]MenuItemTranslation, error)
+
+	// GetCategoryTranslations retrieves translations of the given categories in any of the given locales
+	GetCategoryTranslations(ctx context.Context, categoryIDs []uuid.UUID, locales []string) ([]CategoryTranslation, error)
+
+	// UpsertTranslations creates or replaces menu item and category translations in a single transaction
+	UpsertTranslations(ctx context.Context, items []MenuItemTranslation, categories []CategoryTranslation) error
+
+	// DeleteMenuItemTranslation deletes one locale of a menu item's translations
+	DeleteMenuItemTranslation(ctx context.Context, itemID uuid.UUID, locale string) error
+
+	// ListMissingTranslations lists live menu items and categories with no translation in the locale
+	ListMissingTranslations(ctx context.Context, locale string) (*MissingTranslations, error)
+
+	// ListAllMenuItems lists every live menu item in display order
+	ListAllMenuItems(ctx context.Context) ([]*MenuItem, error)
+
+	// ApplyMoves repositions categories and menu items in a single transaction
+	ApplyMoves(ctx context.Context, moves []Move) error
+
+	// ImportMenu creates the given categories and menu items in a single transaction
+	ImportMenu(ctx context.Context, categories []*Category, items []*MenuItem) error
+
+	// AddMenuItemPrice inserts a price into a live menu item's price timeline
+	AddMenuItemPrice(ctx context.Context, price *MenuItemPrice) error
+
+	// ListMenuItemPrices lists the price timeline of a menu item, oldest first
+	ListMenuItemPrices(ctx context.Context, itemID uuid.UUID) ([]MenuItemPrice, error)
+
+	// GetMenuItemPriceAt retrieves the price of a menu item in effect at a point in time
+	GetMenuItemPriceAt(ctx context.Context, itemID uuid.UUID, at time.Time) (*MenuItemPrice, error)
+
+	// DeleteScheduledPrice removes a price change that has not been applied yet
+	DeleteScheduledPrice(ctx context.Context, itemID uuid.UUID, priceID uuid.UUID) error
+
+	// ApplyDuePrices copies prices that have come into effect onto their menu items
+	ApplyDuePrices(ctx context.Context, now time.Time) (int, error)
 }
 
 // postgresMenuRepository implements MenuRepository using PostgreSQL
@@ -60,15 +113,34 @@ func NewMenuRepository(db *sql.DB) MenuRepository {
 
 // Implementations (stubs for now)
 func (r *postgresMenuRepository) CreateMenuItem(ctx context.Context, item *MenuItem) error {
-	_, err := r.db.ExecContext(ctx, "INSERT INTO menu_items (id, name, description, price, avalability_status, category, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7)",
-		item.ID, item.Name, item.Description, item.Price, item.AvalabilityStatus, item.CategoryID, item.CreatedAt)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.NewInternalError("failed to begin transaction", err)
+	}
+	defer tx.Rollback()
+
+	if err := insertMenuItem(ctx, tx, item); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// insertMenuItem inserts a menu item together with the first entry of its price history
+func insertMenuItem(ctx context.Context, tx *sql.Tx, item *MenuItem) error {
+	_, err := tx.ExecContext(ctx, "INSERT INTO menu_items (id, name, description, price, avalability_status, category, sort_order, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)",
+		item.ID, item.Name, item.Description, item.Price, item.AvalabilityStatus, item.CategoryID, item.SortOrder, item.CreatedAt)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, "INSERT INTO menu_item_prices (id, menu_item_id, price, effective_from, applied_at) VALUES ($1, $2, $3, $4, $4)",
+		uuid.New(), item.ID, item.Price, item.CreatedAt)
 	return err
 }
 
 func (r *postgresMenuRepository) GetMenuItem(ctx context.Context, id uuid.UUID) (*MenuItem, error) {
 	var item MenuItem
-	err := r.db.QueryRowContext(ctx, "SELECT id, name, description, price, avalability_status, category, created_at FROM menu_items WHERE id = $1", id).Scan(
-		&item.ID, &item.Name, &item.Description, &item.Price, &item.AvalabilityStatus, &item.CategoryID, &item.CreatedAt)
+	err := r.db.QueryRowContext(ctx, "SELECT id, name, description, price, avalability_status, category, sort_order, image_url, thumbnail_url, created_at, deleted_at, version FROM menu_items WHERE id = $1", id).Scan(
+		&item.ID, &item.Name, &item.Description, &item.Price, &item.AvalabilityStatus, &item.CategoryID, &item.SortOrder, &item.ImageURL, &item.ThumbnailURL, &item.CreatedAt, &item.DeletedAt, &item.Version)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, errors.ErrMenuItemNotFound
@@ -78,9 +150,9 @@ func (r *postgresMenuRepository) GetMenuItem(ctx context.Context, id uuid.UUID)
 	return &item, nil
 }
 
-func (r *postgresMenuRepository) ListMenuItems(ctx context.Context, offset int, limit int) ([]*MenuItem, error) {
+func (r *postgresMenuRepository) ListMenuItems(ctx context.Context, offset int, limit int, includeDeleted bool) ([]*MenuItem, error) {
 	// TODO: Consider adding pagination (limit, offset) and availability filter for production use
-	rows, err := r.db.QueryContext(ctx, "SELECT id, name, description, price, avalability_status, category, created_at FROM menu_items ORDER BY created_at DESC OFFSET $1 LIMIT $2", offset, limit)
+	rows, err := r.db.QueryContext(ctx, "SELECT id, name, description, price, avalability_status, category, sort_order, image_url, thumbnail_url, created_at, deleted_at, version FROM menu_items WHERE ($3 OR deleted_at IS NULL) ORDER BY created_at DESC OFFSET $1 LIMIT $2", offset, limit, includeDeleted)
 	if err != nil {
 		return nil, err
 	}
@@ -89,7 +161,7 @@ func (r *postgresMenuRepository) ListMenuItems(ctx context.Context, offset int,
 	var items []*MenuItem
 	for rows.Next() {
 		var item MenuItem
-		err := rows.Scan(&item.ID, &item.Name, &item.Description, &item.Price, &item.AvalabilityStatus, &item.CategoryID, &item.CreatedAt)
+		err := rows.Scan(&item.ID, &item.Name, &item.Description, &item.Price, &item.AvalabilityStatus, &item.CategoryID, &item.SortOrder, &item.ImageURL, &item.ThumbnailURL, &item.CreatedAt, &item.DeletedAt, &item.Version)
 		if err != nil {
 			return nil, err
 		}
@@ -101,14 +173,14 @@ func (r *postgresMenuRepository) ListMenuItems(ctx context.Context, offset int,
 	return items, nil
 }
 
-func (r *postgresMenuRepository) GetMenuItemsByCategory(ctx context.Context, category string) ([]*MenuItem, error) {
+func (r *postgresMenuRepository) GetMenuItemsByCategory(ctx context.Context, category string, includeDeleted bool) ([]*MenuItem, error) {
 	// First get the category ID by name
 	categoryID, err := r.CategoryIDByName(ctx, category)
 	if err != nil {
 		return nil, err
 	}
 
-	rows, err := r.db.QueryContext(ctx, "SELECT id, name, description, price, avalability_status, category, created_at FROM menu_items WHERE category = $1", categoryID)
+	rows, err := r.db.QueryContext(ctx, "SELECT id, name, description, price, avalability_status, category, sort_order, image_url, thumbnail_url, created_at, deleted_at, version FROM menu_items WHERE category = $1 AND ($2 OR deleted_at IS NULL) ORDER BY sort_order, name", categoryID, includeDeleted)
 	if err != nil {
 		return nil, err
 	}
@@ -117,7 +189,7 @@ func (r *postgresMenuRepository) GetMenuItemsByCategory(ctx context.Context, cat
 	var items []*MenuItem
 	for rows.Next() {
 		var item MenuItem
-		err := rows.Scan(&item.ID, &item.Name, &item.Description, &item.Price, &item.AvalabilityStatus, &item.CategoryID, &item.CreatedAt)
+		err := rows.Scan(&item.ID, &item.Name, &item.Description, &item.Price, &item.AvalabilityStatus, &item.CategoryID, &item.SortOrder, &item.ImageURL, &item.ThumbnailURL, &item.CreatedAt, &item.DeletedAt, &item.Version)
 		if err != nil {
 			return nil, err
 		}
@@ -129,14 +201,50 @@ func (r *postgresMenuRepository) GetMenuItemsByCategory(ctx context.Context, cat
 	return items, nil
 }
 
-func (r *postgresMenuRepository) UpdateMenuItem(ctx context.Context, item *MenuItem) error {
-	_, err := r.db.ExecContext(ctx, "UPDATE menu_items SET name = $1, description = $2, price = $3, avalability_status = $4, category = $5 WHERE id = $6",
-		item.Name, item.Description, item.Price, item.AvalabilityStatus, item.CategoryID, item.ID)
-	return err
+// UpdateMenuItem updates a menu item if it is still at item.Version, and sets item.Version to the new version.
+// When price is not nil the change is added to the price timeline in the same transaction, so the menu and
+// its history never disagree.
+func (r *postgresMenuRepository) UpdateMenuItem(ctx context.Context, item *MenuItem, price *MenuItemPrice) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.NewInternalError("failed to begin transaction", err)
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, "UPDATE menu_items SET version = version + 1, name = $1, description = $2, price = $3, avalability_status = $4, category = $5 WHERE id = $6 AND version = $7 RETURNING version",
+		item.Name, item.Description, item.Price, item.AvalabilityStatus, item.CategoryID, item.ID, item.Version).Scan(&item.Version)
+	if err == sql.ErrNoRows {
+		return r.versionMiss(ctx, "SELECT EXISTS (SELECT 1 FROM menu_items WHERE id = $1)", item.ID, errors.ErrMenuItemNotFound)
+	}
+	if err != nil {
+		return err
+	}
+
+	if price != nil {
+		if err := insertMenuItemPrice(ctx, tx, price); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// versionMiss explains why a versioned update changed nothing: notFound if the row is gone, otherwise
+// ErrVersionConflict because someone else updated it first
+func (r *postgresMenuRepository) versionMiss(ctx context.Context, existsQuery string, key any, notFound error) error {
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, existsQuery, key).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return notFound
+	}
+	return errors.ErrVersionConflict
 }
 
 func (r *postgresMenuRepository) DeleteMenuItem(ctx context.Context, id uuid.UUID) error {
-	result, err := r.db.ExecContext(ctx, "DELETE FROM menu_items WHERE id = $1", id)
+	// Soft delete so historical order items keep resolving their menu item
+	result, err := r.db.ExecContext(ctx, "UPDATE menu_items SET version = version + 1, deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL", time.Now(), id)
 	if err != nil {
 		return err
 	}
@@ -150,8 +258,24 @@ func (r *postgresMenuRepository) DeleteMenuItem(ctx context.Context, id uuid.UUI
 	return nil
 }
 
-func (r *postgresMenuRepository) ListCategories(ctx context.Context) ([]Category, error) {
-	rows, err := r.db.QueryContext(ctx, "SELECT id, name FROM categories ORDER BY name")
+// RestoreMenuItem clears the soft delete marker of a menu item
+func (r *postgresMenuRepository) RestoreMenuItem(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, "UPDATE menu_items SET version = version + 1, deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL", id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.NewNotFoundError("deleted menu item not found")
+	}
+	return nil
+}
+
+func (r *postgresMenuRepository) ListCategories(ctx context.Context, includeDeleted bool) ([]Category, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT id, name, parent_id, sort_order, image_url, thumbnail_url, deleted_at, version FROM categories WHERE ($1 OR deleted_at IS NULL) ORDER BY sort_order, name", includeDeleted)
 	if err != nil {
 		return nil, err
 	}
@@ -160,7 +284,7 @@ func (r *postgresMenuRepository) ListCategories(ctx context.Context) ([]Category
 	var categories []Category
 	for rows.Next() {
 		var category Category
-		err := rows.Scan(&category.ID, &category.Name)
+		err := rows.Scan(&category.ID, &category.Name, &category.ParentID, &category.SortOrder, &category.ImageURL, &category.ThumbnailURL, &category.DeletedAt, &category.Version)
 		if err != nil {
 			return nil, err
 		}
@@ -172,14 +296,16 @@ func (r *postgresMenuRepository) ListCategories(ctx context.Context) ([]Category
 	return categories, nil
 }
 
-func (r *postgresMenuRepository) CreateCategory(ctx context.Context, name string, id uuid.UUID) error {
-	_, err := r.db.ExecContext(ctx, "INSERT INTO categories (id, name) VALUES ($1, $2)", id, name)
+func (r *postgresMenuRepository) CreateCategory(ctx context.Context, category *Category) error {
+	_, err := r.db.ExecContext(ctx, "INSERT INTO categories (id, name, parent_id, sort_order) VALUES ($1, $2, $3, $4)",
+		category.ID, category.Name, category.ParentID, category.SortOrder)
 	return err
 }
 
 func (r *postgresMenuRepository) GetCategoryByID(ctx context.Context, id uuid.UUID) (*Category, error) {
 	var category Category
-	err := r.db.QueryRowContext(ctx, "SELECT id, name FROM categories WHERE id = $1", id).Scan(&category.ID, &category.Name)
+	err := r.db.QueryRowContext(ctx, "SELECT id, name, parent_id, sort_order, image_url, thumbnail_url, deleted_at, version FROM categories WHERE id = $1", id).Scan(
+		&category.ID, &category.Name, &category.ParentID, &category.SortOrder, &category.ImageURL, &category.ThumbnailURL, &category.DeletedAt, &category.Version)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, errors.ErrCategoryNotFound
@@ -189,9 +315,9 @@ func (r *postgresMenuRepository) GetCategoryByID(ctx context.Context, id uuid.UU
 	return &category, nil
 }
 
-// UpdateCategory updates an existing category name
-func (r *postgresMenuRepository) UpdateCategory(ctx context.Context, old_name string, new_name string) error {
-	result, err := r.db.ExecContext(ctx, "UPDATE categories SET name = $1 WHERE LOWER(name) = LOWER($2)", new_name, old_name)
+// UpdateCategory updates an existing category name if the category is still at version
+func (r *postgresMenuRepository) UpdateCategory(ctx context.Context, old_name string, new_name string, version int) error {
+	result, err := r.db.ExecContext(ctx, "UPDATE categories SET version = version + 1, name = $1 WHERE LOWER(name) = LOWER($2) AND deleted_at IS NULL AND version = $3", new_name, old_name, version)
 	if err != nil {
 		return err
 	}
@@ -200,18 +326,30 @@ func (r *postgresMenuRepository) UpdateCategory(ctx context.Context, old_name st
 		return err
 	}
 	if rowsAffected == 0 {
-		return errors.ErrCategoryNotFound
+		return r.versionMiss(ctx, "SELECT EXISTS (SELECT 1 FROM categories WHERE LOWER(name) = LOWER($1) AND deleted_at IS NULL)", old_name, errors.ErrCategoryNotFound)
 	}
 	return nil
 }
 
 func (r *postgresMenuRepository) DeleteCategory(ctx context.Context, name string) error {
-	result, err := r.db.ExecContext(ctx, "DELETE FROM categories WHERE LOWER(name) = LOWER($1)", name)
+	// A category still holding live menu items or subcategories cannot be deleted
+	var inUse bool
+	err := r.db.QueryRowContext(ctx, `SELECT EXISTS (
+		SELECT 1 FROM menu_items m JOIN categories c ON m.category = c.id
+		WHERE LOWER(c.name) = LOWER($1) AND c.deleted_at IS NULL AND m.deleted_at IS NULL
+	) OR EXISTS (
+		SELECT 1 FROM categories child JOIN categories c ON child.parent_id = c.id
+		WHERE LOWER(c.name) = LOWER($1) AND c.deleted_at IS NULL AND child.deleted_at IS NULL
+	)`, name).Scan(&inUse)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return errors.ErrForeignKeyViolation
+	}
+
+	result, err := r.db.ExecContext(ctx, "UPDATE categories SET version = version + 1, deleted_at = $1 WHERE LOWER(name) = LOWER($2) AND deleted_at IS NULL", time.Now(), name)
 	if err != nil {
-		// Check if it's a foreign key violation
-		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
-			return errors.ErrForeignKeyViolation
-		}
 		return err
 	}
 	rowsAffected, err := result.RowsAffected()
@@ -224,9 +362,25 @@ func (r *postgresMenuRepository) DeleteCategory(ctx context.Context, name string
 	return nil
 }
 
+// RestoreCategory clears the soft delete marker of a category
+func (r *postgresMenuRepository) RestoreCategory(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, "UPDATE categories SET version = version + 1, deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL", id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.NewNotFoundError("deleted category not found")
+	}
+	return nil
+}
+
 func (r *postgresMenuRepository) CategoryIDByName(ctx context.Context, name string) (uuid.UUID, error) {
 	var id uuid.UUID
-	err := r.db.QueryRowContext(ctx, "SELECT id FROM categories WHERE LOWER(name) = LOWER($1)", name).Scan(&id)
+	err := r.db.QueryRowContext(ctx, "SELECT id FROM categories WHERE LOWER(name) = LOWER($1) AND deleted_at IS NULL", name).Scan(&id)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return uuid.Nil, errors.ErrCategoryNotFound
@@ -241,7 +395,7 @@ func (r *postgresMenuRepository) CategoryIDByNameCreateIfNotPresent(ctx context.
 	if err == errors.ErrCategoryNotFound {
 		// Create new category
 		newID := uuid.New()
-		err = r.CreateCategory(ctx, name, newID)
+		err = r.CreateCategory(ctx, &Category{ID: newID, Name: name})
 		if err != nil {
 			// Check if it's a UNIQUE constraint violation
 			if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
@@ -256,3 +410,450 @@ func (r *postgresMenuRepository) CategoryIDByNameCreateIfNotPresent(ctx context.
 	}
 	return id, nil
 }
+
+// ListAllMenuItems lists every menu item ordered for display
+func (r *postgresMenuRepository) ListAllMenuItems(ctx context.Context) ([]*MenuItem, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT id, name, description, price, avalability_status, category, sort_order, image_url, thumbnail_url, created_at, deleted_at, version FROM menu_items WHERE deleted_at IS NULL ORDER BY sort_order, name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []*MenuItem
+	for rows.Next() {
+		var item MenuItem
+		err := rows.Scan(&item.ID, &item.Name, &item.Description, &item.Price, &item.AvalabilityStatus, &item.CategoryID, &item.SortOrder, &item.ImageURL, &item.ThumbnailURL, &item.CreatedAt, &item.DeletedAt, &item.Version)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, &item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// ApplyMoves repositions categories and menu items atomically
+func (r *postgresMenuRepository) ApplyMoves(ctx context.Context, moves []Move) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.NewInternalError("failed to begin transaction", err)
+	}
+	defer tx.Rollback()
+
+	for _, move := range moves {
+		var result sql.Result
+		switch move.Type {
+		case MoveTypeCategory:
+			result, err = tx.ExecContext(ctx, "UPDATE categories SET version = version + 1, parent_id = $1, sort_order = $2 WHERE id = $3 AND deleted_at IS NULL",
+				move.ParentID, move.SortOrder, move.ID)
+		case MoveTypeMenuItem:
+			result, err = tx.ExecContext(ctx, "UPDATE menu_items SET version = version + 1, category = COALESCE($1, category), sort_order = $2 WHERE id = $3 AND deleted_at IS NULL",
+				move.CategoryID, move.SortOrder, move.ID)
+		default:
+			return errors.NewValidationError("invalid move type: " + string(move.Type))
+		}
+		if err != nil {
+			if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+				return errors.ErrCategoryNotFound
+			}
+			return err
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			if move.Type == MoveTypeCategory {
+				return errors.ErrCategoryNotFound
+			}
+			return errors.ErrMenuItemNotFound
+		}
+	}
+
+	return tx.Commit()
+}
+
+// SetMenuItemImage stores the image URLs of a live menu item
+func (r *postgresMenuRepository) SetMenuItemImage(ctx context.Context, id uuid.UUID, imageURL string, thumbnailURL string) error {
+	result, err := r.db.ExecContext(ctx, "UPDATE menu_items SET version = version + 1, image_url = $1, thumbnail_url = $2 WHERE id = $3 AND deleted_at IS NULL",
+		imageURL, thumbnailURL, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.ErrMenuItemNotFound
+	}
+	return nil
+}
+
+// SetCategoryImage stores the image URLs of a live category
+func (r *postgresMenuRepository) SetCategoryImage(ctx context.Context, id uuid.UUID, imageURL string, thumbnailURL string) error {
+	result, err := r.db.ExecContext(ctx, "UPDATE categories SET version = version + 1, image_url = $1, thumbnail_url = $2 WHERE id = $3 AND deleted_at IS NULL",
+		imageURL, thumbnailURL, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.ErrCategoryNotFound
+	}
+	return nil
+}
+
+// GetMenuItemTranslations retrieves translations of the given menu items in any of the given locales
+func (r *postgresMenuRepository) GetMenuItemTranslations(ctx context.Context, itemIDs []uuid.UUID, locales []string) ([]MenuItemTranslation, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT menu_item_id, locale, name, description FROM menu_item_translations WHERE menu_item_id = ANY($1) AND locale = ANY($2)",
+		pq.Array(itemIDs), pq.Array(locales))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var translations []MenuItemTranslation
+	for rows.Next() {
+		var t MenuItemTranslation
+		if err := rows.Scan(&t.MenuItemID, &t.Locale, &t.Name, &t.Description); err != nil {
+			return nil, err
+		}
+		translations = append(translations, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return translations, nil
+}
+
+// GetCategoryTranslations retrieves translations of the given categories in any of the given locales
+func (r *postgresMenuRepository) GetCategoryTranslations(ctx context.Context, categoryIDs []uuid.UUID, locales []string) ([]CategoryTranslation, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT category_id, locale, name FROM category_translations WHERE category_id = ANY($1) AND locale = ANY($2)",
+		pq.Array(categoryIDs), pq.Array(locales))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var translations []CategoryTranslation
+	for rows.Next() {
+		var t CategoryTranslation
+		if err := rows.Scan(&t.CategoryID, &t.Locale, &t.Name); err != nil {
+			return nil, err
+		}
+		translations = append(translations, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return translations, nil
+}
+
+// UpsertTranslations creates or replaces translations atomically
+func (r *postgresMenuRepository) UpsertTranslations(ctx context.Context, items []MenuItemTranslation, categories []CategoryTranslation) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.NewInternalError("failed to begin transaction", err)
+	}
+	defer tx.Rollback()
+
+	// A translation is part of what its item or category returns, so writing one bumps the parent's version
+	now := time.Now()
+	for _, t := range items {
+		_, err := tx.ExecContext(ctx, `WITH upserted AS (
+				INSERT INTO menu_item_translations (menu_item_id, locale, name, description, updated_at)
+				VALUES ($1, $2, $3, $4, $5)
+				ON CONFLICT (menu_item_id, locale) DO UPDATE SET name = EXCLUDED.name, description = EXCLUDED.description, updated_at = EXCLUDED.updated_at
+				RETURNING menu_item_id
+			)
+			UPDATE menu_items SET version = version + 1 WHERE id IN (SELECT menu_item_id FROM upserted)`,
+			t.MenuItemID, t.Locale, t.Name, t.Description, now)
+		if err != nil {
+			if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+				return errors.NewNotFoundError("menu item " + t.MenuItemID.String() + " not found")
+			}
+			return err
+		}
+	}
+	for _, t := range categories {
+		_, err := tx.ExecContext(ctx, `WITH upserted AS (
+				INSERT INTO category_translations (category_id, locale, name, updated_at)
+				VALUES ($1, $2, $3, $4)
+				ON CONFLICT (category_id, locale) DO UPDATE SET name = EXCLUDED.name, updated_at = EXCLUDED.updated_at
+				RETURNING category_id
+			)
+			UPDATE categories SET version = version + 1 WHERE id IN (SELECT category_id FROM upserted)`,
+			t.CategoryID, t.Locale, t.Name, now)
+		if err != nil {
+			if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
+				return errors.NewNotFoundError("category " + t.CategoryID.String() + " not found")
+			}
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// DeleteMenuItemTranslation deletes one locale of a menu item's translations and bumps the item's version
+func (r *postgresMenuRepository) DeleteMenuItemTranslation(ctx context.Context, itemID uuid.UUID, locale string) error {
+	result, err := r.db.ExecContext(ctx, `WITH deleted AS (
+			DELETE FROM menu_item_translations WHERE menu_item_id = $1 AND locale = $2 RETURNING menu_item_id
+		)
+		UPDATE menu_items SET version = version + 1 WHERE id IN (SELECT menu_item_id FROM deleted)`, itemID, locale)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.NewNotFoundError("translation not found")
+	}
+	return nil
+}
+
+// ListMissingTranslations lists live menu items and categories with no translation in the locale
+func (r *postgresMenuRepository) ListMissingTranslations(ctx context.Context, locale string) (*MissingTranslations, error) {
+	missing := &MissingTranslations{
+		Locale:     locale,
+		MenuItems:  []TranslationGap{},
+		Categories: []TranslationGap{},
+	}
+
+	rows, err := r.db.QueryContext(ctx, `SELECT m.id, m.name FROM menu_items m
+		WHERE m.deleted_at IS NULL
+			AND NOT EXISTS (SELECT 1 FROM menu_item_translations t WHERE t.menu_item_id = m.id AND t.locale = $1)
+		ORDER BY m.name`, locale)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var gap TranslationGap
+		if err := rows.Scan(&gap.ID, &gap.Name); err != nil {
+			return nil, err
+		}
+		missing.MenuItems = append(missing.MenuItems, gap)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	catRows, err := r.db.QueryContext(ctx, `SELECT c.id, c.name FROM categories c
+		WHERE c.deleted_at IS NULL
+			AND NOT EXISTS (SELECT 1 FROM category_translations t WHERE t.category_id = c.id AND t.locale = $1)
+		ORDER BY c.name`, locale)
+	if err != nil {
+		return nil, err
+	}
+	defer catRows.Close()
+	for catRows.Next() {
+		var gap TranslationGap
+		if err := catRows.Scan(&gap.ID, &gap.Name); err != nil {
+			return nil, err
+		}
+		missing.Categories = append(missing.Categories, gap)
+	}
+	if err := catRows.Err(); err != nil {
+		return nil, err
+	}
+
+	return missing, nil
+}
+
+// ImportMenu inserts new categories followed by menu items; nothing is written unless every insert succeeds
+func (r *postgresMenuRepository) ImportMenu(ctx context.Context, categories []*Category, items []*MenuItem) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.NewInternalError("failed to begin transaction", err)
+	}
+	defer tx.Rollback()
+
+	for _, category := range categories {
+		_, err := tx.ExecContext(ctx, "INSERT INTO categories (id, name, parent_id, sort_order) VALUES ($1, $2, $3, $4)",
+			category.ID, category.Name, category.ParentID, category.SortOrder)
+		if err != nil {
+			return err
+		}
+	}
+	for _, item := range items {
+		if err := insertMenuItem(ctx, tx, item); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// AddMenuItemPrice splits the price timeline at price.EffectiveFrom: the range covering that instant is cut short
+// and the new price runs until the next change, or indefinitely if there is none. When price.AppliedAt is set the
+// price takes effect immediately and menu_items.price is updated in the same transaction.
+func (r *postgresMenuRepository) AddMenuItemPrice(ctx context.Context, price *MenuItemPrice) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.NewInternalError("failed to begin transaction", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the item so concurrent edits of the same timeline are serialized
+	var id string
+	err = tx.QueryRowContext(ctx, "SELECT id FROM menu_items WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", price.MenuItemID).Scan(&id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return errors.ErrMenuItemNotFound
+		}
+		return err
+	}
+
+	if err := insertMenuItemPrice(ctx, tx, price); err != nil {
+		return err
+	}
+
+	if price.AppliedAt != nil {
+		_, err = tx.ExecContext(ctx, "UPDATE menu_items SET version = version + 1, price = $1 WHERE id = $2", price.Price, price.MenuItemID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// insertMenuItemPrice cuts short the range of the menu item's timeline covering price.EffectiveFrom and inserts
+// price, running until the next change. The caller must hold a lock on the menu item.
+func insertMenuItemPrice(ctx context.Context, tx *sql.Tx, price *MenuItemPrice) error {
+	var next sql.NullTime
+	err := tx.QueryRowContext(ctx, "SELECT MIN(effective_from) FROM menu_item_prices WHERE menu_item_id = $1 AND effective_from > $2",
+		price.MenuItemID, price.EffectiveFrom).Scan(&next)
+	if err != nil {
+		return err
+	}
+	price.EffectiveTo = nil
+	if next.Valid {
+		price.EffectiveTo = &next.Time
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE menu_item_prices SET effective_to = $2 WHERE menu_item_id = $1 AND effective_from < $2 AND (effective_to IS NULL OR effective_to > $2)",
+		price.MenuItemID, price.EffectiveFrom)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "INSERT INTO menu_item_prices (id, menu_item_id, price, effective_from, effective_to, applied_at, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7)",
+		price.ID, price.MenuItemID, price.Price, price.EffectiveFrom, price.EffectiveTo, price.AppliedAt, price.CreatedAt)
+	return err
+}
+
+// ListMenuItemPrices lists every price of a menu item ordered by effective_from
+func (r *postgresMenuRepository) ListMenuItemPrices(ctx context.Context, itemID uuid.UUID) ([]MenuItemPrice, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT id, menu_item_id, price, effective_from, effective_to, applied_at, created_at FROM menu_item_prices WHERE menu_item_id = $1 ORDER BY effective_from", itemID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	prices := []MenuItemPrice{}
+	for rows.Next() {
+		var p MenuItemPrice
+		if err := rows.Scan(&p.ID, &p.MenuItemID, &p.Price, &p.EffectiveFrom, &p.EffectiveTo, &p.AppliedAt, &p.CreatedAt); err != nil {
+			return nil, err
+		}
+		prices = append(prices, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return prices, nil
+}
+
+// GetMenuItemPriceAt retrieves the price whose range contains at
+func (r *postgresMenuRepository) GetMenuItemPriceAt(ctx context.Context, itemID uuid.UUID, at time.Time) (*MenuItemPrice, error) {
+	var p MenuItemPrice
+	err := r.db.QueryRowContext(ctx, `SELECT id, menu_item_id, price, effective_from, effective_to, applied_at, created_at FROM menu_item_prices
+		WHERE menu_item_id = $1 AND effective_from <= $2 AND (effective_to IS NULL OR effective_to > $2)`, itemID, at).Scan(
+		&p.ID, &p.MenuItemID, &p.Price, &p.EffectiveFrom, &p.EffectiveTo, &p.AppliedAt, &p.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.NewNotFoundError("no price recorded for menu item at " + at.Format(time.RFC3339))
+		}
+		return nil, errors.NewInternalError("failed to get menu item price", err)
+	}
+	return &p, nil
+}
+
+// DeleteScheduledPrice removes an unapplied price change and extends the preceding range over its gap
+func (r *postgresMenuRepository) DeleteScheduledPrice(ctx context.Context, itemID uuid.UUID, priceID uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.NewInternalError("failed to begin transaction", err)
+	}
+	defer tx.Rollback()
+
+	var id string
+	err = tx.QueryRowContext(ctx, "SELECT id FROM menu_items WHERE id = $1 FOR UPDATE", itemID).Scan(&id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return errors.ErrMenuItemNotFound
+		}
+		return err
+	}
+
+	var from time.Time
+	var to sql.NullTime
+	err = tx.QueryRowContext(ctx, "DELETE FROM menu_item_prices WHERE id = $1 AND menu_item_id = $2 AND applied_at IS NULL RETURNING effective_from, effective_to",
+		priceID, itemID).Scan(&from, &to)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return errors.NewNotFoundError("scheduled price change not found")
+		}
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE menu_item_prices SET effective_to = $3 WHERE menu_item_id = $1 AND effective_to = $2",
+		itemID, from, to)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ApplyDuePrices sets menu_items.price from every unapplied price whose range contains now, then marks
+// all changes that have started by now as applied, including ones already superseded by a later change
+func (r *postgresMenuRepository) ApplyDuePrices(ctx context.Context, now time.Time) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, errors.NewInternalError("failed to begin transaction", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `UPDATE menu_items m SET version = version + 1, price = p.price
+		FROM menu_item_prices p
+		WHERE p.menu_item_id = m.id AND p.applied_at IS NULL
+			AND p.effective_from <= $1 AND (p.effective_to IS NULL OR p.effective_to > $1)`, now)
+	if err != nil {
+		return 0, err
+	}
+	applied, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE menu_item_prices SET applied_at = $1 WHERE applied_at IS NULL AND effective_from <= $1", now)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return int(applied), nil
+}
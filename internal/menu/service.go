@@ -3,7 +3,10 @@ package menu
 import (
 	"context"
 	"fmt"
+	"io"
 	apperrors "restaurant/internal/errors"
+	"restaurant/internal/media"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,26 +17,52 @@ import (
 type MenuService interface {
 	CreateMenuItem(ctx context.Context, Name string, Description string, Price float64, Category string, AvalabilityStatus ItemStatus) (*MenuItem, error)
 	GetMenuItem(ctx context.Context, id uuid.UUID) (*MenuItem, error)
-	ListMenuItems(ctx context.Context, offset int, limit int) ([]*MenuItem, error)
-	UpdateMenuItem(ctx context.Context, id uuid.UUID, name string, desc string, category string, price float64, avalabilityStatus ItemStatus) error
+	ListMenuItems(ctx context.Context, offset int, limit int, includeDeleted bool) ([]*MenuItem, error)
+	UpdateMenuItem(ctx context.Context, id uuid.UUID, name string, desc string, category string, price float64, avalabilityStatus ItemStatus, version int) (*MenuItem, error)
 	DeleteMenuItem(ctx context.Context, id uuid.UUID) error
-	GetMenuItemsByCategory(ctx context.Context, category string) ([]*MenuItem, error)
-	ListCategories(ctx context.Context) ([]Category, error)
-	CreateCategory(ctx context.Context, name string) (*Category, error)
+	RestoreMenuItem(ctx context.Context, id uuid.UUID) (*MenuItem, error)
+	GetMenuItemsByCategory(ctx context.Context, category string, includeDeleted bool) ([]*MenuItem, error)
+	ListCategories(ctx context.Context, includeDeleted bool) ([]Category, error)
+	CreateCategory(ctx context.Context, name string, parentID *uuid.UUID, sortOrder int) (*Category, error)
 	GetCategoryByID(ctx context.Context, id uuid.UUID) (*Category, error)
 	DeleteCategory(ctx context.Context, name string) error
-	UpdateCategory(ctx context.Context, old_name string, new_name string) (*Category, error)
+	RestoreCategory(ctx context.Context, id uuid.UUID) (*Category, error)
+	UpdateCategory(ctx context.Context, old_name string, new_name string, version int) (*Category, error)
 	CategoryIDByName(ctx context.Context, name string) (uuid.UUID, error)
+	GetMenuTree(ctx context.Context) ([]*CategoryNode, error)
+	Reorder(ctx context.Context, moves []Move) error
+	UploadMenuItemImage(ctx context.Context, id uuid.UUID, r io.Reader) (*MenuItem, error)
+	DeleteMenuItemImage(ctx context.Context, id uuid.UUID) error
+	UploadCategoryImage(ctx context.Context, id uuid.UUID, r io.Reader) (*Category, error)
+	DeleteCategoryImage(ctx context.Context, id uuid.UUID) error
+	LocalizeMenuItems(ctx context.Context, locales []string, items []*MenuItem) error
+	LocalizeCategories(ctx context.Context, locales []string, categories []Category) error
+	LocalizeMenuTree(ctx context.Context, locales []string, tree []*CategoryNode) error
+	UpsertTranslations(ctx context.Context, items []MenuItemTranslation, categories []CategoryTranslation) error
+	DeleteMenuItemTranslation(ctx context.Context, itemID uuid.UUID, locale string) error
+	GetMissingTranslations(ctx context.Context, locale string) (*MissingTranslations, error)
+	ImportMenu(ctx context.Context, rows []CreateMenuItemRequest, dryRun bool) (*ImportResult, error)
+	ExportMenu(ctx context.Context) ([]CreateMenuItemRequest, error)
+	SchedulePriceChange(ctx context.Context, itemID uuid.UUID, price float64, effectiveFrom time.Time) (*MenuItemPrice, error)
+	CancelPriceChange(ctx context.Context, itemID uuid.UUID, priceID uuid.UUID) error
+	GetPriceHistory(ctx context.Context, itemID uuid.UUID) ([]MenuItemPrice, error)
+	GetPriceAt(ctx context.Context, itemID uuid.UUID, at time.Time) (*MenuItemPrice, error)
+	ApplyDuePrices(ctx context.Context) (int, error)
 }
 
 // menuService implements MenuService
 type menuService struct {
-	repo MenuRepository
+	repo          MenuRepository
+	images        *media.ImageProcessor
+	defaultLocale string
 }
 
-// NewMenuService creates a new menu service
-func NewMenuService(repo MenuRepository) MenuService {
-	return &menuService{repo: repo}
+// NewMenuService creates a new menu service; base menu content is stored in defaultLocale
+func NewMenuService(repo MenuRepository, images *media.ImageProcessor, defaultLocale string) MenuService {
+	if defaultLocale == "" {
+		defaultLocale = "en"
+	}
+	return &menuService{repo: repo, images: images, defaultLocale: strings.ToLower(defaultLocale)}
 }
 
 // Implementations (wrappers around repository)
@@ -74,21 +103,30 @@ func (s *menuService) CreateMenuItem(ctx context.Context, Name string, Descripti
 	return item, nil
 }
 
-func (s *menuService) ListMenuItems(ctx context.Context, offset int, limit int) ([]*MenuItem, error) {
-	items, err := s.repo.ListMenuItems(ctx, offset, limit)
+func (s *menuService) ListMenuItems(ctx context.Context, offset int, limit int, includeDeleted bool) ([]*MenuItem, error) {
+	items, err := s.repo.ListMenuItems(ctx, offset, limit, includeDeleted)
 	if err != nil {
 		return nil, apperrors.WrapError(500, "failed to list menu items", err)
 	}
 	return items, nil
 }
 
-func (s *menuService) UpdateMenuItem(ctx context.Context, id uuid.UUID, name string, desc string, category string, price float64, avalabilityStatus ItemStatus) error {
+// UpdateMenuItem updates a menu item that is still at version and returns it with its new version
+func (s *menuService) UpdateMenuItem(ctx context.Context, id uuid.UUID, name string, desc string, category string, price float64, avalabilityStatus ItemStatus, version int) (*MenuItem, error) {
 	// Shape validation (name, description, price, category) already done by handler using ValidateStruct
 
 	// Ensure category exists (BUSINESS LOGIC)
 	categoryID, err := s.CategoryIDByName(ctx, category)
 	if err != nil {
-		return apperrors.WrapError(500, "failed to ensure category exists", err)
+		return nil, apperrors.WrapError(500, "failed to ensure category exists", err)
+	}
+
+	current, err := s.repo.GetMenuItem(ctx, id)
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to retrieve menu item", err)
+	}
+	if current.Version != version {
+		return nil, apperrors.ErrVersionConflict
 	}
 
 	item := &MenuItem{
@@ -98,12 +136,35 @@ func (s *menuService) UpdateMenuItem(ctx context.Context, id uuid.UUID, name str
 		Price:             price,
 		CategoryID:        categoryID,
 		AvalabilityStatus: avalabilityStatus,
+		Version:           version,
 	}
-	err = s.repo.UpdateMenuItem(ctx, item)
+
+	// A price change takes effect now and is recorded in the price history together with the update (BUSINESS LOGIC)
+	var change *MenuItemPrice
+	if price > 0 && price != current.Price {
+		now := time.Now()
+		change = &MenuItemPrice{
+			ID:            uuid.New(),
+			MenuItemID:    id,
+			Price:         price,
+			EffectiveFrom: now,
+			AppliedAt:     &now,
+			CreatedAt:     now,
+		}
+	}
+	err = s.repo.UpdateMenuItem(ctx, item, change)
 	if err != nil {
-		return apperrors.WrapError(500, "failed to update menu item", err)
+		if err == apperrors.ErrVersionConflict {
+			return nil, err
+		}
+		return nil, apperrors.WrapError(500, "failed to update menu item", err)
 	}
-	return nil
+
+	updated, err := s.repo.GetMenuItem(ctx, id)
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to retrieve updated menu item", err)
+	}
+	return updated, nil
 }
 
 func (s *menuService) DeleteMenuItem(ctx context.Context, id uuid.UUID) error {
@@ -118,30 +179,65 @@ func (s *menuService) DeleteMenuItem(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
-func (s *menuService) GetMenuItemsByCategory(ctx context.Context, category string) ([]*MenuItem, error) {
+// RestoreMenuItem brings a soft-deleted menu item back onto the menu
+func (s *menuService) RestoreMenuItem(ctx context.Context, id uuid.UUID) (*MenuItem, error) {
+	item, err := s.repo.GetMenuItem(ctx, id)
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to retrieve menu item", err)
+	}
+
+	// Items cannot come back into a category that is itself deleted (BUSINESS LOGIC)
+	category, err := s.repo.GetCategoryByID(ctx, item.CategoryID)
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to retrieve menu item category", err)
+	}
+	if category.DeletedAt != nil {
+		return nil, apperrors.NewConflictError("restore category '" + category.Name + "' before restoring its menu items")
+	}
+
+	if err := s.repo.RestoreMenuItem(ctx, id); err != nil {
+		return nil, apperrors.WrapError(500, "failed to restore menu item", err)
+	}
+	item.DeletedAt = nil
+	return item, nil
+}
+
+func (s *menuService) GetMenuItemsByCategory(ctx context.Context, category string, includeDeleted bool) ([]*MenuItem, error) {
 	if category == "" {
 		return nil, apperrors.NewValidationError("category is required")
 	}
-	items, err := s.repo.GetMenuItemsByCategory(ctx, category)
+	items, err := s.repo.GetMenuItemsByCategory(ctx, category, includeDeleted)
 	if err != nil {
 		return nil, apperrors.WrapError(500, "failed to get menu items by category", err)
 	}
 	return items, nil
 }
 
-func (s *menuService) ListCategories(ctx context.Context) ([]Category, error) {
-	categories, err := s.repo.ListCategories(ctx)
+func (s *menuService) ListCategories(ctx context.Context, includeDeleted bool) ([]Category, error) {
+	categories, err := s.repo.ListCategories(ctx, includeDeleted)
 	if err != nil {
 		return nil, apperrors.WrapError(500, "failed to list categories", err)
 	}
 	return categories, nil
 }
 
-func (s *menuService) CreateCategory(ctx context.Context, name string) (*Category, error) {
-	// Shape validation (name) already done by handler using ValidateStruct
+func (s *menuService) CreateCategory(ctx context.Context, name string, parentID *uuid.UUID, sortOrder int) (*Category, error) {
+	// Shape validation (name, sort order) already done by handler using ValidateStruct
 
-	id := uuid.New()
-	err := s.repo.CreateCategory(ctx, name, id)
+	// Ensure parent category exists (BUSINESS LOGIC)
+	if parentID != nil {
+		if _, err := s.repo.GetCategoryByID(ctx, *parentID); err != nil {
+			return nil, apperrors.WrapError(500, "failed to retrieve parent category", err)
+		}
+	}
+
+	category := &Category{
+		ID:        uuid.New(),
+		Name:      name,
+		ParentID:  parentID,
+		SortOrder: sortOrder,
+	}
+	err := s.repo.CreateCategory(ctx, category)
 	if err != nil {
 		// Handle PostgreSQL UNIQUE constraint violation
 		if pqErr, ok := err.(*pq.Error); ok {
@@ -153,10 +249,7 @@ func (s *menuService) CreateCategory(ctx context.Context, name string) (*Categor
 	}
 
 	// Return category with generated ID
-	return &Category{
-		ID:   id,
-		Name: name,
-	}, nil
+	return category, nil
 }
 
 func (s *menuService) GetCategoryByID(ctx context.Context, id uuid.UUID) (*Category, error) {
@@ -179,9 +272,30 @@ func (s *menuService) DeleteCategory(ctx context.Context, name string) error {
 	return nil
 }
 
-func (s *menuService) UpdateCategory(ctx context.Context, old_name string, new_name string) (*Category, error) {
-	err := s.repo.UpdateCategory(ctx, old_name, new_name)
+// RestoreCategory brings a soft-deleted category back onto the menu
+func (s *menuService) RestoreCategory(ctx context.Context, id uuid.UUID) (*Category, error) {
+	err := s.repo.RestoreCategory(ctx, id)
 	if err != nil {
+		// A live category may have taken the name in the meantime
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return nil, apperrors.ErrDuplicateCategory
+		}
+		return nil, apperrors.WrapError(500, "failed to restore category", err)
+	}
+
+	category, err := s.repo.GetCategoryByID(ctx, id)
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to retrieve restored category", err)
+	}
+	return category, nil
+}
+
+func (s *menuService) UpdateCategory(ctx context.Context, old_name string, new_name string, version int) (*Category, error) {
+	err := s.repo.UpdateCategory(ctx, old_name, new_name, version)
+	if err != nil {
+		if err == apperrors.ErrVersionConflict {
+			return nil, err
+		}
 		// Handle PostgreSQL UNIQUE constraint violation
 		if pqErr, ok := err.(*pq.Error); ok {
 			if pqErr.Code == "23505" { // unique_violation error code
@@ -212,3 +326,495 @@ func (s *menuService) CategoryIDByName(ctx context.Context, name string) (uuid.U
 	}
 	return id, nil
 }
+
+// GetMenuTree returns the full menu as a tree of categories, each holding its subcategories and items in display order
+func (s *menuService) GetMenuTree(ctx context.Context) ([]*CategoryNode, error) {
+	categories, err := s.repo.ListCategories(ctx, false)
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to list categories", err)
+	}
+	items, err := s.repo.ListAllMenuItems(ctx)
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to list menu items", err)
+	}
+
+	// Categories and items arrive already sorted, so appending preserves display order
+	nodes := make(map[uuid.UUID]*CategoryNode, len(categories))
+	for _, category := range categories {
+		nodes[category.ID] = &CategoryNode{
+			Category: category,
+			Children: []*CategoryNode{},
+			Items:    []*MenuItem{},
+		}
+	}
+	for _, item := range items {
+		if node, ok := nodes[item.CategoryID]; ok {
+			node.Items = append(node.Items, item)
+		}
+	}
+
+	roots := []*CategoryNode{}
+	for _, category := range categories {
+		node := nodes[category.ID]
+		if category.ParentID == nil {
+			roots = append(roots, node)
+			continue
+		}
+		parent, ok := nodes[*category.ParentID]
+		if !ok {
+			roots = append(roots, node)
+			continue
+		}
+		parent.Children = append(parent.Children, node)
+	}
+	return roots, nil
+}
+
+// Reorder applies a batch of moves after checking that every target exists and no category becomes its own ancestor
+func (s *menuService) Reorder(ctx context.Context, moves []Move) error {
+	categories, err := s.repo.ListCategories(ctx, false)
+	if err != nil {
+		return apperrors.WrapError(500, "failed to list categories", err)
+	}
+
+	// Build the parent map as it will look after all moves are applied (BUSINESS LOGIC)
+	parents := make(map[uuid.UUID]*uuid.UUID, len(categories))
+	for _, category := range categories {
+		parents[category.ID] = category.ParentID
+	}
+	for _, move := range moves {
+		switch move.Type {
+		case MoveTypeCategory:
+			if _, ok := parents[move.ID]; !ok {
+				return apperrors.ErrCategoryNotFound
+			}
+			if move.ParentID != nil {
+				if _, ok := parents[*move.ParentID]; !ok {
+					return apperrors.NewNotFoundError("parent category not found")
+				}
+			}
+			parents[move.ID] = move.ParentID
+		case MoveTypeMenuItem:
+			if move.CategoryID != nil {
+				if _, ok := parents[*move.CategoryID]; !ok {
+					return apperrors.ErrCategoryNotFound
+				}
+			}
+		default:
+			return apperrors.NewValidationError("invalid move type: " + string(move.Type))
+		}
+	}
+
+	for _, move := range moves {
+		if move.Type != MoveTypeCategory {
+			continue
+		}
+		seen := map[uuid.UUID]bool{move.ID: true}
+		for parent := parents[move.ID]; parent != nil; parent = parents[*parent] {
+			if seen[*parent] {
+				return apperrors.NewValidationError(fmt.Sprintf("moving category %s would create a cycle", move.ID))
+			}
+			seen[*parent] = true
+		}
+	}
+
+	if err := s.repo.ApplyMoves(ctx, moves); err != nil {
+		return apperrors.WrapError(500, "failed to apply moves", err)
+	}
+	return nil
+}
+
+// menuItemImagePrefix is the storage prefix holding a menu item's image and thumbnail
+func menuItemImagePrefix(id uuid.UUID) string {
+	return "menu-items/" + id.String()
+}
+
+// categoryImagePrefix is the storage prefix holding a category's image and thumbnail
+func categoryImagePrefix(id uuid.UUID) string {
+	return "categories/" + id.String()
+}
+
+// UploadMenuItemImage validates and stores a photo for a menu item, replacing any existing one
+func (s *menuService) UploadMenuItemImage(ctx context.Context, id uuid.UUID, r io.Reader) (*MenuItem, error) {
+	item, err := s.repo.GetMenuItem(ctx, id)
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to retrieve menu item", err)
+	}
+	if item.DeletedAt != nil {
+		return nil, apperrors.ErrMenuItemNotFound
+	}
+
+	img, err := s.images.Store(ctx, menuItemImagePrefix(id), r)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetMenuItemImage(ctx, id, img.URL, img.ThumbnailURL); err != nil {
+		return nil, apperrors.WrapError(500, "failed to save menu item image", err)
+	}
+
+	item.ImageURL = img.URL
+	item.ThumbnailURL = img.ThumbnailURL
+	return item, nil
+}
+
+// DeleteMenuItemImage removes the photo of a menu item
+func (s *menuService) DeleteMenuItemImage(ctx context.Context, id uuid.UUID) error {
+	if err := s.repo.SetMenuItemImage(ctx, id, "", ""); err != nil {
+		return apperrors.WrapError(500, "failed to clear menu item image", err)
+	}
+	return s.images.Remove(ctx, menuItemImagePrefix(id))
+}
+
+// UploadCategoryImage validates and stores an image for a category, replacing any existing one
+func (s *menuService) UploadCategoryImage(ctx context.Context, id uuid.UUID, r io.Reader) (*Category, error) {
+	category, err := s.repo.GetCategoryByID(ctx, id)
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to retrieve category", err)
+	}
+	if category.DeletedAt != nil {
+		return nil, apperrors.ErrCategoryNotFound
+	}
+
+	img, err := s.images.Store(ctx, categoryImagePrefix(id), r)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetCategoryImage(ctx, id, img.URL, img.ThumbnailURL); err != nil {
+		return nil, apperrors.WrapError(500, "failed to save category image", err)
+	}
+
+	category.ImageURL = img.URL
+	category.ThumbnailURL = img.ThumbnailURL
+	return category, nil
+}
+
+// DeleteCategoryImage removes the image of a category
+func (s *menuService) DeleteCategoryImage(ctx context.Context, id uuid.UUID) error {
+	if err := s.repo.SetCategoryImage(ctx, id, "", ""); err != nil {
+		return apperrors.WrapError(500, "failed to clear category image", err)
+	}
+	return s.images.Remove(ctx, categoryImagePrefix(id))
+}
+
+// translationLocales trims the client's preferred locales to those worth looking up:
+// everything preferred over the default language, which is served from the base columns
+func (s *menuService) translationLocales(locales []string) []string {
+	for i, locale := range locales {
+		if locale == s.defaultLocale {
+			return locales[:i]
+		}
+	}
+	return locales
+}
+
+// pickLocale returns the most preferred locale that has a translation, or "" if none does
+func pickLocale(locales []string, available map[string]bool) string {
+	for _, locale := range locales {
+		if available[locale] {
+			return locale
+		}
+	}
+	return ""
+}
+
+// LocalizeMenuItems replaces names and descriptions with the best available translation, falling back to the default language
+func (s *menuService) LocalizeMenuItems(ctx context.Context, locales []string, items []*MenuItem) error {
+	for _, item := range items {
+		item.Locale = s.defaultLocale
+	}
+	locales = s.translationLocales(locales)
+	if len(locales) == 0 || len(items) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	translations, err := s.repo.GetMenuItemTranslations(ctx, ids, locales)
+	if err != nil {
+		return apperrors.WrapError(500, "failed to load menu item translations", err)
+	}
+
+	byItem := make(map[uuid.UUID]map[string]MenuItemTranslation)
+	for _, t := range translations {
+		if byItem[t.MenuItemID] == nil {
+			byItem[t.MenuItemID] = make(map[string]MenuItemTranslation)
+		}
+		byItem[t.MenuItemID][t.Locale] = t
+	}
+	for _, item := range items {
+		available := make(map[string]bool, len(byItem[item.ID]))
+		for locale := range byItem[item.ID] {
+			available[locale] = true
+		}
+		if locale := pickLocale(locales, available); locale != "" {
+			t := byItem[item.ID][locale]
+			item.Name = t.Name
+			item.Description = t.Description
+			item.Locale = locale
+		}
+	}
+	return nil
+}
+
+// LocalizeCategories replaces category names with the best available translation, falling back to the default language
+func (s *menuService) LocalizeCategories(ctx context.Context, locales []string, categories []Category) error {
+	ptrs := make([]*Category, len(categories))
+	for i := range categories {
+		ptrs[i] = &categories[i]
+	}
+	return s.localizeCategoryPtrs(ctx, locales, ptrs)
+}
+
+// LocalizeMenuTree localizes every category and menu item in a menu tree
+func (s *menuService) LocalizeMenuTree(ctx context.Context, locales []string, tree []*CategoryNode) error {
+	var categories []*Category
+	var items []*MenuItem
+	var walk func(nodes []*CategoryNode)
+	walk = func(nodes []*CategoryNode) {
+		for _, node := range nodes {
+			categories = append(categories, &node.Category)
+			items = append(items, node.Items...)
+			walk(node.Children)
+		}
+	}
+	walk(tree)
+
+	if err := s.localizeCategoryPtrs(ctx, locales, categories); err != nil {
+		return err
+	}
+	return s.LocalizeMenuItems(ctx, locales, items)
+}
+
+// localizeCategoryPtrs replaces names of the referenced categories with their best available translation
+func (s *menuService) localizeCategoryPtrs(ctx context.Context, locales []string, categories []*Category) error {
+	for _, category := range categories {
+		category.Locale = s.defaultLocale
+	}
+	locales = s.translationLocales(locales)
+	if len(locales) == 0 || len(categories) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, len(categories))
+	for i, category := range categories {
+		ids[i] = category.ID
+	}
+	translations, err := s.repo.GetCategoryTranslations(ctx, ids, locales)
+	if err != nil {
+		return apperrors.WrapError(500, "failed to load category translations", err)
+	}
+
+	names := make(map[uuid.UUID]map[string]string)
+	for _, t := range translations {
+		if names[t.CategoryID] == nil {
+			names[t.CategoryID] = make(map[string]string)
+		}
+		names[t.CategoryID][t.Locale] = t.Name
+	}
+	for _, category := range categories {
+		available := make(map[string]bool, len(names[category.ID]))
+		for locale := range names[category.ID] {
+			available[locale] = true
+		}
+		if locale := pickLocale(locales, available); locale != "" {
+			category.Name = names[category.ID][locale]
+			category.Locale = locale
+		}
+	}
+	return nil
+}
+
+// UpsertTranslations creates or replaces translations in bulk
+func (s *menuService) UpsertTranslations(ctx context.Context, items []MenuItemTranslation, categories []CategoryTranslation) error {
+	// Locales are matched case-insensitively; the default language lives in the base columns (BUSINESS LOGIC)
+	for i := range items {
+		items[i].Locale = strings.ToLower(items[i].Locale)
+		if items[i].Locale == s.defaultLocale {
+			return apperrors.NewValidationError("translations cannot use the default locale " + s.defaultLocale + "; update the menu item instead")
+		}
+	}
+	for i := range categories {
+		categories[i].Locale = strings.ToLower(categories[i].Locale)
+		if categories[i].Locale == s.defaultLocale {
+			return apperrors.NewValidationError("translations cannot use the default locale " + s.defaultLocale + "; update the category instead")
+		}
+	}
+
+	if err := s.repo.UpsertTranslations(ctx, items, categories); err != nil {
+		return apperrors.WrapError(500, "failed to save translations", err)
+	}
+	return nil
+}
+
+// DeleteMenuItemTranslation deletes one locale of a menu item's translations
+func (s *menuService) DeleteMenuItemTranslation(ctx context.Context, itemID uuid.UUID, locale string) error {
+	if err := s.repo.DeleteMenuItemTranslation(ctx, itemID, strings.ToLower(locale)); err != nil {
+		return apperrors.WrapError(500, "failed to delete translation", err)
+	}
+	return nil
+}
+
+// GetMissingTranslations reports live menu items and categories not yet translated into the locale
+func (s *menuService) GetMissingTranslations(ctx context.Context, locale string) (*MissingTranslations, error) {
+	locale = strings.ToLower(locale)
+	if locale == s.defaultLocale {
+		return &MissingTranslations{Locale: locale, MenuItems: []TranslationGap{}, Categories: []TranslationGap{}}, nil
+	}
+
+	missing, err := s.repo.ListMissingTranslations(ctx, locale)
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to list missing translations", err)
+	}
+	return missing, nil
+}
+
+// ImportMenu creates menu items from validated import rows, creating any categories that do not exist yet.
+// On a dry run nothing is written and the result describes what would have been created.
+func (s *menuService) ImportMenu(ctx context.Context, rows []CreateMenuItemRequest, dryRun bool) (*ImportResult, error) {
+	// Shape validation of every row already done by handler using ValidateImportRows
+	categories, err := s.repo.ListCategories(ctx, false)
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to list categories", err)
+	}
+	categoryIDs := make(map[string]uuid.UUID, len(categories))
+	for _, category := range categories {
+		categoryIDs[strings.ToLower(category.Name)] = category.ID
+	}
+
+	// Category names match case-insensitively, like the live unique index (BUSINESS LOGIC)
+	var newCategories []*Category
+	result := &ImportResult{DryRun: dryRun, Rows: len(rows), CategoriesCreated: []string{}, Errors: []ImportRowError{}}
+	now := time.Now()
+	nextSortOrder := make(map[uuid.UUID]int)
+	items := make([]*MenuItem, 0, len(rows))
+	for _, row := range rows {
+		key := strings.ToLower(row.Category)
+		categoryID, ok := categoryIDs[key]
+		if !ok {
+			categoryID = uuid.New()
+			categoryIDs[key] = categoryID
+			newCategories = append(newCategories, &Category{ID: categoryID, Name: row.Category})
+			result.CategoriesCreated = append(result.CategoriesCreated, row.Category)
+		}
+
+		// Imported items keep their upload order within each category
+		items = append(items, &MenuItem{
+			ID:                uuid.New(),
+			Name:              row.Name,
+			Description:       row.Description,
+			Price:             row.Price,
+			CategoryID:        categoryID,
+			AvalabilityStatus: ItemStatus(row.Status),
+			SortOrder:         nextSortOrder[categoryID],
+			CreatedAt:         now,
+		})
+		nextSortOrder[categoryID]++
+	}
+	result.ItemsCreated = len(items)
+
+	if dryRun {
+		return result, nil
+	}
+
+	if err := s.repo.ImportMenu(ctx, newCategories, items); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return nil, apperrors.WrapError(409, "a category was created concurrently, retry the import", nil)
+		}
+		return nil, apperrors.WrapError(500, "failed to import menu", err)
+	}
+	result.Applied = true
+	return result, nil
+}
+
+// ExportMenu returns every live menu item in the import row format, so an export can be imported again
+func (s *menuService) ExportMenu(ctx context.Context) ([]CreateMenuItemRequest, error) {
+	categories, err := s.repo.ListCategories(ctx, false)
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to list categories", err)
+	}
+	categoryNames := make(map[uuid.UUID]string, len(categories))
+	for _, category := range categories {
+		categoryNames[category.ID] = category.Name
+	}
+
+	items, err := s.repo.ListAllMenuItems(ctx)
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to list menu items", err)
+	}
+
+	rows := make([]CreateMenuItemRequest, 0, len(items))
+	for _, item := range items {
+		rows = append(rows, CreateMenuItemRequest{
+			Name:        item.Name,
+			Description: item.Description,
+			Price:       item.Price,
+			Category:    categoryNames[item.CategoryID],
+			Status:      string(item.AvalabilityStatus),
+		})
+	}
+	return rows, nil
+}
+
+// SchedulePriceChange records a future price for a menu item; the price scheduler applies it at effectiveFrom
+func (s *menuService) SchedulePriceChange(ctx context.Context, itemID uuid.UUID, price float64, effectiveFrom time.Time) (*MenuItemPrice, error) {
+	// Immediate changes go through UpdateMenuItem so the menu and the history never disagree (BUSINESS LOGIC)
+	now := time.Now()
+	if !effectiveFrom.After(now) {
+		return nil, apperrors.NewValidationError("effective_from must be in the future")
+	}
+
+	change := &MenuItemPrice{
+		ID:            uuid.New(),
+		MenuItemID:    itemID,
+		Price:         price,
+		EffectiveFrom: effectiveFrom,
+		CreatedAt:     now,
+	}
+	if err := s.repo.AddMenuItemPrice(ctx, change); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return nil, apperrors.WrapError(409, "a price change is already scheduled at that time", nil)
+		}
+		return nil, apperrors.WrapError(500, "failed to schedule price change", err)
+	}
+	return change, nil
+}
+
+// CancelPriceChange removes a scheduled price change that has not taken effect
+func (s *menuService) CancelPriceChange(ctx context.Context, itemID uuid.UUID, priceID uuid.UUID) error {
+	if err := s.repo.DeleteScheduledPrice(ctx, itemID, priceID); err != nil {
+		return apperrors.WrapError(500, "failed to cancel price change", err)
+	}
+	return nil
+}
+
+// GetPriceHistory returns past, current and scheduled prices of a menu item
+func (s *menuService) GetPriceHistory(ctx context.Context, itemID uuid.UUID) ([]MenuItemPrice, error) {
+	if _, err := s.repo.GetMenuItem(ctx, itemID); err != nil {
+		return nil, apperrors.WrapError(500, "failed to retrieve menu item", err)
+	}
+	prices, err := s.repo.ListMenuItemPrices(ctx, itemID)
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to list menu item prices", err)
+	}
+	return prices, nil
+}
+
+// GetPriceAt returns the price of a menu item in effect at a point in time
+func (s *menuService) GetPriceAt(ctx context.Context, itemID uuid.UUID, at time.Time) (*MenuItemPrice, error) {
+	price, err := s.repo.GetMenuItemPriceAt(ctx, itemID, at)
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to retrieve menu item price", err)
+	}
+	return price, nil
+}
+
+// ApplyDuePrices brings menu item prices up to date with scheduled changes that have come into effect
+func (s *menuService) ApplyDuePrices(ctx context.Context) (int, error) {
+	applied, err := s.repo.ApplyDuePrices(ctx, time.Now())
+	if err != nil {
+		return 0, apperrors.WrapError(500, "failed to apply scheduled prices", err)
+	}
+	return applied, nil
+}
@@ -0,0 +1,160 @@
+package menu
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// menuCSVHeader is the column layout written by exports; imports accept these columns in any order
+var menuCSVHeader = []string{"name", "description", "price", "category", "status"}
+
+// maxImportRows caps the number of rows accepted by a single import
+const maxImportRows = 5000
+
+// menuExport is the JSON document written by exports; imports also accept a bare array of rows
+type menuExport struct {
+	Items []CreateMenuItemRequest `json:"items"`
+}
+
+// ParseMenuImport decodes an import upload into rows. Malformed documents return an error;
+// cells that cannot be converted (such as a non-numeric price) are reported per row instead.
+func ParseMenuImport(format ImportFormat, r io.Reader) ([]CreateMenuItemRequest, []ImportRowError, error) {
+	var (
+		rows      []CreateMenuItemRequest
+		rowErrors []ImportRowError
+		err       error
+	)
+	switch format {
+	case ImportFormatCSV:
+		rows, rowErrors, err = parseMenuCSV(r)
+	case ImportFormatJSON:
+		rows, err = parseMenuJSON(r)
+	default:
+		return nil, nil, fmt.Errorf("unsupported import format: %s", format)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil, fmt.Errorf("import contains no rows")
+	}
+	if len(rows) > maxImportRows {
+		return nil, nil, fmt.Errorf("import contains %d rows, the maximum is %d", len(rows), maxImportRows)
+	}
+	return rows, rowErrors, nil
+}
+
+func parseMenuCSV(r io.Reader) ([]CreateMenuItemRequest, []ImportRowError, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid CSV header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if _, dup := columns[name]; dup {
+			return nil, nil, fmt.Errorf("duplicate CSV column: %s", name)
+		}
+		columns[name] = i
+	}
+	for _, required := range []string{"name", "price", "category"} {
+		if _, ok := columns[required]; !ok {
+			return nil, nil, fmt.Errorf("missing CSV column: %s", required)
+		}
+	}
+
+	cell := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return record[i]
+		}
+		return ""
+	}
+
+	var rows []CreateMenuItemRequest
+	var rowErrors []ImportRowError
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+
+		row := CreateMenuItemRequest{
+			Name:        cell(record, "name"),
+			Description: cell(record, "description"),
+			Category:    cell(record, "category"),
+			Status:      cell(record, "status"),
+		}
+		if price := strings.TrimSpace(cell(record, "price")); price != "" {
+			row.Price, err = strconv.ParseFloat(price, 64)
+			if err != nil {
+				rowErrors = append(rowErrors, ImportRowError{
+					Row:    len(rows) + 1,
+					Errors: []string{"price must be a number: " + price},
+				})
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, rowErrors, nil
+}
+
+func parseMenuJSON(r io.Reader) ([]CreateMenuItemRequest, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []CreateMenuItemRequest
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		err = json.Unmarshal(trimmed, &rows)
+	} else {
+		var doc menuExport
+		err = json.Unmarshal(trimmed, &doc)
+		rows = doc.Items
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	return rows, nil
+}
+
+// WriteMenuExport encodes rows in the given format, readable again by ParseMenuImport
+func WriteMenuExport(format ImportFormat, w io.Writer, rows []CreateMenuItemRequest) error {
+	switch format {
+	case ImportFormatCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(menuCSVHeader); err != nil {
+			return err
+		}
+		for _, row := range rows {
+			record := []string{row.Name, row.Description, strconv.FormatFloat(row.Price, 'f', -1, 64), row.Category, row.Status}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	case ImportFormatJSON:
+		if rows == nil {
+			rows = []CreateMenuItemRequest{}
+		}
+		return json.NewEncoder(w).Encode(menuExport{Items: rows})
+	default:
+		return fmt.Errorf("unsupported export format: %s", format)
+	}
+}
@@ -1,5 +1,15 @@
 package menu
 
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
 // CreateMenuItemRequest represents the request to create a menu item
 type CreateMenuItemRequest struct {
 	Name        string  `json:"name" validate:"required,min=1,max=255"`
@@ -16,6 +26,7 @@ type UpdateMenuItemRequest struct {
 	Price       float64 `json:"price" validate:"omitempty,gt=0"`
 	Category    string  `json:"category" validate:"omitempty,min=1,max=100"`
 	Status      string  `json:"availability_status" validate:"omitempty,oneof=in_stock out_of_stock"`
+	Version     *int    `json:"version" validate:"omitempty,min=1"` // version being updated, if not sent in If-Match
 }
 
 // ListMenuItemsRequest represents the request to list menu items with pagination
@@ -26,12 +37,15 @@ type ListMenuItemsRequest struct {
 
 // CreateCategoryRequest represents the request to create a category
 type CreateCategoryRequest struct {
-	Name string `json:"name" validate:"required,min=1,max=100"`
+	Name      string     `json:"name" validate:"required,min=1,max=100"`
+	ParentID  *uuid.UUID `json:"parent_id" validate:"omitempty"`
+	SortOrder int        `json:"sort_order" validate:"min=0"`
 }
 
 // UpdateCategoryRequest represents the request to update a category
 type UpdateCategoryRequest struct {
-	Name string `json:"name" validate:"required,min=1,max=100"`
+	Name    string `json:"name" validate:"required,min=1,max=100"`
+	Version *int   `json:"version" validate:"omitempty,min=1"` // version being updated, if not sent in If-Match
 }
 
 // CategoryIDByNameRequest represents the request to get category ID by name
@@ -39,6 +53,47 @@ type CategoryIDByNameRequest struct {
 	Name string `query:"name" validate:"required,min=1,max=100"`
 }
 
+// MoveRequest represents a single repositioning of a category or menu item
+type MoveRequest struct {
+	Type       string     `json:"type" validate:"required,oneof=category menu_item"`
+	ID         uuid.UUID  `json:"id" validate:"required"`
+	ParentID   *uuid.UUID `json:"parent_id" validate:"omitempty"`
+	CategoryID *uuid.UUID `json:"category_id" validate:"omitempty"`
+	SortOrder  int        `json:"sort_order" validate:"min=0"`
+}
+
+// ReorderRequest represents a batch of moves applied atomically
+type ReorderRequest struct {
+	Moves []MoveRequest `json:"moves" validate:"required,min=1,max=500,dive"`
+}
+
+// MenuItemTranslationRequest represents a menu item's name and description in one locale
+type MenuItemTranslationRequest struct {
+	MenuItemID  uuid.UUID `json:"menu_item_id" validate:"required"`
+	Locale      string    `json:"locale" validate:"required,bcp47_language_tag"`
+	Name        string    `json:"name" validate:"required,min=1,max=100"`
+	Description string    `json:"description" validate:"max=1000"`
+}
+
+// CategoryTranslationRequest represents a category's name in one locale
+type CategoryTranslationRequest struct {
+	CategoryID uuid.UUID `json:"category_id" validate:"required"`
+	Locale     string    `json:"locale" validate:"required,bcp47_language_tag"`
+	Name       string    `json:"name" validate:"required,min=1,max=100"`
+}
+
+// UpsertTranslationsRequest represents a bulk create-or-replace of translations
+type UpsertTranslationsRequest struct {
+	MenuItems  []MenuItemTranslationRequest `json:"menu_items" validate:"max=1000,dive"`
+	Categories []CategoryTranslationRequest `json:"categories" validate:"max=1000,dive"`
+}
+
+// SchedulePriceRequest represents a future price change of a menu item
+type SchedulePriceRequest struct {
+	Price         float64   `json:"price" validate:"required,gt=0"`
+	EffectiveFrom time.Time `json:"effective_from" validate:"required"`
+}
+
 // ValidateCreateMenuItem validates the create menu item request
 func ValidateCreateMenuItem(req CreateMenuItemRequest) error {
 	return ValidateStruct(req)
@@ -68,3 +123,80 @@ func ValidateUpdateCategory(req UpdateCategoryRequest) error {
 func ValidateCategoryIDByName(req CategoryIDByNameRequest) error {
 	return ValidateStruct(req)
 }
+
+// ValidateReorder validates the reorder request
+func ValidateReorder(req ReorderRequest) error {
+	return ValidateStruct(req)
+}
+
+// ValidateUpsertTranslations validates the bulk translations request
+func ValidateUpsertTranslations(req UpsertTranslationsRequest) error {
+	if err := ValidateStruct(req); err != nil {
+		return err
+	}
+	if len(req.MenuItems) == 0 && len(req.Categories) == 0 {
+		return errors.New("at least one menu item or category translation is required")
+	}
+	return nil
+}
+
+// ValidateLocale validates a locale tag such as "fr" or "pt-BR"
+func ValidateLocale(locale string) error {
+	return GetValidator().Var(locale, "required,bcp47_language_tag")
+}
+
+// ValidateImportRows normalizes import rows in place and checks each against the CreateMenuItemRequest rules.
+// Errors already found while parsing are merged in, and the result is ordered by row.
+func ValidateImportRows(rows []CreateMenuItemRequest, parseErrors []ImportRowError) []ImportRowError {
+	byRow := make(map[int][]string, len(parseErrors))
+	for _, e := range parseErrors {
+		byRow[e.Row] = append(byRow[e.Row], e.Errors...)
+	}
+
+	rowErrors := []ImportRowError{}
+	for i := range rows {
+		row := &rows[i]
+		row.Name = strings.TrimSpace(row.Name)
+		row.Description = strings.TrimSpace(row.Description)
+		row.Category = strings.TrimSpace(row.Category)
+		row.Status = strings.TrimSpace(row.Status)
+		if row.Status == "" {
+			row.Status = string(ItemStatusInStock)
+		}
+
+		messages := byRow[i+1]
+		if err := ValidateCreateMenuItem(*row); err != nil {
+			var fieldErrors validator.ValidationErrors
+			if errors.As(err, &fieldErrors) {
+				for _, fe := range fieldErrors {
+					field := strings.ToLower(fe.Field())
+					if hasFieldMessage(byRow[i+1], field) {
+						continue // the parse error already explains this field
+					}
+					messages = append(messages, fmt.Sprintf("%s failed on the '%s' rule", field, fe.Tag()))
+				}
+			} else {
+				messages = append(messages, err.Error())
+			}
+		}
+		if len(messages) > 0 {
+			rowErrors = append(rowErrors, ImportRowError{Row: i + 1, Errors: messages})
+		}
+	}
+	return rowErrors
+}
+
+// hasFieldMessage reports whether one of the messages is about the given field
+func hasFieldMessage(messages []string, field string) bool {
+	for _, m := range messages {
+		if strings.HasPrefix(m, field+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateSchedulePrice validates the schedule price change request
+func ValidateSchedulePrice(req SchedulePriceRequest) error {
+	return ValidateStruct(req)
+}
@@ -0,0 +1,385 @@
+package middleware
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	apperrors "restaurant/internal/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// GuestTokenHeader is an alternative to the Authorization header for guest tokens, for guest apps that
+// cannot set Authorization
+const GuestTokenHeader = "X-Session-Token"
+
+// APIKeyHeader carries the API key of a machine integration
+const APIKeyHeader = "X-API-Key"
+
+// principalKey is the Gin context key holding the authenticated Principal
+const principalKey = "auth-principal"
+
+// jwtHeader is the fixed, pre-encoded header of every token; only HS256 is issued or accepted
+var jwtHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// TokenUse says what a token may be used for
+type TokenUse string
+
+const (
+	TokenAccess  TokenUse = "access"  // short-lived staff token sent with every request
+	TokenRefresh TokenUse = "refresh" // long-lived staff token exchanged for a new pair at /auth/refresh
+	TokenGuest   TokenUse = "guest"   // guest token limited to the one session it was issued for
+)
+
+// PrincipalKind distinguishes staff from guests
+type PrincipalKind string
+
+const (
+	PrincipalStaff  PrincipalKind = "staff"
+	PrincipalGuest  PrincipalKind = "guest"
+	PrincipalAPIKey PrincipalKind = "api_key"
+)
+
+// Claims is the payload of a signed token
+type Claims struct {
+	ID        string   `json:"jti"`
+	Issuer    string   `json:"iss"`
+	Subject   string   `json:"sub"`            // staff ID, or session ID for guest tokens
+	Use       TokenUse `json:"use"`            // what the token may be used for
+	Role      string   `json:"role,omitempty"` // staff role at the time the token was issued
+	TableID   int      `json:"tbl,omitempty"`  // table a guest scanned
+	IssuedAt  int64    `json:"iat"`
+	ExpiresAt int64    `json:"exp"`
+}
+
+// Expiry returns when the token stops being accepted
+func (c *Claims) Expiry() time.Time {
+	return time.Unix(c.ExpiresAt, 0)
+}
+
+// Principal is the authenticated caller of a request
+type Principal struct {
+	Kind      PrincipalKind
+	StaffID   uuid.UUID // staff principals only
+	Role      string    // staff role, or RoleGuest for guests
+	SessionID uuid.UUID // guest principals only: the one session they may act on
+	TableID   int       // guest principals only
+	TokenID   string    // ID of the token the request was authenticated with, for revocation
+	ExpiresAt time.Time // when that token expires
+
+	APIKeyID uuid.UUID    // API key principals only
+	Scopes   []Permission // API key principals only: the permissions the key was granted
+	Quota    *Quota       // API key principals only: the key's own rate limit, if it has one
+}
+
+// IsStaff reports whether the caller is a staff member
+func (p *Principal) IsStaff() bool {
+	return p.Kind == PrincipalStaff
+}
+
+// CanAccessSession reports whether the caller may act on a session: staff and API keys may act on any, guests
+// only on the session their token was issued for
+func (p *Principal) CanAccessSession(sessionID uuid.UUID) bool {
+	return p.Kind != PrincipalGuest || p.SessionID == sessionID
+}
+
+// TokenPair is issued to staff at login and on refresh
+type TokenPair struct {
+	AccessToken      string    `json:"access_token"`
+	RefreshToken     string    `json:"refresh_token"`
+	TokenType        string    `json:"token_type"` // always "Bearer"
+	ExpiresAt        time.Time `json:"expires_at"` // when the access token expires
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
+
+// RevocationStore records tokens revoked before they expire
+type RevocationStore interface {
+	// RevokeToken records that a token may no longer be used; it can be forgotten after expiresAt
+	RevokeToken(ctx context.Context, id string, expiresAt time.Time) error
+
+	// IsTokenRevoked reports whether a token was revoked
+	IsTokenRevoked(ctx context.Context, id string) (bool, error)
+}
+
+// APIKeyVerifier resolves the API keys of machine integrations
+type APIKeyVerifier interface {
+	// VerifyAPIKey returns the principal of a valid key, or ErrInvalidAPIKey
+	VerifyAPIKey(ctx context.Context, key string) (*Principal, error)
+}
+
+// AuthConfig holds token signing configuration
+type AuthConfig struct {
+	Secret     []byte        // HMAC key for all tokens; must be stable across restarts
+	Issuer     string        // iss claim of issued tokens; tokens from other issuers are rejected
+	AccessTTL  time.Duration // lifetime of staff access tokens
+	RefreshTTL time.Duration // lifetime of staff refresh tokens
+	GuestTTL   time.Duration // lifetime of guest tokens issued when a table QR code is scanned
+}
+
+// DefaultAuthConfig returns the token configuration used when none is provided
+func DefaultAuthConfig() AuthConfig {
+	return AuthConfig{
+		Issuer:     "restaurant",
+		AccessTTL:  15 * time.Minute,
+		RefreshTTL: 7 * 24 * time.Hour,
+		GuestTTL:   4 * time.Hour,
+	}
+}
+
+// Authenticator issues and verifies HS256 JWTs for staff and guests
+type Authenticator struct {
+	config      AuthConfig
+	revocations RevocationStore
+	apiKeys     APIKeyVerifier
+}
+
+// NewAuthenticator creates a new authenticator. Without a secret, a random one is generated, so every token
+// stops working when the process restarts. apiKeys may be nil if API keys are not accepted.
+func NewAuthenticator(config AuthConfig, revocations RevocationStore, apiKeys APIKeyVerifier) *Authenticator {
+	defaults := DefaultAuthConfig()
+	if config.Issuer == "" {
+		config.Issuer = defaults.Issuer
+	}
+	if config.AccessTTL <= 0 {
+		config.AccessTTL = defaults.AccessTTL
+	}
+	if config.RefreshTTL <= 0 {
+		config.RefreshTTL = defaults.RefreshTTL
+	}
+	if config.GuestTTL <= 0 {
+		config.GuestTTL = defaults.GuestTTL
+	}
+	if len(config.Secret) == 0 {
+		config.Secret = make([]byte, 32)
+		if _, err := rand.Read(config.Secret); err != nil {
+			panic("middleware: failed to generate auth secret: " + err.Error())
+		}
+	}
+	return &Authenticator{config: config, revocations: revocations, apiKeys: apiKeys}
+}
+
+// IssueStaffTokens issues an access and refresh token pair for a staff member
+func (a *Authenticator) IssueStaffTokens(staffID uuid.UUID, role string) (*TokenPair, error) {
+	now := time.Now()
+	access, accessClaims, err := a.issue(TokenAccess, staffID.String(), role, 0, now, a.config.AccessTTL)
+	if err != nil {
+		return nil, err
+	}
+	refresh, refreshClaims, err := a.issue(TokenRefresh, staffID.String(), role, 0, now, a.config.RefreshTTL)
+	if err != nil {
+		return nil, err
+	}
+	return &TokenPair{
+		AccessToken:      access,
+		RefreshToken:     refresh,
+		TokenType:        "Bearer",
+		ExpiresAt:        accessClaims.Expiry(),
+		RefreshExpiresAt: refreshClaims.Expiry(),
+	}, nil
+}
+
+// IssueGuestToken issues a token that lets a guest act on one session only
+func (a *Authenticator) IssueGuestToken(sessionID uuid.UUID, tableID int) (string, time.Time, error) {
+	token, claims, err := a.issue(TokenGuest, sessionID.String(), "", tableID, time.Now(), a.config.GuestTTL)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return token, claims.Expiry(), nil
+}
+
+// VerifyToken checks a token's signature, issuer, use and expiry, and that it has not been revoked
+func (a *Authenticator) VerifyToken(ctx context.Context, token string, use TokenUse) (*Claims, error) {
+	claims, err := a.verify(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Use != use {
+		return nil, apperrors.ErrInvalidToken
+	}
+	return claims, nil
+}
+
+// verify checks a token of any use
+func (a *Authenticator) verify(ctx context.Context, token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != jwtHeader {
+		return nil, apperrors.ErrInvalidToken
+	}
+	if !hmac.Equal([]byte(parts[2]), []byte(a.sign(parts[0]+"."+parts[1]))) {
+		return nil, apperrors.ErrInvalidToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, apperrors.ErrInvalidToken
+	}
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, apperrors.ErrInvalidToken
+	}
+	if claims.Issuer != a.config.Issuer || claims.ID == "" || !time.Now().Before(claims.Expiry()) {
+		return nil, apperrors.ErrInvalidToken
+	}
+
+	if a.revocations != nil {
+		revoked, err := a.revocations.IsTokenRevoked(ctx, claims.ID)
+		if err != nil {
+			return nil, apperrors.NewInternalError("failed to check token revocation", err)
+		}
+		if revoked {
+			return nil, apperrors.ErrInvalidToken
+		}
+	}
+	return &claims, nil
+}
+
+// RevokeToken revokes a token before it expires
+func (a *Authenticator) RevokeToken(ctx context.Context, id string, expiresAt time.Time) error {
+	if a.revocations == nil {
+		return apperrors.NewInternalError("token revocation is not configured", nil)
+	}
+	if err := a.revocations.RevokeToken(ctx, id, expiresAt); err != nil {
+		return apperrors.NewInternalError("failed to revoke token", err)
+	}
+	return nil
+}
+
+// Authenticate is a Gin middleware that resolves the caller from an API key in the X-API-Key header, a bearer
+// token in the Authorization header, or a guest token in the X-Session-Token header. Requests without a token
+// pass through anonymously, so public routes keep working; route groups declare what they need with
+// RoutePermissions. A key or token that is present but invalid, expired or revoked is rejected.
+func (a *Authenticator) Authenticate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if key := c.GetHeader(APIKeyHeader); key != "" {
+			if a.apiKeys == nil {
+				HandleError(c, apperrors.ErrInvalidAPIKey)
+				c.Abort()
+				return
+			}
+			principal, err := a.apiKeys.VerifyAPIKey(c.Request.Context(), key)
+			if err != nil {
+				HandleError(c, err)
+				c.Abort()
+				return
+			}
+			c.Set(principalKey, principal)
+			c.Next()
+			return
+		}
+
+		token, guestHeader := requestToken(c)
+		if token == "" {
+			c.Next()
+			return
+		}
+
+		principal, err := a.principal(c.Request.Context(), token, guestHeader)
+		if err != nil {
+			HandleError(c, err)
+			c.Abort()
+			return
+		}
+
+		c.Set(principalKey, principal)
+		c.Next()
+	}
+}
+
+// principal verifies a request token and turns its claims into a Principal. A bearer token may be a staff
+// access token or a guest token; the guest header only takes guest tokens.
+func (a *Authenticator) principal(ctx context.Context, token string, guestHeader bool) (*Principal, error) {
+	claims, err := a.verify(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Use != TokenGuest && (guestHeader || claims.Use != TokenAccess) {
+		return nil, apperrors.ErrInvalidToken
+	}
+
+	id, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return nil, apperrors.ErrInvalidToken
+	}
+	principal := &Principal{TokenID: claims.ID, ExpiresAt: claims.Expiry()}
+	if claims.Use == TokenGuest {
+		principal.Kind = PrincipalGuest
+		principal.Role = RoleGuest
+		principal.SessionID = id
+		principal.TableID = claims.TableID
+	} else {
+		principal.Kind = PrincipalStaff
+		principal.StaffID = id
+		principal.Role = claims.Role
+	}
+	return principal, nil
+}
+
+// issue signs a new token
+func (a *Authenticator) issue(use TokenUse, subject string, role string, tableID int, now time.Time, ttl time.Duration) (string, *Claims, error) {
+	claims := &Claims{
+		ID:        uuid.NewString(),
+		Issuer:    a.config.Issuer,
+		Subject:   subject,
+		Use:       use,
+		Role:      role,
+		TableID:   tableID,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(ttl).Unix(),
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", nil, apperrors.NewInternalError("failed to encode token", err)
+	}
+	unsigned := jwtHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return unsigned + "." + a.sign(unsigned), claims, nil
+}
+
+// sign returns the base64url HMAC-SHA256 signature of a token's header and payload
+func (a *Authenticator) sign(unsigned string) string {
+	h := hmac.New(sha256.New, a.config.Secret)
+	h.Write([]byte(unsigned))
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
+
+// requestToken extracts the token of a request, reporting whether it came from the guest header
+func requestToken(c *gin.Context) (string, bool) {
+	if header := c.GetHeader("Authorization"); header != "" {
+		scheme, token, found := strings.Cut(header, " ")
+		if found && strings.EqualFold(scheme, "Bearer") {
+			return strings.TrimSpace(token), false
+		}
+		return header, false // malformed; rejected by verification
+	}
+	return c.GetHeader(GuestTokenHeader), true
+}
+
+// GetPrincipal returns the authenticated caller of a request, if any
+func GetPrincipal(c *gin.Context) (*Principal, bool) {
+	value, exists := c.Get(principalKey)
+	if !exists {
+		return nil, false
+	}
+	principal, ok := value.(*Principal)
+	return principal, ok
+}
+
+// SessionAccess reports whether the caller may act on a session, writing a 403 response if not. Handlers call
+// it after RoutePermissions has authorized the route.
+func SessionAccess(c *gin.Context, sessionID uuid.UUID) bool {
+	principal, ok := GetPrincipal(c)
+	if !ok {
+		HandleError(c, apperrors.ErrUnauthorized)
+		return false
+	}
+	if !principal.CanAccessSession(sessionID) {
+		HandleError(c, apperrors.NewForbiddenError("guest tokens only grant access to their own session"))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CacheRevalidate lets browsers and shared caches store a response but makes them check its ETag before every
+// use, so a change shows up on the next request while unchanged responses cost a 304 with no body
+const CacheRevalidate = "public, no-cache"
+
+// CacheRevalidatePrivate is CacheRevalidate for responses only the requesting browser may store, such as
+// reads that need a staff token
+const CacheRevalidatePrivate = "private, no-cache"
+
+// RouteCacheControl declares the Cache-Control policy of GET routes, keyed like RoutePermissions by method and
+// full route path. Routes left out are passed through untouched.
+type RouteCacheControl map[string]string
+
+// Conditional is a Gin group middleware for the routes in the map. It gives successful responses a strong ETag
+// computed from the body and the route's Cache-Control, and answers a matching If-None-Match with 304 Not
+// Modified instead of the body. Any write that changes what a route returns changes its ETag, so nothing has
+// to be invalidated. A handler that already set an ETag, such as a version ETag, or a Cache-Control, such as a
+// private one for a staff-only variant of a public read, keeps it. vary lists request headers the responses
+// depend on, such as Accept-Language.
+func (routes RouteCacheControl) Conditional(vary ...string) gin.HandlerFunc {
+	varyHeader := strings.Join(vary, ", ")
+	return func(c *gin.Context) {
+		cacheControl, ok := routes[c.Request.Method+" "+c.FullPath()]
+		if !ok {
+			c.Next()
+			return
+		}
+
+		original := c.Writer
+		buffered := &bufferedWriter{ResponseWriter: original}
+		c.Writer = buffered
+		c.Next()
+		c.Writer = original
+
+		if buffered.Status() != http.StatusOK || len(c.Errors) > 0 {
+			// Errors are written by ErrorHandler once this returns, so an empty body must not send headers yet
+			if buffered.body.Len() > 0 {
+				original.Write(buffered.body.Bytes())
+			}
+			return
+		}
+
+		header := original.Header()
+		etag := header.Get("ETag")
+		if etag == "" {
+			sum := sha256.Sum256(buffered.body.Bytes())
+			etag = `"` + base64.RawURLEncoding.EncodeToString(sum[:16]) + `"`
+			header.Set("ETag", etag)
+		}
+		if header.Get("Cache-Control") == "" {
+			header.Set("Cache-Control", cacheControl)
+		}
+		if varyHeader != "" {
+			header.Add("Vary", varyHeader)
+		}
+
+		if etagMatches(c.GetHeader("If-None-Match"), etag) {
+			header.Del("Content-Type")
+			original.WriteHeader(http.StatusNotModified)
+			original.WriteHeaderNow()
+			return
+		}
+		original.Write(buffered.body.Bytes())
+	}
+}
+
+// etagMatches reports whether an If-None-Match header lists the ETag. If-None-Match uses weak comparison, so a
+// W/ prefix is ignored.
+func etagMatches(ifNoneMatch string, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// bufferedWriter holds back a response body so its ETag can be computed before anything is sent
+type bufferedWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
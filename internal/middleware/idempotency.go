@@ -0,0 +1,266 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	apperrors "restaurant/internal/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxIdempotencyKeyLength bounds Idempotency-Key; clients normally send a UUID
+const maxIdempotencyKeyLength = 255
+
+// IdempotencyStore keeps the records of an Idempotency middleware. Claiming a key must be atomic, so replicas
+// sharing a store never run the same request twice.
+type IdempotencyStore interface {
+	// Begin claims key for a request with fingerprint and returns nil if the request should run. Otherwise it
+	// returns the record already under key. A record whose request has been running for longer than lockTimeout
+	// is taken to be abandoned and is claimed again; an expired record is replaced.
+	Begin(ctx context.Context, key string, fingerprint string, ttl time.Duration, lockTimeout time.Duration) (*IdempotencyRecord, error)
+
+	// Complete stores the response of the request holding key
+	Complete(ctx context.Context, key string, response StoredResponse) error
+
+	// Release drops the claim on key, so the request can run again
+	Release(ctx context.Context, key string) error
+
+	// Cleanup removes expired records
+	Cleanup(ctx context.Context) error
+}
+
+// IdempotencyRecord is what a store holds under a key
+type IdempotencyRecord struct {
+	Fingerprint string          // hash of the method, path and body of the request that claimed the key
+	Response    *StoredResponse // nil while that request is still running
+}
+
+// StoredResponse is a response kept for replay
+type StoredResponse struct {
+	StatusCode  int
+	ContentType string
+	Body        []byte
+}
+
+// IdempotencyConfig holds how long Idempotency-Key records are kept
+type IdempotencyConfig struct {
+	TTL             time.Duration // how long a key can be retried
+	LockTimeout     time.Duration // after which a request still running with a key is taken to have died
+	CleanupInterval time.Duration // how often expired records are removed
+}
+
+// DefaultIdempotencyConfig keeps keys for a day, long enough for a tablet that lost its connection overnight
+func DefaultIdempotencyConfig() IdempotencyConfig {
+	return IdempotencyConfig{
+		TTL:             24 * time.Hour,
+		LockTimeout:     time.Minute,
+		CleanupInterval: time.Hour,
+	}
+}
+
+// RouteIdempotency lists the routes that honour an Idempotency-Key header, keyed like RoutePermissions by
+// method and full route path
+type RouteIdempotency map[string]bool
+
+// Idempotency lets clients retry POST requests safely. The first request made with an Idempotency-Key runs and
+// its response is stored; a retry with the same key and payload gets the stored response back instead of
+// running again.
+type Idempotency struct {
+	config IdempotencyConfig
+	store  IdempotencyStore
+	routes RouteIdempotency
+	mu     sync.RWMutex
+	stop   chan struct{}
+	done   chan struct{}
+	once   sync.Once
+}
+
+// NewIdempotency creates the middleware keeping records in store and starts removing expired records. Zero
+// config fields are taken from DefaultIdempotencyConfig.
+func NewIdempotency(config IdempotencyConfig, store IdempotencyStore) *Idempotency {
+	defaults := DefaultIdempotencyConfig()
+	if config.TTL <= 0 {
+		config.TTL = defaults.TTL
+	}
+	if config.LockTimeout <= 0 {
+		config.LockTimeout = defaults.LockTimeout
+	}
+	if config.CleanupInterval <= 0 {
+		config.CleanupInterval = defaults.CleanupInterval
+	}
+
+	idem := &Idempotency{
+		config: config,
+		store:  store,
+		routes: make(RouteIdempotency),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go idem.cleanup()
+	return idem
+}
+
+// Attach makes routes honour Idempotency-Key; route groups call it from RegisterRoutes
+func (idem *Idempotency) Attach(routes RouteIdempotency) {
+	idem.mu.Lock()
+	defer idem.mu.Unlock()
+	for route, enabled := range routes {
+		idem.routes[route] = enabled
+	}
+}
+
+// attached reports whether a route honours Idempotency-Key
+func (idem *Idempotency) attached(method string, fullPath string) bool {
+	idem.mu.RLock()
+	defer idem.mu.RUnlock()
+	return idem.routes[method+" "+fullPath]
+}
+
+// cleanup periodically removes expired records from the store
+func (idem *Idempotency) cleanup() {
+	defer close(idem.done)
+
+	ticker := time.NewTicker(idem.config.CleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-idem.stop:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), idem.config.CleanupInterval)
+			if err := idem.store.Cleanup(ctx); err != nil {
+				log.Printf("idempotency: failed to remove expired keys: %v", err)
+			}
+			cancel()
+		}
+	}
+}
+
+// Stop halts cleanup and waits for an in-flight run to finish. It is safe to call more than once.
+func (idem *Idempotency) Stop(ctx context.Context) error {
+	idem.once.Do(func() { close(idem.stop) })
+	select {
+	case <-idem.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Middleware is a Gin middleware for the attached routes. Requests without an Idempotency-Key run as usual.
+// Keys are scoped to the caller, so two callers cannot see each other's responses. A key reused with a
+// different payload is rejected with 422, and a retry that arrives while the first request is still running
+// gets a 409. Only responses below 500 that the handler wrote itself are stored; a failed request releases
+// its key so a retry runs it again. If the store fails, the request runs without protection.
+func (idem *Idempotency) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader("Idempotency-Key")
+		if key == "" || !idem.attached(c.Request.Method, c.FullPath()) {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			HandleError(c, apperrors.NewValidationError("Idempotency-Key must be at most 255 characters"))
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				HandleError(c, apperrors.ErrPayloadTooLarge)
+			} else {
+				HandleError(c, apperrors.NewValidationError("failed to read request body"))
+			}
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		identity := "ip:" + c.ClientIP()
+		if principal, ok := GetPrincipal(c); ok {
+			identity = principalIdentity(principal)
+		}
+		recordKey := identity + "|" + key
+		fingerprint := requestFingerprint(c.Request.Method, c.Request.URL.Path, body)
+
+		record, err := idem.store.Begin(c.Request.Context(), recordKey, fingerprint, idem.config.TTL, idem.config.LockTimeout)
+		if err != nil {
+			log.Printf("idempotency: failed to claim key, running request unprotected: %v", err)
+			c.Next()
+			return
+		}
+		if record != nil {
+			switch {
+			case record.Fingerprint != fingerprint:
+				HandleError(c, apperrors.ErrIdempotencyKeyReused)
+			case record.Response == nil:
+				HandleError(c, apperrors.ErrIdempotencyKeyInUse)
+			default:
+				c.Header("Idempotent-Replayed", "true")
+				c.Data(record.Response.StatusCode, record.Response.ContentType, record.Response.Body)
+			}
+			c.Abort()
+			return
+		}
+
+		original := c.Writer
+		recorder := &recordingWriter{ResponseWriter: original}
+		c.Writer = recorder
+		c.Next()
+		c.Writer = original
+
+		// The client may have gone away, which is the reason it will retry; the record must be written anyway
+		ctx := context.WithoutCancel(c.Request.Context())
+		status := recorder.Status()
+		if len(c.Errors) > 0 || status >= http.StatusInternalServerError {
+			if err := idem.store.Release(ctx, recordKey); err != nil {
+				log.Printf("idempotency: failed to release key: %v", err)
+			}
+			return
+		}
+		response := StoredResponse{
+			StatusCode:  status,
+			ContentType: recorder.Header().Get("Content-Type"),
+			Body:        recorder.body.Bytes(),
+		}
+		if err := idem.store.Complete(ctx, recordKey, response); err != nil {
+			log.Printf("idempotency: failed to store response: %v", err)
+		}
+	}
+}
+
+// requestFingerprint hashes what makes two requests the same request
+func requestFingerprint(method string, path string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method + " " + path + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// recordingWriter keeps a copy of a response body as it is sent
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
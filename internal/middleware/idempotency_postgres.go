@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// claimQuery creates a record or takes over an expired or abandoned one in a single upsert, so only one of
+// several concurrent requests with a key gets a row back. Time is read from the database clock, which
+// replicas share.
+const claimQuery = `INSERT INTO idempotency_keys AS k (record_key, fingerprint, locked_at, expires_at)
+	VALUES ($1, $2, clock_timestamp(), clock_timestamp() + make_interval(secs => $3))
+	ON CONFLICT (record_key) DO UPDATE SET
+		fingerprint = EXCLUDED.fingerprint,
+		status_code = NULL,
+		content_type = NULL,
+		response_body = NULL,
+		locked_at = EXCLUDED.locked_at,
+		expires_at = EXCLUDED.expires_at
+	WHERE k.expires_at <= clock_timestamp()
+		OR (k.status_code IS NULL AND k.fingerprint = EXCLUDED.fingerprint
+			AND k.locked_at <= clock_timestamp() - make_interval(secs => $4))
+	RETURNING record_key`
+
+// PostgresIdempotencyStore implements IdempotencyStore in PostgreSQL, so a retry is recognised by any replica
+type PostgresIdempotencyStore struct {
+	db *sql.DB
+}
+
+// NewPostgresIdempotencyStore creates a store using the idempotency_keys table
+func NewPostgresIdempotencyStore(db *sql.DB) *PostgresIdempotencyStore {
+	return &PostgresIdempotencyStore{db: db}
+}
+
+// Begin claims key, or returns the record already under it
+func (s *PostgresIdempotencyStore) Begin(ctx context.Context, key string, fingerprint string, ttl time.Duration, lockTimeout time.Duration) (*IdempotencyRecord, error) {
+	// A record can be released or removed between the claim and the read, in which case the claim is retried
+	for {
+		var claimed string
+		err := s.db.QueryRowContext(ctx, claimQuery, key, fingerprint, ttl.Seconds(), lockTimeout.Seconds()).Scan(&claimed)
+		if err == nil {
+			return nil, nil
+		}
+		if err != sql.ErrNoRows {
+			return nil, err
+		}
+
+		var record IdempotencyRecord
+		var statusCode sql.NullInt64
+		var contentType sql.NullString
+		var body []byte
+		err = s.db.QueryRowContext(ctx, "SELECT fingerprint, status_code, content_type, response_body FROM idempotency_keys WHERE record_key = $1", key).Scan(
+			&record.Fingerprint, &statusCode, &contentType, &body)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if statusCode.Valid {
+			record.Response = &StoredResponse{StatusCode: int(statusCode.Int64), ContentType: contentType.String, Body: body}
+		}
+		return &record, nil
+	}
+}
+
+// Complete stores the response of the request holding key
+func (s *PostgresIdempotencyStore) Complete(ctx context.Context, key string, response StoredResponse) error {
+	_, err := s.db.ExecContext(ctx, "UPDATE idempotency_keys SET status_code = $2, content_type = $3, response_body = $4 WHERE record_key = $1",
+		key, response.StatusCode, response.ContentType, response.Body)
+	return err
+}
+
+// Release deletes the record under key if its request has not completed
+func (s *PostgresIdempotencyStore) Release(ctx context.Context, key string) error {
+	_, err := s.db.ExecContext(ctx, "DELETE FROM idempotency_keys WHERE record_key = $1 AND status_code IS NULL", key)
+	return err
+}
+
+// Cleanup removes expired records
+func (s *PostgresIdempotencyStore) Cleanup(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, "DELETE FROM idempotency_keys WHERE expires_at <= clock_timestamp()")
+	return err
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PreferredLocales returns the locales the client asked for, most preferred first.
+// A lang= query parameter wins over the Accept-Language header. Region-specific tags are
+// followed by their base language (e.g. "fr-ca" then "fr"). Tags are lowercased.
+func PreferredLocales(c *gin.Context) []string {
+	var locales []string
+	seen := make(map[string]bool)
+	add := func(tag string) {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || tag == "*" || seen[tag] {
+			return
+		}
+		seen[tag] = true
+		locales = append(locales, tag)
+	}
+	addWithBase := func(tag string) {
+		add(tag)
+		if base, _, found := strings.Cut(tag, "-"); found {
+			add(base)
+		}
+	}
+
+	if lang := c.Query("lang"); lang != "" {
+		addWithBase(lang)
+	}
+
+	for _, tag := range parseAcceptLanguage(c.GetHeader("Accept-Language")) {
+		addWithBase(tag)
+	}
+
+	return locales
+}
+
+// parseAcceptLanguage parses an Accept-Language header into tags ordered by quality
+func parseAcceptLanguage(header string) []string {
+	type weightedTag struct {
+		tag     string
+		quality float64
+	}
+
+	var tags []weightedTag
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" {
+			continue
+		}
+		quality := 1.0
+		if q, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		if quality <= 0 {
+			continue
+		}
+		tags = append(tags, weightedTag{tag: tag, quality: quality})
+	}
+
+	// Stable sort keeps header order for equal quality values
+	sort.SliceStable(tags, func(i, j int) bool {
+		return tags[i].quality > tags[j].quality
+	})
+
+	result := make([]string, len(tags))
+	for i, t := range tags {
+		result[i] = t.tag
+	}
+	return result
+}
@@ -53,6 +53,23 @@ func GetIntQueryParam(c *gin.Context, paramName string, required bool) (int, boo
 	return intVal, true
 }
 
+// GetBoolQueryParam safely extracts and validates a boolean query parameter, defaulting to false
+func GetBoolQueryParam(c *gin.Context, paramName string) (bool, bool) {
+	value := c.Query(paramName)
+	if value == "" {
+		return false, true
+	}
+
+	boolVal, err := strconv.ParseBool(value)
+	if err != nil {
+		errResp := apperrors.NewValidationError(paramName + " must be a valid boolean")
+		HandleError(c, errResp)
+		return false, false
+	}
+
+	return boolVal, true
+}
+
 // GetStringQueryParam safely extracts a string query parameter
 func GetStringQueryParam(c *gin.Context, paramName string, required bool) (string, bool) {
 	value := c.Query(paramName)
@@ -0,0 +1,285 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	apperrors "restaurant/internal/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Names of the rate limit policies route groups attach
+const (
+	PolicyDefault    = "default"     // every route without a policy of its own
+	PolicyBrowse     = "browse"      // public reads such as the menu
+	PolicyGuestEntry = "guest-entry" // starting sessions and scanning table QR codes
+	PolicyLogin      = "login"       // staff login and token refresh
+	PolicyBooking    = "booking"     // public reservation requests
+)
+
+// RateLimitKey says whose requests share a bucket
+type RateLimitKey string
+
+const (
+	// KeyByIP gives each client IP address a bucket
+	KeyByIP RateLimitKey = "ip"
+	// KeyByPrincipal gives each API key, staff member and guest token a bucket; anonymous requests fall back to
+	// their IP address
+	KeyByPrincipal RateLimitKey = "principal"
+)
+
+// RateLimitPolicy is a named token bucket limit: Limit requests per Period, with up to Burst at once
+type RateLimitPolicy struct {
+	Limit  int
+	Period time.Duration
+	Burst  int // defaults to Limit
+	KeyBy  RateLimitKey
+}
+
+// rate returns the tokens the policy adds per second
+func (p RateLimitPolicy) rate() float64 {
+	return float64(p.Limit) / p.Period.Seconds()
+}
+
+// Quota is the rate limit of a single client, such as an API key with its own allowance
+type Quota struct {
+	RequestsPerSecond int
+	BurstSize         int // defaults to twice the rate
+}
+
+// apply replaces the limit of a policy with the quota, keeping whatever the quota leaves unset
+func (q Quota) apply(policy RateLimitPolicy) RateLimitPolicy {
+	if q.RequestsPerSecond > 0 {
+		policy.Limit, policy.Period, policy.Burst = q.RequestsPerSecond, time.Second, q.RequestsPerSecond*2
+	}
+	if q.BurstSize > 0 {
+		policy.Burst = q.BurstSize
+	}
+	return policy
+}
+
+// RateLimitConfig holds rate limiting configuration
+type RateLimitConfig struct {
+	Policies        map[string]RateLimitPolicy // by name; PolicyDefault is required
+	CleanupInterval time.Duration
+}
+
+// DefaultRateLimitConfig returns the policies used when none are configured: generous limits per caller for
+// the API as a whole and for browsing, tight limits per IP on public endpoints that create things or check
+// credentials.
+//
+// Guest entry is the exception. Guests scan from their phones on the restaurant Wi-Fi, so a whole dining room
+// shares one NAT address and a per-IP limit sized for one client would lock out every table after the first
+// few. Its limit is sized for a full room seating at once instead. Scans need a signed table token, and a table
+// holds at most one live session, so the looser limit does not let one client open sessions at will.
+func DefaultRateLimitConfig() RateLimitConfig {
+	return RateLimitConfig{
+		Policies: map[string]RateLimitPolicy{
+			PolicyDefault:    {Limit: 100, Period: time.Second, Burst: 200, KeyBy: KeyByPrincipal},
+			PolicyBrowse:     {Limit: 200, Period: time.Second, Burst: 400, KeyBy: KeyByPrincipal},
+			PolicyGuestEntry: {Limit: 120, Period: time.Minute, Burst: 60, KeyBy: KeyByIP},
+			PolicyLogin:      {Limit: 5, Period: time.Minute, Burst: 5, KeyBy: KeyByIP},
+			PolicyBooking:    {Limit: 20, Period: time.Hour, Burst: 5, KeyBy: KeyByIP},
+		},
+		CleanupInterval: 5 * time.Minute,
+	}
+}
+
+// ParseRateLimit reads a limit written as "<requests>/<period>", e.g. "10/1m"
+func ParseRateLimit(value string) (int, time.Duration, error) {
+	count, period, found := strings.Cut(value, "/")
+	if !found {
+		return 0, 0, fmt.Errorf("rate limit %q must look like 10/1m", value)
+	}
+	limit, err := strconv.Atoi(count)
+	if err != nil || limit <= 0 {
+		return 0, 0, fmt.Errorf("rate limit %q must allow a positive number of requests", value)
+	}
+	duration, err := time.ParseDuration(period)
+	if err != nil || duration <= 0 {
+		return 0, 0, fmt.Errorf("rate limit %q must have a positive period", value)
+	}
+	return limit, duration, nil
+}
+
+// RouteRateLimits declares the rate limit policy of routes, keyed like RoutePermissions by method and full
+// route path. A key without a method, such as "/menu", covers every route at or under that path. Routes not
+// covered use PolicyDefault.
+type RouteRateLimits map[string]string
+
+// RateLimiter implements token bucket rate limiting with named policies
+type RateLimiter struct {
+	config   RateLimitConfig
+	store    RateLimitStore
+	routes   RouteRateLimits
+	mu       sync.RWMutex
+	ticker   *time.Ticker
+	stopChan chan struct{}
+}
+
+// NewRateLimiter creates a new rate limiter keeping its buckets in store, or in memory if store is nil. Policies
+// without a burst allow Limit requests at once; a missing default policy is taken from DefaultRateLimitConfig.
+func NewRateLimiter(config RateLimitConfig, store RateLimitStore) *RateLimiter {
+	defaults := DefaultRateLimitConfig()
+	policies := make(map[string]RateLimitPolicy, len(config.Policies)+1)
+	for name, policy := range config.Policies {
+		if policy.Burst <= 0 {
+			policy.Burst = policy.Limit
+		}
+		if policy.KeyBy == "" {
+			policy.KeyBy = KeyByIP
+		}
+		policies[name] = policy
+	}
+	if _, ok := policies[PolicyDefault]; !ok {
+		policies[PolicyDefault] = defaults.Policies[PolicyDefault]
+	}
+	config.Policies = policies
+	if config.CleanupInterval == 0 {
+		config.CleanupInterval = defaults.CleanupInterval
+	}
+	if store == nil {
+		store = NewMemoryRateLimitStore()
+	}
+
+	rl := &RateLimiter{
+		config:   config,
+		store:    store,
+		routes:   make(RouteRateLimits),
+		stopChan: make(chan struct{}),
+	}
+
+	// Start cleanup goroutine
+	rl.ticker = time.NewTicker(config.CleanupInterval)
+	go rl.cleanup()
+
+	return rl
+}
+
+// Attach applies policies to routes; route groups call it from RegisterRoutes. It panics on an unknown
+// policy, which is a programming error.
+func (rl *RateLimiter) Attach(limits RouteRateLimits) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	for route, name := range limits {
+		if _, ok := rl.config.Policies[name]; !ok {
+			panic(fmt.Sprintf("middleware: rate limit policy %q attached to %s is not configured", name, route))
+		}
+		rl.routes[route] = name
+	}
+}
+
+// policyFor returns the name of the policy of a route: an exact entry, else the longest path entry covering it,
+// else the default
+func (rl *RateLimiter) policyFor(method string, fullPath string) string {
+	rl.mu.RLock()
+	defer rl.mu.RUnlock()
+
+	if fullPath == "" {
+		return PolicyDefault // no route matched
+	}
+	if name, ok := rl.routes[method+" "+fullPath]; ok {
+		return name
+	}
+	name, longest := PolicyDefault, 0
+	for route, policy := range rl.routes {
+		if strings.HasPrefix(route, "/") && len(route) > longest &&
+			(fullPath == route || strings.HasPrefix(fullPath, route+"/")) {
+			name, longest = policy, len(route)
+		}
+	}
+	return name
+}
+
+// cleanup periodically removes buckets that have refilled from the store
+func (rl *RateLimiter) cleanup() {
+	for {
+		select {
+		case <-rl.stopChan:
+			return
+		case <-rl.ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), rl.config.CleanupInterval)
+			if err := rl.store.Cleanup(ctx); err != nil {
+				log.Printf("rate limiter: failed to clean up buckets: %v", err)
+			}
+			cancel()
+		}
+	}
+}
+
+// Stop stops the rate limiter
+func (rl *RateLimiter) Stop() {
+	rl.ticker.Stop()
+	close(rl.stopChan)
+}
+
+// Middleware is a Gin middleware that applies the policy attached to the matched route, or the default
+// policy. API keys with a quota of their own use it in place of the default policy. Every response carries
+// RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset and RateLimit-Policy headers; rejected requests get a
+// 429 with Retry-After. If the store fails, the request is let through rather than taking the API down with it.
+func (rl *RateLimiter) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := rl.policyFor(c.Request.Method, c.FullPath())
+		policy := rl.config.Policies[name]
+
+		identity := "ip:" + c.ClientIP()
+		principal, authenticated := GetPrincipal(c)
+		if policy.KeyBy == KeyByPrincipal && authenticated {
+			identity = principalIdentity(principal)
+		}
+
+		if name == PolicyDefault && authenticated && principal.Quota != nil {
+			policy = principal.Quota.apply(policy)
+		}
+
+		result, err := rl.store.Take(c.Request.Context(), name+"|"+identity, policy.rate(), policy.Burst)
+		if err != nil {
+			log.Printf("rate limiter: failed to take from bucket, allowing request: %v", err)
+			c.Next()
+			return
+		}
+
+		header := c.Writer.Header()
+		header.Set("RateLimit-Limit", strconv.Itoa(policy.Burst))
+		header.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		header.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+		header.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d;burst=%d;name=%q", policy.Limit, ceilSeconds(policy.Period), policy.Burst, name))
+
+		if !result.Allowed {
+			header.Set("Retry-After", strconv.Itoa(max(ceilSeconds(result.RetryAfter), 1)))
+			HandleError(c, apperrors.NewAppError(http.StatusTooManyRequests, "rate limit exceeded", nil))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// principalIdentity returns the bucket identity of an authenticated caller
+func principalIdentity(p *Principal) string {
+	switch p.Kind {
+	case PrincipalAPIKey:
+		return "api-key:" + p.APIKeyID.String()
+	case PrincipalStaff:
+		return "staff:" + p.StaffID.String()
+	default:
+		return "guest:" + p.TokenID
+	}
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
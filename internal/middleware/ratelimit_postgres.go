@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"context"
+	"database/sql"
+)
+
+// refilledTokens is the SQL for the tokens in a bucket row now, refilled at the rate ($2) and capped at the
+// burst ($3) of the current request
+const refilledTokens = "LEAST(b.tokens + GREATEST(EXTRACT(EPOCH FROM clock_timestamp() - b.updated_at), 0) * $2::DOUBLE PRECISION, $3::DOUBLE PRECISION)"
+
+// takeQuery creates a full bucket or refills and takes from an existing one in a single upsert, so concurrent
+// requests from any replica serialise on the row. Time is read from the database clock, which replicas share, and
+// after the row lock is taken; NOW() would be when the transaction began, possibly before waiting for the lock.
+const takeQuery = `INSERT INTO rate_limit_buckets AS b (bucket_key, tokens, burst, rate, allowed, updated_at)
+	VALUES ($1, $3::DOUBLE PRECISION - 1, $3::DOUBLE PRECISION, $2::DOUBLE PRECISION, TRUE, clock_timestamp())
+	ON CONFLICT (bucket_key) DO UPDATE SET
+		tokens = CASE WHEN ` + refilledTokens + ` >= 1 THEN ` + refilledTokens + ` - 1 ELSE ` + refilledTokens + ` END,
+		allowed = ` + refilledTokens + ` >= 1,
+		burst = EXCLUDED.burst,
+		rate = EXCLUDED.rate,
+		updated_at = clock_timestamp()
+	RETURNING allowed, tokens`
+
+// PostgresRateLimitStore implements RateLimitStore in PostgreSQL, so several API replicas share their limits
+type PostgresRateLimitStore struct {
+	db *sql.DB
+}
+
+// NewPostgresRateLimitStore creates a store using the rate_limit_buckets table
+func NewPostgresRateLimitStore(db *sql.DB) *PostgresRateLimitStore {
+	return &PostgresRateLimitStore{db: db}
+}
+
+// Take removes a token from the bucket under key
+func (s *PostgresRateLimitStore) Take(ctx context.Context, key string, rate float64, burst int) (RateLimitResult, error) {
+	var allowed bool
+	var tokens float64
+	if err := s.db.QueryRowContext(ctx, takeQuery, key, rate, float64(burst)).Scan(&allowed, &tokens); err != nil {
+		return RateLimitResult{}, err
+	}
+	return newRateLimitResult(allowed, tokens, rate, burst), nil
+}
+
+// Cleanup removes buckets that have refilled
+func (s *PostgresRateLimitStore) Cleanup(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, "DELETE FROM rate_limit_buckets WHERE tokens + EXTRACT(EPOCH FROM clock_timestamp() - updated_at) * rate >= burst")
+	return err
+}
@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimitStore keeps the token buckets of a RateLimiter. Taking a token must be atomic, so replicas sharing a
+// store share their limits.
+type RateLimitStore interface {
+	// Take removes a token from the bucket under key, creating it full if it does not exist. The bucket refills
+	// at rate tokens per second up to burst; a bucket last used with another rate or burst is resized.
+	Take(ctx context.Context, key string, rate float64, burst int) (RateLimitResult, error)
+
+	// Cleanup removes buckets that have refilled, since a new bucket would be identical
+	Cleanup(ctx context.Context) error
+}
+
+// RateLimitResult is the outcome of taking a token from a bucket
+type RateLimitResult struct {
+	Allowed    bool
+	Remaining  int           // whole tokens left
+	Reset      time.Duration // until the bucket is full again
+	RetryAfter time.Duration // until the next token, if not allowed
+}
+
+// newRateLimitResult describes a bucket holding tokens after a take
+func newRateLimitResult(allowed bool, tokens float64, rate float64, burst int) RateLimitResult {
+	result := RateLimitResult{
+		Allowed:   allowed,
+		Remaining: int(tokens),
+		Reset:     secondsToDuration((float64(burst) - tokens) / rate),
+	}
+	if !allowed {
+		result.RetryAfter = secondsToDuration((1 - tokens) / rate)
+	}
+	return result
+}
+
+// TokenBucket represents a token bucket for rate limiting
+type TokenBucket struct {
+	tokens    float64
+	maxTokens float64
+	rate      float64 // tokens added per second
+	lastReset time.Time
+}
+
+// refilled returns the tokens in the bucket at now
+func (b *TokenBucket) refilled(now time.Time) float64 {
+	return min(b.tokens+now.Sub(b.lastReset).Seconds()*b.rate, b.maxTokens)
+}
+
+// MemoryRateLimitStore implements RateLimitStore in the memory of one process
+type MemoryRateLimitStore struct {
+	buckets map[string]*TokenBucket
+	mu      sync.Mutex
+}
+
+// NewMemoryRateLimitStore creates an empty in-memory store
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{buckets: make(map[string]*TokenBucket)}
+}
+
+// Take removes a token from the bucket under key
+func (s *MemoryRateLimitStore) Take(ctx context.Context, key string, rate float64, burst int) (RateLimitResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	bucket, exists := s.buckets[key]
+	if !exists {
+		bucket = &TokenBucket{
+			tokens:    float64(burst),
+			lastReset: now,
+		}
+		s.buckets[key] = bucket
+	}
+	bucket.maxTokens = float64(burst)
+	bucket.rate = rate
+
+	// Refill tokens based on time elapsed
+	bucket.tokens = bucket.refilled(now)
+	bucket.lastReset = now
+
+	allowed := bucket.tokens >= 1
+	if allowed {
+		bucket.tokens--
+	}
+	return newRateLimitResult(allowed, bucket.tokens, rate, burst), nil
+}
+
+// Cleanup removes buckets that have refilled
+func (s *MemoryRateLimitStore) Cleanup(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for key, bucket := range s.buckets {
+		if bucket.refilled(now) >= bucket.maxTokens {
+			delete(s.buckets, key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package middleware
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"restaurant/internal/dbtest"
+
+	"github.com/google/uuid"
+)
+
+// slowRate refills so slowly that no token comes back while a check runs
+const slowRate = 1.0 / 3600
+
+func TestMemoryRateLimitStore(t *testing.T) {
+	testRateLimitStore(t, NewMemoryRateLimitStore())
+}
+
+// TestPostgresRateLimitStore needs TEST_DATABASE_URL pointing at a migrated database
+func TestPostgresRateLimitStore(t *testing.T) {
+	testRateLimitStore(t, NewPostgresRateLimitStore(dbtest.Open(t)))
+}
+
+// testRateLimitStore checks that a store behaves like every other RateLimitStore. Bucket keys are unique to the
+// run, so a shared store such as a PostgreSQL database can be tested while in use.
+func testRateLimitStore(t *testing.T, store RateLimitStore) {
+	ctx := context.Background()
+	run := uuid.NewString()
+	key := func(name string) string { return "ratelimit_store_test|" + run + "|" + name }
+
+	t.Run("burst", func(t *testing.T) {
+		// A new bucket is full and counts down
+		key := key("burst")
+		for want := 2; want >= 0; want-- {
+			result := take(t, store, key, slowRate, 3)
+			if !result.Allowed {
+				t.Fatalf("take %d of a burst of 3 was rejected", 3-want)
+			}
+			if result.Remaining != want {
+				t.Errorf("take %d left %d tokens, want %d", 3-want, result.Remaining, want)
+			}
+			if result.RetryAfter != 0 {
+				t.Errorf("allowed take has Retry-After %s", result.RetryAfter)
+			}
+		}
+	})
+
+	t.Run("reject", func(t *testing.T) {
+		// An empty bucket rejects with when to retry and when it is full again
+		const rate = 1.0 / 60
+		key := key("reject")
+		take(t, store, key, rate, 1)
+		result := take(t, store, key, rate, 1)
+		if result.Allowed {
+			t.Fatal("take from an empty bucket was allowed")
+		}
+		if result.Remaining != 0 {
+			t.Errorf("empty bucket has %d tokens remaining", result.Remaining)
+		}
+		if result.RetryAfter <= 50*time.Second || result.RetryAfter > time.Minute {
+			t.Errorf("Retry-After is %s, want about a minute", result.RetryAfter)
+		}
+		if result.Reset <= 50*time.Second || result.Reset > time.Minute {
+			t.Errorf("reset is %s, want about a minute", result.Reset)
+		}
+	})
+
+	t.Run("independent keys", func(t *testing.T) {
+		// Emptying one bucket leaves others alone
+		key := key("independent keys")
+		take(t, store, key+"|a", slowRate, 1)
+		if result := take(t, store, key+"|b", slowRate, 1); !result.Allowed {
+			t.Error("take from a new bucket was rejected after another bucket was emptied")
+		}
+	})
+
+	t.Run("refill", func(t *testing.T) {
+		// An empty bucket gets tokens back over time
+		const rate = 20.0 // a token every 50ms
+		key := key("refill")
+		take(t, store, key, rate, 1)
+		result := take(t, store, key, rate, 1)
+		if result.Allowed {
+			t.Fatal("take from an empty bucket was allowed")
+		}
+		time.Sleep(result.RetryAfter + 20*time.Millisecond)
+		if result := take(t, store, key, rate, 1); !result.Allowed {
+			t.Error("take after Retry-After was rejected")
+		}
+	})
+
+	t.Run("resize", func(t *testing.T) {
+		// A bucket is capped at the burst of the latest take
+		key := key("resize")
+		take(t, store, key, slowRate, 10)
+		if result := take(t, store, key, slowRate, 2); !result.Allowed || result.Remaining != 1 {
+			t.Errorf("take after shrinking the burst to 2 left %d tokens, want 1", result.Remaining)
+		}
+	})
+
+	t.Run("concurrent takes", func(t *testing.T) {
+		// Takes are atomic: concurrent requests get exactly the burst between them
+		const burst, requests = 10, 40
+		key := key("concurrent takes")
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		var allowed int
+		for range requests {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				result, err := store.Take(ctx, key, slowRate, burst)
+				if err != nil {
+					t.Errorf("Take: %v", err)
+					return
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				if result.Allowed {
+					allowed++
+				}
+			}()
+		}
+		wg.Wait()
+		if allowed != burst {
+			t.Errorf("%d of %d concurrent takes were allowed, want %d", allowed, requests, burst)
+		}
+	})
+
+	t.Run("cleanup", func(t *testing.T) {
+		// Cleanup keeps buckets that are still refilling
+		key := key("cleanup")
+		take(t, store, key, slowRate, 1)
+		if err := store.Cleanup(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if result := take(t, store, key, slowRate, 1); result.Allowed {
+			t.Error("cleanup removed a bucket that was still empty")
+		}
+	})
+}
+
+// take takes a token from a bucket, failing the test if the store returns an error
+func take(t *testing.T, store RateLimitStore, key string, rate float64, burst int) RateLimitResult {
+	t.Helper()
+	result, err := store.Take(context.Background(), key, rate, burst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
@@ -0,0 +1,192 @@
+package middleware
+
+import (
+	"fmt"
+	"sort"
+
+	apperrors "restaurant/internal/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Permission is an action a role may be granted
+type Permission string
+
+const (
+	// PermPublic marks a route anyone may call, with or without a token
+	PermPublic Permission = "public"
+
+	PermAccount Permission = "account" // own staff account: view it, log out
+
+	PermMenuManage Permission = "menu:manage" // create, edit, price, translate and delete menu items and categories
+
+	PermOrdersRead    Permission = "orders:read"    // view orders (guests: their own session's)
+	PermOrdersList    Permission = "orders:list"    // list every order
+	PermOrdersPlace   Permission = "orders:place"   // create orders, add items to carts, submit or cancel them
+	PermOrdersPrepare Permission = "orders:prepare" // move orders to preparing and ready
+	PermOrdersServe   Permission = "orders:serve"   // mark orders served
+
+	PermSessionsCurrent Permission = "sessions:current" // view the session a guest token was issued for
+	PermSessionsRead    Permission = "sessions:read"    // view sessions and tables
+	PermSessionsManage  Permission = "sessions:manage"  // update sessions, move, join, release and split tables
+	PermSessionsAssign  Permission = "sessions:assign"  // hand sessions to another server
+	PermSessionsDelete  Permission = "sessions:delete"
+	PermTablesManage    Permission = "tables:manage" // create, edit and delete tables and rotate their QR codes
+	PermReportsRead     Permission = "reports:read"
+
+	PermReservationsManage Permission = "reservations:manage" // view, change, cancel and seat reservations
+	PermWaitlistManage     Permission = "waitlist:manage"
+
+	PermStaffRead   Permission = "staff:read"   // view staff, shifts and per-server views
+	PermStaffManage Permission = "staff:manage" // add and change staff, schedule shifts, set login credentials
+
+	PermAPIKeysManage Permission = "api-keys:manage" // issue, change, rotate and revoke API keys
+)
+
+// Roles a principal can have. Staff roles are stored on the staff member and carried in their access token;
+// every guest token has RoleGuest.
+const (
+	RoleGuest   = "guest"
+	RoleServer  = "server"
+	RoleHost    = "host"
+	RoleKitchen = "kitchen"
+	RoleManager = "manager"
+)
+
+// rolePermissions lists what each role may do. Roles not listed may do nothing beyond public routes.
+var rolePermissions = map[string][]Permission{
+	RoleGuest: {
+		PermOrdersRead, PermOrdersPlace, PermSessionsCurrent,
+	},
+	RoleServer: {
+		PermAccount, PermOrdersRead, PermOrdersList, PermOrdersPlace, PermOrdersServe,
+		PermSessionsRead, PermSessionsManage, PermReservationsManage, PermWaitlistManage, PermStaffRead,
+	},
+	RoleHost: {
+		PermAccount, PermOrdersRead, PermOrdersList,
+		PermSessionsRead, PermSessionsManage, PermSessionsAssign, PermReservationsManage, PermWaitlistManage, PermStaffRead,
+	},
+	RoleKitchen: {
+		PermAccount, PermOrdersRead, PermOrdersList, PermOrdersPrepare, PermOrdersServe, PermSessionsRead, PermStaffRead,
+	},
+	RoleManager: {
+		PermAccount, PermMenuManage,
+		PermOrdersRead, PermOrdersList, PermOrdersPlace, PermOrdersPrepare, PermOrdersServe,
+		PermSessionsRead, PermSessionsManage, PermSessionsAssign, PermSessionsDelete, PermTablesManage, PermReportsRead,
+		PermReservationsManage, PermWaitlistManage, PermStaffRead, PermStaffManage, PermAPIKeysManage,
+	},
+}
+
+// grants indexes rolePermissions for lookups
+var grants = func() map[string]map[Permission]bool {
+	index := make(map[string]map[Permission]bool, len(rolePermissions))
+	for role, permissions := range rolePermissions {
+		index[role] = make(map[Permission]bool, len(permissions))
+		for _, permission := range permissions {
+			index[role][permission] = true
+		}
+	}
+	return index
+}()
+
+// RoleNames returns every role that is granted permissions, sorted by name
+func RoleNames() []string {
+	names := make([]string, 0, len(rolePermissions))
+	for role := range rolePermissions {
+		names = append(names, role)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RolePermissions returns the permissions granted to a role
+func RolePermissions(role string) []Permission {
+	return append([]Permission(nil), rolePermissions[role]...)
+}
+
+// Can reports whether the caller's role, or an API key's scopes, grant a permission
+func (p *Principal) Can(permission Permission) bool {
+	if permission == PermPublic {
+		return true
+	}
+	if p.Kind == PrincipalAPIKey {
+		for _, scope := range p.Scopes {
+			if scope == permission {
+				return true
+			}
+		}
+		return false
+	}
+	return grants[p.Role][permission]
+}
+
+// ValidScope reports whether a permission may be granted to an API key: any permission a staff role has,
+// except managing their own account or other API keys
+func ValidScope(permission Permission) bool {
+	if permission == PermAccount || permission == PermAPIKeysManage {
+		return false
+	}
+	for role, granted := range grants {
+		if role != RoleGuest && granted[permission] {
+			return true
+		}
+	}
+	return false
+}
+
+// RoutePermissions declares the permission each route of a group needs, keyed by method and full route path
+// as registered, e.g. "PUT /menu/:id". Routes missing from the map are denied, so a new route stays closed
+// until it is given a permission.
+type RoutePermissions map[string]Permission
+
+// Authorize is a Gin middleware for a route group that enforces the permission declared for the matched route:
+// 401 without a valid token, 403 if the caller's role does not grant it
+func (p RoutePermissions) Authorize() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		permission, ok := p[c.Request.Method+" "+c.FullPath()]
+		if !ok {
+			HandleError(c, apperrors.NewForbiddenError("no permission is defined for this route"))
+			c.Abort()
+			return
+		}
+		if permission != PermPublic && !Authorize(c, permission) {
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// Authorize reports whether the caller has a permission, writing a 401 or 403 response if not. Handlers call
+// it for checks that depend on the request body, such as the status an order is moved to.
+func Authorize(c *gin.Context, permission Permission) bool {
+	principal, ok := GetPrincipal(c)
+	if !ok {
+		HandleError(c, apperrors.ErrUnauthorized)
+		return false
+	}
+	if !principal.Can(permission) {
+		if principal.Kind == PrincipalAPIKey {
+			HandleError(c, apperrors.NewForbiddenError(fmt.Sprintf("API key lacks the %s scope", permission)))
+		} else {
+			HandleError(c, apperrors.NewForbiddenError(fmt.Sprintf("role %q lacks the %s permission", principal.Role, permission)))
+		}
+		return false
+	}
+	return true
+}
+
+// IncludeDeleted reads the include_deleted query parameter of a listing, which only callers with permission may
+// set. A listing with soft-deleted entries is marked private, so shared caches never serve it to the public. ok
+// is false once an error response has been written.
+func IncludeDeleted(c *gin.Context, permission Permission) (include bool, ok bool) {
+	include, ok = GetBoolQueryParam(c, "include_deleted")
+	if !ok || !include {
+		return false, ok
+	}
+	if !Authorize(c, permission) {
+		return false, false
+	}
+	c.Header("Cache-Control", CacheRevalidatePrivate)
+	return true, true
+}
@@ -1,159 +1,34 @@
 package middleware
 
 import (
+	"io"
 	"net/http"
-	"sync"
-	"time"
-
-	apperrors "restaurant/internal/errors"
 
 	"github.com/gin-gonic/gin"
 )
 
-// RateLimitConfig holds rate limiting configuration
-type RateLimitConfig struct {
-	RequestsPerSecond int
-	BurstSize         int
-	CleanupInterval   time.Duration
-}
-
-// RateLimiter implements token bucket rate limiting
-type RateLimiter struct {
-	config   RateLimitConfig
-	buckets  map[string]*TokenBucket
-	mu       sync.RWMutex
-	ticker   *time.Ticker
-	stopChan chan struct{}
-}
-
-// TokenBucket represents a token bucket for rate limiting
-type TokenBucket struct {
-	tokens    float64
-	maxTokens float64
-	lastReset time.Time
-}
-
-// NewRateLimiter creates a new rate limiter
-func NewRateLimiter(config RateLimitConfig) *RateLimiter {
-	if config.RequestsPerSecond == 0 {
-		config.RequestsPerSecond = 100
-	}
-	if config.BurstSize == 0 {
-		config.BurstSize = config.RequestsPerSecond * 2
-	}
-	if config.CleanupInterval == 0 {
-		config.CleanupInterval = 5 * time.Minute
-	}
-
-	rl := &RateLimiter{
-		config:   config,
-		buckets:  make(map[string]*TokenBucket),
-		stopChan: make(chan struct{}),
-	}
-
-	// Start cleanup goroutine
-	rl.ticker = time.NewTicker(config.CleanupInterval)
-	go rl.cleanup()
+// originalBodyKey is the context key holding the request body before any size limit was applied
+const originalBodyKey = "original-body"
 
-	return rl
-}
-
-// Allow checks if a request from the given identifier is allowed
-func (rl *RateLimiter) Allow(identifier string) bool {
-	rl.mu.Lock()
-	defer rl.mu.Unlock()
-
-	bucket, exists := rl.buckets[identifier]
-	if !exists {
-		bucket = &TokenBucket{
-			tokens:    float64(rl.config.BurstSize),
-			maxTokens: float64(rl.config.BurstSize),
-			lastReset: time.Now(),
-		}
-		rl.buckets[identifier] = bucket
-	}
-
-	// Refill tokens based on time elapsed
-	now := time.Now()
-	elapsed := now.Sub(bucket.lastReset).Seconds()
-	tokensToAdd := elapsed * float64(rl.config.RequestsPerSecond)
-	bucket.tokens = min(bucket.tokens+tokensToAdd, bucket.maxTokens)
-	bucket.lastReset = now
-
-	// Check if we have tokens
-	if bucket.tokens >= 1 {
-		bucket.tokens--
-		return true
-	}
-
-	return false
-}
-
-// cleanup removes old buckets periodically
-func (rl *RateLimiter) cleanup() {
-	for range rl.ticker.C {
-		rl.mu.Lock()
-		now := time.Now()
-		for id, bucket := range rl.buckets {
-			if now.Sub(bucket.lastReset) > rl.config.CleanupInterval {
-				delete(rl.buckets, id)
-			}
-		}
-		rl.mu.Unlock()
-	}
-}
-
-// Stop stops the rate limiter
-func (rl *RateLimiter) Stop() {
-	rl.ticker.Stop()
-	close(rl.stopChan)
-}
-
-func min(a, b float64) float64 {
-	if a < b {
-		return a
-	}
-	return b
-}
-
-// Global rate limiter instance
-var globalRateLimiter *RateLimiter
-
-// InitRateLimiter initializes the global rate limiter
-func InitRateLimiter(config RateLimitConfig) {
-	globalRateLimiter = NewRateLimiter(config)
-}
-
-// RateLimitMiddleware is a Gin middleware for rate limiting
-func RateLimitMiddleware() gin.HandlerFunc {
+// RequestSizeLimitMiddleware limits the size of incoming requests
+func RequestSizeLimitMiddleware(maxSizeBytes int64) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		if globalRateLimiter == nil {
-			c.Next()
-			return
-		}
-
-		// Use IP address as identifier
-		identifier := c.ClientIP()
-
-		if !globalRateLimiter.Allow(identifier) {
-			err := apperrors.NewAppError(
-				http.StatusTooManyRequests,
-				"rate limit exceeded",
-				nil,
-			)
-			HandleError(c, err)
-			c.Abort()
-			return
-		}
-
+		// Keep the unwrapped body so MaxBodySize can replace this limit on specific routes
+		c.Set(originalBodyKey, c.Request.Body)
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSizeBytes)
 		c.Next()
 	}
 }
 
-// RequestSizeLimitMiddleware limits the size of incoming requests
-func RequestSizeLimitMiddleware(maxSizeBytes int64) gin.HandlerFunc {
+// MaxBodySize overrides the global request size limit for a single route, e.g. file uploads
+// Usage: group.POST("/:id/image", middleware.MaxBodySize(6<<20), h.UploadImage)
+func MaxBodySize(maxSizeBytes int64) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSizeBytes)
+		body := c.Request.Body
+		if original, ok := c.Get(originalBodyKey); ok {
+			body = original.(io.ReadCloser)
+		}
+		c.Request.Body = http.MaxBytesReader(c.Writer, body, maxSizeBytes)
 		c.Next()
 	}
 }
@@ -187,8 +62,8 @@ func CORSMiddleware() gin.HandlerFunc {
 		}
 
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS, PATCH")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Request-ID")
-		c.Writer.Header().Set("Access-Control-Expose-Headers", "X-Request-ID")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Request-ID, X-Session-Token, X-API-Key, If-None-Match, If-Match, Idempotency-Key")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "X-Request-ID, ETag, RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset, RateLimit-Policy, Retry-After, Idempotent-Replayed")
 		c.Writer.Header().Set("Access-Control-Max-Age", "3600")
 
 		if c.Request.Method == "OPTIONS" {
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"strconv"
+	"strings"
+
+	apperrors "restaurant/internal/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Precondition is the version of a resource a client read before sending an update
+type Precondition struct {
+	Version int  // version the update is made against
+	IfMatch bool // whether Version came from an If-Match header rather than the request body
+}
+
+// RequireVersion reads the version an update is made against from the If-Match header, or from the request body
+// if there is no If-Match. body is the version field of the bound request, nil if the client left it out. It
+// writes 428 Precondition Required and returns false if neither is given, and 412 Precondition Failed if
+// If-Match is not a single version ETag, since nothing else can ever match.
+func RequireVersion(c *gin.Context, body *int) (Precondition, bool) {
+	if ifMatch := c.GetHeader("If-Match"); ifMatch != "" {
+		version, ok := parseVersionETag(ifMatch)
+		if !ok {
+			HandleError(c, apperrors.ErrPreconditionFailed)
+			return Precondition{}, false
+		}
+		return Precondition{Version: version, IfMatch: true}, true
+	}
+	if body != nil {
+		return Precondition{Version: *body}, true
+	}
+	HandleError(c, apperrors.ErrPreconditionRequired)
+	return Precondition{}, false
+}
+
+// HandleError reports an error from the update the precondition guards. A version conflict is 412 Precondition
+// Failed if the version came from If-Match and stays 409 Conflict if it came from the body.
+func (p Precondition) HandleError(c *gin.Context, err error) {
+	if p.IfMatch && err == apperrors.ErrVersionConflict {
+		err = apperrors.ErrPreconditionFailed
+	}
+	HandleError(c, err)
+}
+
+// SetVersionETag sets the ETag of a versioned resource so the client can send it back in If-Match. locale is
+// the language of a localized response, empty if the response is not localized.
+func SetVersionETag(c *gin.Context, version int, locale string) {
+	c.Header("ETag", VersionETag(version, locale))
+}
+
+// VersionETag formats a version as a strong ETag, such as "v3" or "v3-fr" for a representation in one locale.
+// Every locale of a version carries the same version number, so any of them is a valid If-Match.
+func VersionETag(version int, locale string) string {
+	tag := "v" + strconv.Itoa(version)
+	if locale != "" {
+		tag += "-" + locale
+	}
+	return `"` + tag + `"`
+}
+
+// parseVersionETag returns the version of an ETag made by VersionETag. Weak ETags never match, since If-Match
+// uses strong comparison.
+func parseVersionETag(etag string) (int, bool) {
+	tag, ok := strings.CutPrefix(strings.TrimSpace(etag), `"v`)
+	if !ok {
+		return 0, false
+	}
+	tag, ok = strings.CutSuffix(tag, `"`)
+	if !ok {
+		return 0, false
+	}
+	digits, _, _ := strings.Cut(tag, "-")
+	version, err := strconv.Atoi(digits)
+	if err != nil || version < 1 {
+		return 0, false
+	}
+	return version, true
+}
@@ -5,21 +5,58 @@ import (
 	"restaurant/internal/middleware"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 // OrderHandler handles HTTP requests for orders
 type OrderHandler struct {
-	svc OrderService
+	svc         OrderService
+	idempotency *middleware.Idempotency
 }
 
-// NewOrderHandler creates a new order handler
-func NewOrderHandler(svc OrderService) *OrderHandler {
-	return &OrderHandler{svc: svc}
+// NewOrderHandler creates a new order handler; idempotency may be nil to ignore Idempotency-Key
+func NewOrderHandler(svc OrderService, idempotency *middleware.Idempotency) *OrderHandler {
+	return &OrderHandler{svc: svc, idempotency: idempotency}
+}
+
+// orderPermissions declares what each order route needs. Guests may order for their own session; the handlers
+// check which session an order belongs to, and which status it may be moved to.
+var orderPermissions = middleware.RoutePermissions{
+	"GET /orders":                   middleware.PermOrdersList,
+	"POST /orders":                  middleware.PermOrdersPlace,
+	"GET /orders/:id":               middleware.PermOrdersRead,
+	"PUT /orders/:id":               middleware.PermOrdersRead, // see statusPermissions
+	"POST /orders/:id/items":        middleware.PermOrdersPlace,
+	"GET /orders/:id/items":         middleware.PermOrdersRead,
+	"GET /sessions/:id/orders":      middleware.PermOrdersRead,
+	"GET /sessions/:id/order-items": middleware.PermOrdersRead,
+}
+
+// statusPermissions is the permission needed to move an order to each status: guests and servers put orders
+// through, the kitchen prepares them and the kitchen or floor serves them
+var statusPermissions = map[OrderStatus]middleware.Permission{
+	OrderStatusCart:      middleware.PermOrdersPlace,
+	OrderStatusPending:   middleware.PermOrdersPlace,
+	OrderStatusCancelled: middleware.PermOrdersPlace,
+	OrderStatusPreparing: middleware.PermOrdersPrepare,
+	OrderStatusReady:     middleware.PermOrdersPrepare,
+	OrderStatusServed:    middleware.PermOrdersServe,
+}
+
+// orderIdempotency lists the order routes tablets retry on a flaky connection. Adding an item to an order adds
+// to an existing line, so running a retry twice would double the quantity.
+var orderIdempotency = middleware.RouteIdempotency{
+	"POST /orders":           true,
+	"POST /orders/:id/items": true,
 }
 
 // RegisterRoutes registers all order routes with the Gin router
 func (h *OrderHandler) RegisterRoutes(router *gin.Engine) {
-	orderGroup := router.Group("/orders")
+	if h.idempotency != nil {
+		h.idempotency.Attach(orderIdempotency)
+	}
+
+	orderGroup := router.Group("/orders", orderPermissions.Authorize())
 	{
 		orderGroup.GET("", h.ListOrders)
 		orderGroup.POST("", h.CreateOrder)
@@ -30,7 +67,7 @@ func (h *OrderHandler) RegisterRoutes(router *gin.Engine) {
 	}
 
 	// Session-related order routes
-	sessionGroup := router.Group("/sessions")
+	sessionGroup := router.Group("/sessions", orderPermissions.Authorize())
 	{
 		sessionGroup.GET("/:id/orders", h.GetOrdersBySession)
 		sessionGroup.GET("/:id/order-items", h.GetOrderItemsBySessionIDs)
@@ -43,9 +80,12 @@ func (h *OrderHandler) RegisterRoutes(router *gin.Engine) {
 // @Tags Orders
 // @Accept json
 // @Produce json
+// @Param Idempotency-Key header string false "Client-generated key; a retry with the same key replays the first response"
 // @Param request body CreateOrderRequest true "Order creation request"
 // @Success 201 {object} Order
 // @Failure 400 {object} middleware.ErrorResponse
+// @Failure 409 {object} middleware.ErrorResponse "A request with the same Idempotency-Key is still running"
+// @Failure 422 {object} middleware.ErrorResponse "Idempotency-Key was used for a different request"
 // @Failure 500 {object} middleware.ErrorResponse
 // @Router /orders [post]
 func (h *OrderHandler) CreateOrder(c *gin.Context) {
@@ -60,6 +100,10 @@ func (h *OrderHandler) CreateOrder(c *gin.Context) {
 		return
 	}
 
+	if !middleware.SessionAccess(c, req.SessionID) {
+		return
+	}
+
 	order, err := h.svc.CreateOrder(c.Request.Context(), req.SessionID)
 	if err != nil {
 		middleware.HandleError(c, err)
@@ -91,7 +135,11 @@ func (h *OrderHandler) GetOrder(c *gin.Context) {
 		middleware.HandleError(c, err)
 		return
 	}
+	if !middleware.SessionAccess(c, order.SessionID) {
+		return
+	}
 
+	middleware.SetVersionETag(c, order.Version, "")
 	c.JSON(200, order)
 }
 
@@ -140,14 +188,19 @@ func (h *OrderHandler) ListOrders(c *gin.Context) {
 
 // UpdateOrder handles PUT /orders/:id
 // @Summary Update order status
-// @Description Update the status of an order
+// @Description Update the status of an order. The version read is sent as If-Match, or as version in the body; if the order has changed since, nothing is updated.
 // @Tags Orders
 // @Accept json
 // @Produce json
 // @Param id path string true "Order ID (UUID)"
+// @Param If-Match header string false "ETag of the version being updated, from the last read"
 // @Param request body UpdateOrderRequest true "Status update request"
 // @Success 200 {object} Order
 // @Failure 400 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 409 {object} middleware.ErrorResponse "Body version is out of date"
+// @Failure 412 {object} middleware.ErrorResponse "If-Match is out of date"
+// @Failure 428 {object} middleware.ErrorResponse "Neither If-Match nor a body version was sent"
 // @Failure 500 {object} middleware.ErrorResponse
 // @Router /orders/{id} [put]
 func (h *OrderHandler) UpdateOrder(c *gin.Context) {
@@ -167,12 +220,25 @@ func (h *OrderHandler) UpdateOrder(c *gin.Context) {
 		return
 	}
 
-	order, err := h.svc.UpdateOrder(c.Request.Context(), id, string(req.Status))
+	if !middleware.Authorize(c, statusPermissions[req.Status]) {
+		return
+	}
+	if !h.orderAccess(c, id) {
+		return
+	}
+
+	precondition, ok := middleware.RequireVersion(c, req.Version)
+	if !ok {
+		return
+	}
+
+	order, err := h.svc.UpdateOrder(c.Request.Context(), id, string(req.Status), precondition.Version)
 	if err != nil {
-		middleware.HandleError(c, err)
+		precondition.HandleError(c, err)
 		return
 	}
 
+	middleware.SetVersionETag(c, order.Version, "")
 	c.JSON(200, order)
 }
 
@@ -183,9 +249,12 @@ func (h *OrderHandler) UpdateOrder(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path string true "Order ID (UUID)"
+// @Param Idempotency-Key header string false "Client-generated key; a retry with the same key replays the first response"
 // @Param request body CreateOrderItemRequest true "Order item creation request"
 // @Success 201 {object} OrderItems
 // @Failure 400 {object} middleware.ErrorResponse
+// @Failure 409 {object} middleware.ErrorResponse "A request with the same Idempotency-Key is still running"
+// @Failure 422 {object} middleware.ErrorResponse "Idempotency-Key was used for a different request"
 // @Failure 500 {object} middleware.ErrorResponse
 // @Router /orders/{id}/items [post]
 func (h *OrderHandler) CreateOrderItem(c *gin.Context) {
@@ -205,6 +274,10 @@ func (h *OrderHandler) CreateOrderItem(c *gin.Context) {
 		return
 	}
 
+	if !h.orderAccess(c, orderID) {
+		return
+	}
+
 	item, err := h.svc.CreateOrderItem(c.Request.Context(), req.MenuItemID, req.Quantity, orderID)
 	if err != nil {
 		middleware.HandleError(c, err)
@@ -231,6 +304,10 @@ func (h *OrderHandler) GetOrderItems(c *gin.Context) {
 		return
 	}
 
+	if !h.orderAccess(c, id) {
+		return
+	}
+
 	items, err := h.svc.GetOrderItems(c.Request.Context(), id)
 	if err != nil {
 		middleware.HandleError(c, err)
@@ -257,6 +334,10 @@ func (h *OrderHandler) GetOrdersBySession(c *gin.Context) {
 		return
 	}
 
+	if !middleware.SessionAccess(c, id) {
+		return
+	}
+
 	orders, err := h.svc.GetOrdersBySession(c.Request.Context(), id)
 	if err != nil {
 		middleware.HandleError(c, err)
@@ -283,6 +364,10 @@ func (h *OrderHandler) GetOrderItemsBySessionIDs(c *gin.Context) {
 		return
 	}
 
+	if !middleware.SessionAccess(c, id) {
+		return
+	}
+
 	items, err := h.svc.GetOrderItemsBySessionID(c.Request.Context(), id)
 	if err != nil {
 		middleware.HandleError(c, err)
@@ -291,3 +376,13 @@ func (h *OrderHandler) GetOrderItemsBySessionIDs(c *gin.Context) {
 
 	c.JSON(200, items)
 }
+
+// orderAccess reports whether the caller may act on an order's session, writing an error response if not
+func (h *OrderHandler) orderAccess(c *gin.Context, id uuid.UUID) bool {
+	order, err := h.svc.GetOrder(c.Request.Context(), id)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return false
+	}
+	return middleware.SessionAccess(c, order.SessionID)
+}
@@ -11,6 +11,8 @@ type Order struct {
 	SessionID uuid.UUID   `json:"session_id"` // associated session ID
 	CreatedAt time.Time   `json:"created_at"` // when the order was created
 	Status    OrderStatus `json:"status"`     // e.g., OrderStatusPending, OrderStatusPreparing, etc.
+	ServerID  *uuid.UUID  `json:"server_id"`  // server delivering the order, taken from its session when placed
+	Version   int         `json:"version"`    // bumped by every write, for optimistic concurrency
 }
 
 type OrderItems struct {
@@ -26,6 +28,7 @@ const (
 	OrderStatusCart      OrderStatus = "cart"
 	OrderStatusPending   OrderStatus = "pending"
 	OrderStatusPreparing OrderStatus = "preparing"
+	OrderStatusReady     OrderStatus = "ready" // cooked and waiting at the pass for its server
 	OrderStatusServed    OrderStatus = "served"
 	OrderStatusCancelled OrderStatus = "cancelled"
 )
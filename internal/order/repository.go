@@ -29,14 +29,11 @@ type OrderRepository interface {
 	// ListOrders lists all orders
 	ListOrders(ctx context.Context, limit int, offset int) ([]*Order, error)
 
-	// UpdateOrder updates an order
-	UpdateOrder(ctx context.Context, orderID uuid.UUID, status string) error
+	// UpdateOrder updates the status of an order that is still at version
+	UpdateOrder(ctx context.Context, orderID uuid.UUID, status string, version int) error
 
-	// CreateOrderItem creates a new order item
-	CreateOrderItem(ctx context.Context, item *OrderItems) error
-
-	// UpdateOrderItemQuantity updates the quantity of an existing order item
-	UpdateOrderItemQuantity(ctx context.Context, itemID uuid.UUID, quantity int) error
+	// AddOrderItem adds an item to an order in cart status, or adds to the quantity of its line for the menu item
+	AddOrderItem(ctx context.Context, item *OrderItems) error
 
 	// GetOrderItems retrieves order items by order ID
 	GetOrderItems(ctx context.Context, orderID uuid.UUID) ([]*OrderItems, error)
@@ -47,6 +44,9 @@ type OrderRepository interface {
 	// GetOrderItemsByOrderIDs retrieves order items by multiple order IDs
 	GetOrderItemsByOrderIDs(ctx context.Context, orderIDs []uuid.UUID) ([]*OrderItems, error)
 
+	// ListOrdersByServer lists a server's orders in the given status, oldest first
+	ListOrdersByServer(ctx context.Context, serverID uuid.UUID, status OrderStatus) ([]*Order, error)
+
 	// BeginTx begins a new database transaction
 	BeginTx(ctx context.Context) (*sql.Tx, error)
 }
@@ -87,7 +87,7 @@ func (r *postgresOrderRepository) BeginTx(ctx context.Context) (*sql.Tx, error)
 // CreateOrder inserts a new order into the database
 func (r *postgresOrderRepository) CreateOrder(ctx context.Context, order *Order) error {
 	// Execute INSERT query with order details
-	_, err := r.db.ExecContext(ctx, "INSERT INTO orders (id, session_id, status, created_at) VALUES ($1, $2, $3, $4)", order.ID, order.SessionID, order.Status, order.CreatedAt)
+	_, err := r.db.ExecContext(ctx, "INSERT INTO orders (id, session_id, status, created_at, server_id) VALUES ($1, $2, $3, $4, $5)", order.ID, order.SessionID, order.Status, order.CreatedAt, order.ServerID)
 	if err != nil {
 		return err
 	}
@@ -98,7 +98,7 @@ func (r *postgresOrderRepository) CreateOrder(ctx context.Context, order *Order)
 func (r *postgresOrderRepository) GetOrder(ctx context.Context, id uuid.UUID) (*Order, error) {
 	var order Order
 	// Execute SELECT query and scan result
-	err := r.db.QueryRowContext(ctx, "SELECT id, session_id, status, created_at FROM orders WHERE id = $1", id).Scan(&order.ID, &order.SessionID, &order.Status, &order.CreatedAt)
+	err := r.db.QueryRowContext(ctx, "SELECT id, session_id, status, created_at, server_id, version FROM orders WHERE id = $1", id).Scan(&order.ID, &order.SessionID, &order.Status, &order.CreatedAt, &order.ServerID, &order.Version)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, errors.ErrOrderNotFound
@@ -112,7 +112,7 @@ func (r *postgresOrderRepository) GetOrder(ctx context.Context, id uuid.UUID) (*
 func (r *postgresOrderRepository) ListOrders(ctx context.Context, limit int, offset int) ([]*Order, error) {
 	var orders []*Order
 	// Execute SELECT query with LIMIT and OFFSET
-	rows, err := r.db.QueryContext(ctx, "SELECT id, session_id, status, created_at FROM orders ORDER BY created_at DESC LIMIT $1 OFFSET $2", limit, offset)
+	rows, err := r.db.QueryContext(ctx, "SELECT id, session_id, status, created_at, server_id, version FROM orders ORDER BY created_at DESC LIMIT $1 OFFSET $2", limit, offset)
 	if err != nil {
 		return nil, err
 	}
@@ -121,7 +121,7 @@ func (r *postgresOrderRepository) ListOrders(ctx context.Context, limit int, off
 	// Iterate through rows and scan into order structs
 	for rows.Next() {
 		var order Order
-		err := rows.Scan(&order.ID, &order.SessionID, &order.Status, &order.CreatedAt)
+		err := rows.Scan(&order.ID, &order.SessionID, &order.Status, &order.CreatedAt, &order.ServerID, &order.Version)
 		if err != nil {
 			return nil, err
 		}
@@ -133,23 +133,59 @@ func (r *postgresOrderRepository) ListOrders(ctx context.Context, limit int, off
 	return orders, nil
 }
 
-// CreateOrderItem creates a new order item in the database
-func (r *postgresOrderRepository) CreateOrderItem(ctx context.Context, item *OrderItems) error {
-	// Execute INSERT query for order item
-	_, err := r.db.ExecContext(ctx, "INSERT INTO order_items (id, order_id, menu_item_id, quantity) VALUES ($1, $2, $3, $4)", item.ID, item.OrderID, item.MenuItemID, item.Quantity)
+// AddOrderItem adds an item to an order in the database. The order is locked so it cannot leave cart status
+// while the item goes in, and the upsert folds concurrent adds of one menu item into a single line. item is
+// updated with the ID and quantity of that line.
+func (r *postgresOrderRepository) AddOrderItem(ctx context.Context, item *OrderItems) error {
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
-	return nil
+	defer tx.Rollback()
+
+	var status OrderStatus
+	err = tx.QueryRowContext(ctx, "SELECT status FROM orders WHERE id = $1 FOR UPDATE", item.OrderID).Scan(&status)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return errors.ErrOrderNotFound
+		}
+		return err
+	}
+	if status != OrderStatusCart {
+		return errors.NewValidationError("can only add items to orders in cart status")
+	}
+
+	err = tx.QueryRowContext(ctx, `INSERT INTO order_items (id, order_id, menu_item_id, quantity) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (order_id, menu_item_id) DO UPDATE SET quantity = order_items.quantity + EXCLUDED.quantity
+		RETURNING id, quantity`,
+		item.ID, item.OrderID, item.MenuItemID, item.Quantity).Scan(&item.ID, &item.Quantity)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
-// UpdateOrder updates an order status in the database
-func (r *postgresOrderRepository) UpdateOrder(ctx context.Context, orderID uuid.UUID, status string) error {
-	// Execute UPDATE query
-	_, err := r.db.ExecContext(ctx, "UPDATE orders SET status = $1 WHERE id = $2", status, orderID)
+// UpdateOrder updates an order status in the database if the order is still at version
+func (r *postgresOrderRepository) UpdateOrder(ctx context.Context, orderID uuid.UUID, status string, version int) error {
+	// Execute UPDATE query guarded by the version the caller read
+	result, err := r.db.ExecContext(ctx, "UPDATE orders SET status = $1, version = version + 1 WHERE id = $2 AND version = $3", status, orderID, version)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
 	if err != nil {
 		return err
 	}
+	if rowsAffected == 0 {
+		var exists bool
+		if err := r.db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM orders WHERE id = $1)", orderID).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			return errors.ErrOrderNotFound
+		}
+		return errors.ErrVersionConflict
+	}
 	return nil
 }
 
@@ -178,22 +214,13 @@ func (r *postgresOrderRepository) GetOrderItems(ctx context.Context, orderID uui
 	return items, nil
 }
 
-// UpdateOrderItemQuantity updates the quantity of an existing order item
-func (r *postgresOrderRepository) UpdateOrderItemQuantity(ctx context.Context, itemID uuid.UUID, quantity int) error {
-	_, err := r.db.ExecContext(ctx, "UPDATE order_items SET quantity = $1 WHERE id = $2", quantity, itemID)
-	if err != nil {
-		return err
-	}
-	return nil
-}
-
 // CreateOrderWithItems atomically creates an order and its items in a transaction
 func (r *postgresOrderRepository) CreateOrderWithItems(ctx context.Context, order *Order, items []*OrderItems, tx *sql.Tx) error {
 	// Insert order within transaction
 	_, err := tx.ExecContext(
 		ctx,
-		"INSERT INTO orders (id, session_id, status, created_at) VALUES ($1, $2, $3, $4)",
-		order.ID, order.SessionID, order.Status, order.CreatedAt,
+		"INSERT INTO orders (id, session_id, status, created_at, server_id) VALUES ($1, $2, $3, $4, $5)",
+		order.ID, order.SessionID, order.Status, order.CreatedAt, order.ServerID,
 	)
 	if err != nil {
 		return errors.WrapError(500, "failed to create order in transaction", err)
@@ -232,7 +259,7 @@ func (r *postgresOrderRepository) UpdateOrderItemsInTx(ctx context.Context, item
 // GetOrdersBySession retrieves orders by session ID
 func (r *postgresOrderRepository) GetOrdersBySession(ctx context.Context, sessionID uuid.UUID) ([]*Order, error) {
 	// Execute SELECT query
-	rows, err := r.db.QueryContext(ctx, "SELECT id, session_id, status, created_at FROM orders WHERE session_id = $1", sessionID)
+	rows, err := r.db.QueryContext(ctx, "SELECT id, session_id, status, created_at, server_id, version FROM orders WHERE session_id = $1", sessionID)
 	if err != nil {
 		return nil, err
 	}
@@ -242,7 +269,7 @@ func (r *postgresOrderRepository) GetOrdersBySession(ctx context.Context, sessio
 	// Iterate through rows and scan into order structs
 	for rows.Next() {
 		var order Order
-		err := rows.Scan(&order.ID, &order.SessionID, &order.Status, &order.CreatedAt)
+		err := rows.Scan(&order.ID, &order.SessionID, &order.Status, &order.CreatedAt, &order.ServerID, &order.Version)
 		if err != nil {
 			return nil, err
 		}
@@ -278,3 +305,26 @@ func (r *postgresOrderRepository) GetOrderItemsByOrderIDs(ctx context.Context, o
 	}
 	return items, nil
 }
+
+// ListOrdersByServer lists a server's orders in the given status, oldest first
+func (r *postgresOrderRepository) ListOrdersByServer(ctx context.Context, serverID uuid.UUID, status OrderStatus) ([]*Order, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT id, session_id, status, created_at, server_id, version FROM orders WHERE server_id = $1 AND status = $2 ORDER BY created_at", serverID, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	orders := []*Order{}
+	for rows.Next() {
+		var order Order
+		err := rows.Scan(&order.ID, &order.SessionID, &order.Status, &order.CreatedAt, &order.ServerID, &order.Version)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, &order)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
@@ -0,0 +1,172 @@
+package order
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"restaurant/internal/dbtest"
+	apperrors "restaurant/internal/errors"
+	"restaurant/internal/menu"
+	"restaurant/internal/session"
+
+	"github.com/google/uuid"
+)
+
+// adders is how many requests add the same menu item at once
+const adders = 40
+
+// newTestCart creates an order in cart status and an in-stock menu item to add to it. Everything is removed
+// when the test ends.
+func newTestCart(t *testing.T, db *sql.DB) (orderID uuid.UUID, menuItemID uuid.UUID) {
+	t.Helper()
+	ctx := context.Background()
+	now := time.Now()
+
+	tableID := dbtest.NewTable(t, db)
+	categoryID, menuItemID, sessionID, orderID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	t.Cleanup(func() {
+		for _, cleanup := range []struct {
+			query string
+			key   any
+		}{
+			{"DELETE FROM order_items WHERE order_id = $1", orderID},
+			{"DELETE FROM orders WHERE id = $1", orderID},
+			{"DELETE FROM sessions WHERE id = $1", sessionID},
+			{"DELETE FROM menu_items WHERE id = $1", menuItemID},
+			{"DELETE FROM categories WHERE id = $1", categoryID},
+		} {
+			if _, err := db.ExecContext(ctx, cleanup.query, cleanup.key); err != nil {
+				t.Errorf("failed to remove test data: %v", err)
+			}
+		}
+	})
+
+	menuRepo := menu.NewMenuRepository(db)
+	if err := menuRepo.CreateCategory(ctx, &menu.Category{ID: categoryID, Name: "test " + categoryID.String()}); err != nil {
+		t.Fatal(err)
+	}
+	err := menuRepo.CreateMenuItem(ctx, &menu.MenuItem{
+		ID:                menuItemID,
+		Name:              "test " + menuItemID.String(),
+		Description:       "concurrency test item",
+		Price:             1,
+		CategoryID:        categoryID,
+		AvalabilityStatus: menu.ItemStatusInStock,
+		CreatedAt:         now,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := session.NewPostgresRepository(db).CreateSession(ctx, sessionID, tableID, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	order := &Order{ID: orderID, SessionID: sessionID, Status: OrderStatusCart, CreatedAt: now}
+	if err := NewOrderRepository(db).CreateOrder(ctx, order); err != nil {
+		t.Fatal(err)
+	}
+	return orderID, menuItemID
+}
+
+// lines returns how many lines of an order hold a menu item and their total quantity
+func lines(t *testing.T, repo OrderRepository, orderID uuid.UUID, menuItemID uuid.UUID) (int, int) {
+	t.Helper()
+	items, err := repo.GetOrderItems(context.Background(), orderID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	count, quantity := 0, 0
+	for _, item := range items {
+		if item.MenuItemID == menuItemID {
+			count++
+			quantity += item.Quantity
+		}
+	}
+	return count, quantity
+}
+
+func TestAddOrderItemConcurrent(t *testing.T) {
+	db := dbtest.Open(t)
+	repo := NewOrderRepository(db)
+	orderID, menuItemID := newTestCart(t, db)
+
+	var wg sync.WaitGroup
+	for range adders {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := repo.AddOrderItem(context.Background(), &OrderItems{ID: uuid.New(), OrderID: orderID, MenuItemID: menuItemID, Quantity: 1})
+			if err != nil {
+				t.Errorf("AddOrderItem: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	count, quantity := lines(t, repo, orderID, menuItemID)
+	if count != 1 {
+		t.Errorf("menu item is on %d lines, want 1", count)
+	}
+	if quantity != adders {
+		t.Errorf("quantity is %d, want %d", quantity, adders)
+	}
+}
+
+func TestAddOrderItemDuringSubmit(t *testing.T) {
+	db := dbtest.Open(t)
+	repo := NewOrderRepository(db)
+	orderID, menuItemID := newTestCart(t, db)
+	ctx := context.Background()
+
+	// Adds that lose the race to the submit are turned away because the order has left cart status
+	rejected := func(err error) bool {
+		return apperrors.GetHTTPStatusCode(err) == http.StatusBadRequest
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var added int
+	for i := range adders {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := repo.AddOrderItem(ctx, &OrderItems{ID: uuid.New(), OrderID: orderID, MenuItemID: menuItemID, Quantity: 1})
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				added++
+			case !rejected(err):
+				t.Errorf("AddOrderItem: %v", err)
+			}
+		}()
+		if i == adders/2 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := repo.UpdateOrder(ctx, orderID, string(OrderStatusPending), 1); err != nil {
+					t.Errorf("UpdateOrder: %v", err)
+				}
+			}()
+		}
+	}
+	wg.Wait()
+
+	err := repo.AddOrderItem(ctx, &OrderItems{ID: uuid.New(), OrderID: orderID, MenuItemID: menuItemID, Quantity: 1})
+	if !rejected(err) {
+		t.Errorf("adding to a submitted order returned %v, want a validation error", err)
+	}
+
+	count, quantity := lines(t, repo, orderID, menuItemID)
+	if added > 0 && count != 1 {
+		t.Errorf("menu item is on %d lines, want 1", count)
+	}
+	if quantity != added {
+		t.Errorf("quantity is %d, want the %d adds that succeeded", quantity, added)
+	}
+}
@@ -16,11 +16,12 @@ type OrderService interface {
 	CreateOrder(ctx context.Context, sessionID uuid.UUID) (*Order, error)
 	GetOrder(ctx context.Context, id uuid.UUID) (*Order, error)
 	ListOrders(ctx context.Context, limit int, offset int) ([]*Order, error)
-	UpdateOrder(ctx context.Context, orderID uuid.UUID, status string) (*Order, error)
+	UpdateOrder(ctx context.Context, orderID uuid.UUID, status string, version int) (*Order, error)
 	CreateOrderItem(ctx context.Context, itemID uuid.UUID, quantity int, orderID uuid.UUID) (*OrderItems, error)
 	GetOrderItems(ctx context.Context, orderID uuid.UUID) ([]*OrderItems, error)
 	GetOrdersBySession(ctx context.Context, sessionID uuid.UUID) ([]*Order, error)
 	GetOrderItemsBySessionID(ctx context.Context, sessionID uuid.UUID) ([]*OrderItems, error)
+	ListReadyOrdersByServer(ctx context.Context, serverID uuid.UUID) ([]*Order, error)
 }
 
 // orderService implements OrderService
@@ -44,17 +45,19 @@ func NewOrderService(repo OrderRepository, menuService menu.MenuService, session
 // CreateOrder creates a new order for the given session ID with validation
 func (s *orderService) CreateOrder(ctx context.Context, sessionID uuid.UUID) (*Order, error) {
 	// Validate that the session exists
-	_, err := s.sessionService.GetSession(ctx, sessionID)
+	currentSession, err := s.sessionService.GetSession(ctx, sessionID)
 	if err != nil {
 		return nil, apperrors.NewNotFoundError("session not found")
 	}
 
-	// Create new order with generated UUID, initial status 'cart', and current timestamp
+	// Create new order with generated UUID, initial status 'cart', and current timestamp.
+	// The session's server delivers it.
 	order := &Order{
 		ID:        uuid.New(),
 		SessionID: sessionID,
 		Status:    "cart",
 		CreatedAt: time.Now(),
+		ServerID:  currentSession.ServerID,
 	}
 	// Persist the order in the repository
 	err = s.repo.CreateOrder(ctx, order)
@@ -85,22 +88,30 @@ func (s *orderService) ListOrders(ctx context.Context, limit int, offset int) ([
 	return orders, nil
 }
 
-// UpdateOrder updates an order status with validation
-func (s *orderService) UpdateOrder(ctx context.Context, orderID uuid.UUID, status string) (*Order, error) {
+// UpdateOrder updates the status of an order that is still at version, with validation
+func (s *orderService) UpdateOrder(ctx context.Context, orderID uuid.UUID, status string, version int) (*Order, error) {
 	// Get current order to validate state transition
 	currentOrder, err := s.repo.GetOrder(ctx, orderID)
 	if err != nil {
 		return nil, apperrors.WrapError(500, "failed to retrieve order", err)
 	}
 
+	// The transition is checked against the version the caller saw, not whatever is current
+	if currentOrder.Version != version {
+		return nil, apperrors.ErrVersionConflict
+	}
+
 	// Validate state transition
 	if err := s.validateOrderStatusTransition(currentOrder, OrderStatus(status)); err != nil {
 		return nil, err
 	}
 
 	// Update order status in repository
-	err = s.repo.UpdateOrder(ctx, orderID, status)
+	err = s.repo.UpdateOrder(ctx, orderID, status, version)
 	if err != nil {
+		if err == apperrors.ErrVersionConflict {
+			return nil, err
+		}
 		return nil, apperrors.WrapError(500, "failed to update order status", err)
 	}
 
@@ -136,8 +147,12 @@ func (s *orderService) validateOrderStatusTransition(order *Order, newStatus Ord
 			return apperrors.NewValidationError("pending orders can only transition to preparing")
 		}
 	case OrderStatusPreparing:
+		if newStatus != OrderStatusReady && newStatus != OrderStatusServed {
+			return apperrors.NewValidationError("preparing orders can only transition to ready or served")
+		}
+	case OrderStatusReady:
 		if newStatus != OrderStatusServed {
-			return apperrors.NewValidationError("preparing orders can only transition to served")
+			return apperrors.NewValidationError("ready orders can only transition to served")
 		}
 	case OrderStatusServed:
 		return apperrors.NewValidationError("served orders cannot be updated")
@@ -150,69 +165,40 @@ func (s *orderService) validateOrderStatusTransition(order *Order, newStatus Ord
 	return nil
 }
 
-// CreateOrderItem creates a new order item with validation or updates quantity if item already exists
+// CreateOrderItem adds an item to an order in cart status, or adds to the quantity of the item's existing line
 func (s *orderService) CreateOrderItem(ctx context.Context, itemID uuid.UUID, quantity int, orderID uuid.UUID) (*OrderItems, error) {
 	// Shape validation (quantity > 0) already done by handler using ValidateStruct
 
-	// Get the order to check its status
-	order, err := s.repo.GetOrder(ctx, orderID)
-	if err != nil {
-		return nil, apperrors.WrapError(500, "failed to retrieve order", err)
-	}
-
-	// Only allow adding items if order is in cart status
-	if order.Status != OrderStatusCart {
-		return nil, apperrors.NewValidationError("can only add items to orders in cart status")
-	}
-
 	// Validate menu item exists and is available (BUSINESS LOGIC)
 	menuItem, err := s.menuService.GetMenuItem(ctx, itemID)
 	if err != nil {
 		return nil, err
 	}
+	if menuItem.DeletedAt != nil {
+		return nil, apperrors.ErrMenuItemDeleted
+	}
 	if menuItem.AvalabilityStatus != "in_stock" {
 		return nil, apperrors.ErrOutOfStock
 	}
 
-	// Check if this menu item already exists in the order
-	existingItems, err := s.repo.GetOrderItems(ctx, orderID)
-	if err != nil {
-		return nil, apperrors.WrapError(500, "failed to check existing order items", err)
-	}
-
-	// Look for existing item with same menu_item_id
-	for _, item := range existingItems {
-		if item.MenuItemID == itemID {
-			// Update existing item's quantity
-			newQuantity := item.Quantity + quantity
-			err = s.repo.UpdateOrderItemQuantity(ctx, item.ID, newQuantity)
-			if err != nil {
-				return nil, apperrors.WrapError(500, "failed to update order item quantity", err)
-			}
-			// Return the updated item
-			item.Quantity = newQuantity
-			return item, nil
-		}
-	}
-
-	// Create new order item if it doesn't exist
-	Item := &OrderItems{
+	// The repository checks the order is in cart status and merges the item into an existing line atomically,
+	// so concurrent adds neither lose quantities nor create duplicate lines
+	item := &OrderItems{
 		ID:         uuid.New(),
 		MenuItemID: itemID,
 		Quantity:   quantity,
 		OrderID:    orderID,
 	}
-	// Persist order item in repository
-	err = s.repo.CreateOrderItem(ctx, Item)
+	err = s.repo.AddOrderItem(ctx, item)
 	if err != nil {
 		// Check for foreign key constraint violation
 		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
 			return nil, apperrors.ErrForeignKeyViolation
 		}
-		return nil, apperrors.WrapError(500, "failed to create order item", err)
+		return nil, apperrors.WrapError(500, "failed to add order item", err)
 	}
 
-	return Item, nil
+	return item, nil
 }
 
 // GetOrderItems retrieves order items by order ID with validation
@@ -262,3 +248,12 @@ func (s *orderService) GetOrderItemsBySessionID(ctx context.Context, sessionID u
 	}
 	return items, nil
 }
+
+// ListReadyOrdersByServer lists the orders waiting at the pass for a server to take to the table, oldest first
+func (s *orderService) ListReadyOrdersByServer(ctx context.Context, serverID uuid.UUID) ([]*Order, error) {
+	orders, err := s.repo.ListOrdersByServer(ctx, serverID, OrderStatusReady)
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to list ready orders", err)
+	}
+	return orders, nil
+}
@@ -11,7 +11,8 @@ type CreateOrderRequest struct {
 
 // UpdateOrderRequest represents the request to update an order
 type UpdateOrderRequest struct {
-	Status OrderStatus `json:"status" validate:"required,oneof=cart pending preparing served cancelled"`
+	Status  OrderStatus `json:"status" validate:"required,oneof=cart pending preparing ready served cancelled"`
+	Version *int        `json:"version" validate:"omitempty,min=1"` // version being updated, if not sent in If-Match
 }
 
 // ListOrdersRequest represents the request to list orders with pagination
@@ -0,0 +1,22 @@
+// Package qrcode renders short strings as QR codes for printing.
+//
+// Encoding is left to github.com/skip2/go-qrcode. Codes use error correction level M, which survives a worn or
+// partly covered table sticker while keeping table links at a small version.
+package qrcode
+
+import (
+	qr "github.com/skip2/go-qrcode"
+)
+
+// PNG encodes content and renders it as a black on white PNG image about size pixels wide, including the quiet zone.
+// Modules are drawn as whole pixels, so the image is never smaller than one pixel per module.
+func PNG(content string, size int) ([]byte, error) {
+	code, err := qr.New(content, qr.Medium)
+	if err != nil {
+		return nil, err
+	}
+	// Rounding down to a multiple of the module count keeps every module the same width, which printers and
+	// scanners handle better than the uneven widths of an arbitrary size
+	modules := len(code.Bitmap())
+	return code.PNG(max(1, size/modules) * modules)
+}
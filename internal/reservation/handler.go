@@ -0,0 +1,300 @@
+package reservation
+
+import (
+	"time"
+
+	"restaurant/internal/errors"
+	"restaurant/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler handles HTTP requests for reservations
+type Handler struct {
+	svc     ReservationService
+	limiter *middleware.RateLimiter
+}
+
+// NewHandler creates a new reservation handler. Its rate limit policies are attached to limiter, which may be nil.
+func NewHandler(svc ReservationService, limiter *middleware.RateLimiter) *Handler {
+	return &Handler{svc: svc, limiter: limiter}
+}
+
+// reservationPermissions declares what each reservation route needs. Guests can look for a free table and book
+// it; managing the book needs floor staff.
+var reservationPermissions = middleware.RoutePermissions{
+	"GET /reservations":              middleware.PermReservationsManage,
+	"POST /reservations":             middleware.PermPublic,
+	"GET /reservations/availability": middleware.PermPublic,
+	"GET /reservations/:id":          middleware.PermReservationsManage,
+	"PUT /reservations/:id":          middleware.PermReservationsManage,
+	"PUT /reservations/:id/status":   middleware.PermReservationsManage,
+	"POST /reservations/:id/seat":    middleware.PermReservationsManage,
+}
+
+// reservationRateLimits puts public booking requests under a tight limit per IP address
+var reservationRateLimits = middleware.RouteRateLimits{
+	"POST /reservations": middleware.PolicyBooking,
+}
+
+// RegisterRoutes registers all reservation routes with the Gin router
+func (h *Handler) RegisterRoutes(router *gin.Engine) {
+	if h.limiter != nil {
+		h.limiter.Attach(reservationRateLimits)
+	}
+
+	reservationGroup := router.Group("/reservations", reservationPermissions.Authorize())
+	{
+		reservationGroup.GET("", h.ListReservations)
+		reservationGroup.POST("", h.CreateReservation)
+		reservationGroup.GET("/availability", h.SearchAvailability)
+		reservationGroup.GET("/:id", h.GetReservation)
+		reservationGroup.PUT("/:id", h.UpdateReservation)
+		reservationGroup.PUT("/:id/status", h.UpdateReservationStatus)
+		reservationGroup.POST("/:id/seat", h.SeatReservation)
+	}
+}
+
+// CreateReservation handles POST /reservations
+// @Summary Create a reservation
+// @Description Book a table for a party; fails if the slot overlaps another booking or a live session on the table
+// @Tags Reservations
+// @Accept json
+// @Produce json
+// @Param request body CreateReservationRequest true "Reservation request"
+// @Success 201 {object} Reservation
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 409 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /reservations [post]
+func (h *Handler) CreateReservation(c *gin.Context) {
+	var req CreateReservationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	if err := ValidateCreateReservation(req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	res, err := h.svc.CreateReservation(c.Request.Context(), &req)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.JSON(201, res)
+}
+
+// GetReservation handles GET /reservations/:id
+// @Summary Get reservation by ID
+// @Description Retrieve a specific reservation by its ID
+// @Tags Reservations
+// @Accept json
+// @Produce json
+// @Param id path string true "Reservation ID (UUID)"
+// @Success 200 {object} Reservation
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /reservations/{id} [get]
+func (h *Handler) GetReservation(c *gin.Context) {
+	id, ok := middleware.UUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	res, err := h.svc.GetReservation(c.Request.Context(), id)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.JSON(200, res)
+}
+
+// ListReservations handles GET /reservations
+// @Summary List reservations for a day
+// @Description Booking calendar: reservations starting on the given date, in start time order
+// @Tags Reservations
+// @Accept json
+// @Produce json
+// @Param date query string false "Date as YYYY-MM-DD (default today)"
+// @Param status query string false "Filter by status (booked, seated, no_show, cancelled)"
+// @Success 200 {array} Reservation
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /reservations [get]
+func (h *Handler) ListReservations(c *gin.Context) {
+	var req ListReservationsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	if err := ValidateListReservations(req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	date := time.Now()
+	if req.Date != "" {
+		date, _ = time.Parse("2006-01-02", req.Date) // format already validated
+	}
+
+	reservations, err := h.svc.ListReservations(c.Request.Context(), date, ReservationStatus(req.Status))
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.JSON(200, reservations)
+}
+
+// UpdateReservation handles PUT /reservations/:id
+// @Summary Update a reservation
+// @Description Change the table, time, duration, party size or contact details of a booked reservation
+// @Tags Reservations
+// @Accept json
+// @Produce json
+// @Param id path string true "Reservation ID (UUID)"
+// @Param request body UpdateReservationRequest true "Fields to change"
+// @Success 200 {object} Reservation
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 409 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /reservations/{id} [put]
+func (h *Handler) UpdateReservation(c *gin.Context) {
+	id, ok := middleware.UUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req UpdateReservationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	if err := ValidateUpdateReservation(req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	res, err := h.svc.UpdateReservation(c.Request.Context(), id, &req)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.JSON(200, res)
+}
+
+// UpdateReservationStatus handles PUT /reservations/:id/status
+// @Summary Cancel a reservation or mark a no-show
+// @Description Move a booked reservation to cancelled, or to no_show once its start time has passed
+// @Tags Reservations
+// @Accept json
+// @Produce json
+// @Param id path string true "Reservation ID (UUID)"
+// @Param request body UpdateReservationStatusRequest true "New status"
+// @Success 200 {object} Reservation
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 409 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /reservations/{id}/status [put]
+func (h *Handler) UpdateReservationStatus(c *gin.Context) {
+	id, ok := middleware.UUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req UpdateReservationStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	if err := ValidateUpdateReservationStatus(req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	res, err := h.svc.UpdateStatus(c.Request.Context(), id, req.Status)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.JSON(200, res)
+}
+
+// SeatReservation handles POST /reservations/:id/seat
+// @Summary Seat a reservation
+// @Description Start a dining session on the reserved table and mark the reservation as seated
+// @Tags Reservations
+// @Accept json
+// @Produce json
+// @Param id path string true "Reservation ID (UUID)"
+// @Success 200 {object} Reservation
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 409 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /reservations/{id}/seat [post]
+func (h *Handler) SeatReservation(c *gin.Context) {
+	id, ok := middleware.UUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	res, err := h.svc.SeatReservation(c.Request.Context(), id)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.JSON(200, res)
+}
+
+// SearchAvailability handles GET /reservations/availability
+// @Summary Search table availability
+// @Description List bookable start times on a date with the tables free for the whole slot
+// @Tags Reservations
+// @Accept json
+// @Produce json
+// @Param date query string true "Date as YYYY-MM-DD"
+// @Param party_size query int true "Number of guests"
+// @Param duration_minutes query int false "Slot length in minutes (default 90)"
+// @Success 200 {array} AvailabilitySlot
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /reservations/availability [get]
+func (h *Handler) SearchAvailability(c *gin.Context) {
+	var req AvailabilityRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	if err := ValidateAvailability(req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	date, _ := time.Parse("2006-01-02", req.Date) // format already validated
+	duration := time.Duration(req.DurationMinutes) * time.Minute
+
+	slots, err := h.svc.SearchAvailability(c.Request.Context(), date, req.PartySize, duration)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.JSON(200, slots)
+}
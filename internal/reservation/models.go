@@ -0,0 +1,53 @@
+package reservation
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReservationStatus represents the possible states of a reservation
+type ReservationStatus string
+
+const (
+	StatusBooked    ReservationStatus = "booked"
+	StatusSeated    ReservationStatus = "seated"
+	StatusNoShow    ReservationStatus = "no_show"
+	StatusCancelled ReservationStatus = "cancelled"
+)
+
+// Reservation holds a table for a party over [StartsAt, EndsAt)
+type Reservation struct {
+	ID         uuid.UUID         `json:"id"`          // unique reservation ID
+	TableID    int               `json:"table_id"`    // reserved table
+	PartySize  int               `json:"party_size"`  // number of guests
+	StartsAt   time.Time         `json:"starts_at"`   // start of the time slot
+	EndsAt     time.Time         `json:"ends_at"`     // end of the time slot (StartsAt + duration)
+	GuestName  string            `json:"guest_name"`  // name the booking is under
+	GuestPhone string            `json:"guest_phone"` // contact phone, may be empty
+	GuestEmail string            `json:"guest_email"` // contact email, may be empty
+	Notes      string            `json:"notes"`       // free-form notes such as allergies or occasions
+	Status     ReservationStatus `json:"status"`      // e.g., StatusBooked, StatusSeated
+	SessionID  *uuid.UUID        `json:"session_id"`  // session created when the party was seated, nil until then
+	CreatedAt  time.Time         `json:"created_at"`  // when the reservation was made
+	UpdatedAt  time.Time         `json:"updated_at"`  // when the reservation was last changed
+}
+
+// Duration returns the length of the reserved time slot
+func (r *Reservation) Duration() time.Duration {
+	return r.EndsAt.Sub(r.StartsAt)
+}
+
+// Occupancy is a span of time a table is held, by a reservation or a live session
+type Occupancy struct {
+	TableID  int
+	StartsAt time.Time
+	EndsAt   time.Time
+}
+
+// AvailabilitySlot lists the tables free for a whole reservation starting at StartsAt
+type AvailabilitySlot struct {
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+	TableIDs []int     `json:"table_ids"`
+}
@@ -0,0 +1,225 @@
+package reservation
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"restaurant/internal/errors"
+
+	"github.com/google/uuid"
+)
+
+// reservationColumns is the column list scanned by scanReservation
+const reservationColumns = "id, table_id, party_size, starts_at, ends_at, guest_name, guest_phone, guest_email, notes, status, session_id, created_at, updated_at"
+
+// Repository defines methods for reservation database operations
+type Repository interface {
+	// CreateReservation inserts a reservation unless its slot overlaps another booking or a live session on the table
+	CreateReservation(ctx context.Context, r *Reservation, sessionHold time.Duration) error
+
+	// GetReservation retrieves a reservation by ID
+	GetReservation(ctx context.Context, id uuid.UUID) (*Reservation, error)
+
+	// ListReservations lists reservations starting in [from, to), optionally filtered by status
+	ListReservations(ctx context.Context, from time.Time, to time.Time, status ReservationStatus) ([]*Reservation, error)
+
+	// UpdateReservation rewrites a booked reservation with the same conflict checks as CreateReservation
+	UpdateReservation(ctx context.Context, r *Reservation, sessionHold time.Duration) error
+
+	// UpdateStatus moves a reservation from one status to another, failing if it is no longer in the expected status
+	UpdateStatus(ctx context.Context, id uuid.UUID, from ReservationStatus, to ReservationStatus, sessionID *uuid.UUID) error
+
+	// ListOccupancy lists the spans tables are held by reservations or live sessions that overlap [from, to)
+	ListOccupancy(ctx context.Context, from time.Time, to time.Time, sessionHold time.Duration) ([]Occupancy, error)
+}
+
+// postgresRepository implements Repository using PostgreSQL
+type postgresRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresRepository creates a new PostgreSQL-based reservation repository
+func NewPostgresRepository(db *sql.DB) Repository {
+	return &postgresRepository{db: db}
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanReservation(row rowScanner) (*Reservation, error) {
+	var r Reservation
+	var status string
+	err := row.Scan(&r.ID, &r.TableID, &r.PartySize, &r.StartsAt, &r.EndsAt, &r.GuestName, &r.GuestPhone, &r.GuestEmail,
+		&r.Notes, &status, &r.SessionID, &r.CreatedAt, &r.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	r.Status = ReservationStatus(status)
+	return &r, nil
+}
+
+// checkSlot locks the table and reports ErrReservationConflict if the slot of r overlaps another booked or
+// seated reservation, or a live session assumed to hold the table for at least sessionHold after it started
+func checkSlot(ctx context.Context, tx *sql.Tx, r *Reservation, sessionHold time.Duration) error {
+	var tableID int
+	err := tx.QueryRowContext(ctx, "SELECT id FROM tables WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", r.TableID).Scan(&tableID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return errors.NewNotFoundError("table not found")
+		}
+		return err
+	}
+
+	var conflict bool
+	err = tx.QueryRowContext(ctx, `SELECT EXISTS (
+		SELECT 1 FROM reservations
+		WHERE table_id = $1 AND id <> $4 AND status IN ('booked', 'seated')
+			AND starts_at < $3 AND ends_at > $2
+	) OR EXISTS (
+		SELECT 1 FROM session_table_assignments
+		WHERE table_id = $1 AND status IN ('active', 'pending')
+			AND created_at < $3 AND GREATEST(NOW(), created_at + $5 * INTERVAL '1 second') > $2
+	)`, r.TableID, r.StartsAt, r.EndsAt, r.ID, sessionHold.Seconds()).Scan(&conflict)
+	if err != nil {
+		return err
+	}
+	if conflict {
+		return errors.ErrReservationConflict
+	}
+	return nil
+}
+
+// CreateReservation inserts a reservation after checking its slot inside the same transaction
+func (r *postgresRepository) CreateReservation(ctx context.Context, res *Reservation, sessionHold time.Duration) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.NewInternalError("failed to begin transaction", err)
+	}
+	defer tx.Rollback()
+
+	if err := checkSlot(ctx, tx, res, sessionHold); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "INSERT INTO reservations ("+reservationColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)",
+		res.ID, res.TableID, res.PartySize, res.StartsAt, res.EndsAt, res.GuestName, res.GuestPhone, res.GuestEmail,
+		res.Notes, res.Status, res.SessionID, res.CreatedAt, res.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetReservation retrieves a reservation by ID
+func (r *postgresRepository) GetReservation(ctx context.Context, id uuid.UUID) (*Reservation, error) {
+	res, err := scanReservation(r.db.QueryRowContext(ctx, "SELECT "+reservationColumns+" FROM reservations WHERE id = $1", id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ErrReservationNotFound
+		}
+		return nil, errors.NewInternalError("failed to get reservation", err)
+	}
+	return res, nil
+}
+
+// ListReservations lists reservations in start time order
+func (r *postgresRepository) ListReservations(ctx context.Context, from time.Time, to time.Time, status ReservationStatus) ([]*Reservation, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+reservationColumns+" FROM reservations WHERE starts_at >= $1 AND starts_at < $2 AND ($3 = '' OR status = $3) ORDER BY starts_at, table_id",
+		from, to, string(status))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reservations := []*Reservation{}
+	for rows.Next() {
+		res, err := scanReservation(rows)
+		if err != nil {
+			return nil, err
+		}
+		reservations = append(reservations, res)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return reservations, nil
+}
+
+// UpdateReservation rewrites the table, slot, party and contact details of a booked reservation
+func (r *postgresRepository) UpdateReservation(ctx context.Context, res *Reservation, sessionHold time.Duration) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.NewInternalError("failed to begin transaction", err)
+	}
+	defer tx.Rollback()
+
+	if err := checkSlot(ctx, tx, res, sessionHold); err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, `UPDATE reservations SET table_id = $1, party_size = $2, starts_at = $3, ends_at = $4,
+		guest_name = $5, guest_phone = $6, guest_email = $7, notes = $8, updated_at = $9
+		WHERE id = $10 AND status = $11`,
+		res.TableID, res.PartySize, res.StartsAt, res.EndsAt, res.GuestName, res.GuestPhone, res.GuestEmail, res.Notes, res.UpdatedAt,
+		res.ID, StatusBooked)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.NewConflictError("only booked reservations can be changed")
+	}
+
+	return tx.Commit()
+}
+
+// UpdateStatus performs a conditional status transition, recording the session when a party is seated
+func (r *postgresRepository) UpdateStatus(ctx context.Context, id uuid.UUID, from ReservationStatus, to ReservationStatus, sessionID *uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, "UPDATE reservations SET status = $1, session_id = COALESCE($2, session_id), updated_at = $3 WHERE id = $4 AND status = $5",
+		to, sessionID, time.Now(), id, from)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.NewConflictError("reservation is no longer " + string(from))
+	}
+	return nil
+}
+
+// ListOccupancy returns blocking reservations and live sessions; a live session is assumed to hold its table
+// until sessionHold after it started, or until now if it has run longer than that
+func (r *postgresRepository) ListOccupancy(ctx context.Context, from time.Time, to time.Time, sessionHold time.Duration) ([]Occupancy, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT table_id, starts_at, ends_at FROM reservations
+		WHERE status IN ('booked', 'seated') AND starts_at < $2 AND ends_at > $1
+	UNION ALL
+	SELECT table_id, created_at::timestamptz, GREATEST(NOW(), created_at + $3 * INTERVAL '1 second')::timestamptz FROM session_table_assignments
+		WHERE status IN ('active', 'pending') AND created_at < $2 AND GREATEST(NOW(), created_at + $3 * INTERVAL '1 second') > $1`,
+		from, to, sessionHold.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var occupancy []Occupancy
+	for rows.Next() {
+		var o Occupancy
+		if err := rows.Scan(&o.TableID, &o.StartsAt, &o.EndsAt); err != nil {
+			return nil, err
+		}
+		occupancy = append(occupancy, o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return occupancy, nil
+}
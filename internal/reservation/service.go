@@ -0,0 +1,305 @@
+package reservation
+
+import (
+	"context"
+	"fmt"
+	apperrors "restaurant/internal/errors"
+	"restaurant/internal/session"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Config holds reservation scheduling configuration
+type Config struct {
+	DefaultDuration time.Duration  // slot length when a booking does not specify one
+	SessionHold     time.Duration  // how long a live session is assumed to hold its table after it started
+	SlotInterval    time.Duration  // spacing between start times offered by the availability search
+	OpensAt         time.Duration  // offset from midnight of the first bookable start time
+	LastSeating     time.Duration  // offset from midnight of the last bookable start time
+	Location        *time.Location // time zone that calendar dates are interpreted in
+}
+
+// DefaultConfig returns the reservation configuration used when none is provided
+func DefaultConfig() Config {
+	return Config{
+		DefaultDuration: 90 * time.Minute,
+		SessionHold:     2 * time.Hour,
+		SlotInterval:    15 * time.Minute,
+		OpensAt:         11 * time.Hour,
+		LastSeating:     22 * time.Hour,
+		Location:        time.Local,
+	}
+}
+
+// ReservationService defines business logic for reservations
+type ReservationService interface {
+	CreateReservation(ctx context.Context, req *CreateReservationRequest) (*Reservation, error)
+	GetReservation(ctx context.Context, id uuid.UUID) (*Reservation, error)
+	ListReservations(ctx context.Context, date time.Time, status ReservationStatus) ([]*Reservation, error)
+	UpdateReservation(ctx context.Context, id uuid.UUID, req *UpdateReservationRequest) (*Reservation, error)
+	UpdateStatus(ctx context.Context, id uuid.UUID, status ReservationStatus) (*Reservation, error)
+	SeatReservation(ctx context.Context, id uuid.UUID) (*Reservation, error)
+	SearchAvailability(ctx context.Context, date time.Time, partySize int, duration time.Duration) ([]AvailabilitySlot, error)
+}
+
+// reservationService implements ReservationService
+type reservationService struct {
+	repo           Repository
+	sessionService session.SessionService
+	config         Config
+}
+
+// NewService creates a new reservation service
+func NewService(repo Repository, sessionService session.SessionService, config Config) ReservationService {
+	defaults := DefaultConfig()
+	if config.DefaultDuration <= 0 {
+		config.DefaultDuration = defaults.DefaultDuration
+	}
+	if config.SessionHold <= 0 {
+		config.SessionHold = defaults.SessionHold
+	}
+	if config.SlotInterval <= 0 {
+		config.SlotInterval = defaults.SlotInterval
+	}
+	if config.LastSeating <= config.OpensAt {
+		config.OpensAt, config.LastSeating = defaults.OpensAt, defaults.LastSeating
+	}
+	if config.Location == nil {
+		config.Location = defaults.Location
+	}
+	return &reservationService{repo: repo, sessionService: sessionService, config: config}
+}
+
+// CreateReservation books a table for a future time slot
+func (s *reservationService) CreateReservation(ctx context.Context, req *CreateReservationRequest) (*Reservation, error) {
+	// Shape validation (party size, contact, duration range) already done by handler using ValidateStruct
+	now := time.Now()
+	if !req.StartsAt.After(now) {
+		return nil, apperrors.NewValidationError("starts_at must be in the future")
+	}
+
+	duration := s.config.DefaultDuration
+	if req.DurationMinutes > 0 {
+		duration = time.Duration(req.DurationMinutes) * time.Minute
+	}
+
+	if err := s.checkCapacity(ctx, req.TableID, req.PartySize); err != nil {
+		return nil, err
+	}
+
+	res := &Reservation{
+		ID:         uuid.New(),
+		TableID:    req.TableID,
+		PartySize:  req.PartySize,
+		StartsAt:   req.StartsAt,
+		EndsAt:     req.StartsAt.Add(duration),
+		GuestName:  strings.TrimSpace(req.GuestName),
+		GuestPhone: strings.TrimSpace(req.GuestPhone),
+		GuestEmail: strings.TrimSpace(req.GuestEmail),
+		Notes:      strings.TrimSpace(req.Notes),
+		Status:     StatusBooked,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := s.repo.CreateReservation(ctx, res, s.config.SessionHold); err != nil {
+		return nil, apperrors.WrapError(500, "failed to create reservation", err)
+	}
+	return res, nil
+}
+
+// GetReservation retrieves a reservation
+func (s *reservationService) GetReservation(ctx context.Context, id uuid.UUID) (*Reservation, error) {
+	res, err := s.repo.GetReservation(ctx, id)
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to retrieve reservation", err)
+	}
+	return res, nil
+}
+
+// ListReservations lists the reservations starting on a calendar day
+func (s *reservationService) ListReservations(ctx context.Context, date time.Time, status ReservationStatus) ([]*Reservation, error) {
+	dayStart := s.startOfDay(date)
+	reservations, err := s.repo.ListReservations(ctx, dayStart, dayStart.AddDate(0, 0, 1), status)
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to list reservations", err)
+	}
+	return reservations, nil
+}
+
+// UpdateReservation changes the table, slot, party size or contact details of a booked reservation
+func (s *reservationService) UpdateReservation(ctx context.Context, id uuid.UUID, req *UpdateReservationRequest) (*Reservation, error) {
+	res, err := s.repo.GetReservation(ctx, id)
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to retrieve reservation", err)
+	}
+
+	// Only upcoming bookings can be moved; seated, cancelled and no-show reservations are history (BUSINESS LOGIC)
+	if res.Status != StatusBooked {
+		return nil, apperrors.NewConflictError("only booked reservations can be changed")
+	}
+
+	duration := res.Duration()
+	if req.DurationMinutes > 0 {
+		duration = time.Duration(req.DurationMinutes) * time.Minute
+	}
+	if req.StartsAt != nil {
+		if !req.StartsAt.After(time.Now()) {
+			return nil, apperrors.NewValidationError("starts_at must be in the future")
+		}
+		res.StartsAt = *req.StartsAt
+	}
+	res.EndsAt = res.StartsAt.Add(duration)
+	if req.TableID > 0 {
+		res.TableID = req.TableID
+	}
+	if req.PartySize > 0 {
+		res.PartySize = req.PartySize
+	}
+	if name := strings.TrimSpace(req.GuestName); name != "" {
+		res.GuestName = name
+	}
+	if req.GuestPhone != nil {
+		res.GuestPhone = strings.TrimSpace(*req.GuestPhone)
+	}
+	if req.GuestEmail != nil {
+		res.GuestEmail = strings.TrimSpace(*req.GuestEmail)
+	}
+	if req.Notes != nil {
+		res.Notes = strings.TrimSpace(*req.Notes)
+	}
+	if res.GuestPhone == "" && res.GuestEmail == "" {
+		return nil, apperrors.NewValidationError("a guest phone or email is required")
+	}
+	if err := s.checkCapacity(ctx, res.TableID, res.PartySize); err != nil {
+		return nil, err
+	}
+	res.UpdatedAt = time.Now()
+
+	if err := s.repo.UpdateReservation(ctx, res, s.config.SessionHold); err != nil {
+		return nil, apperrors.WrapError(500, "failed to update reservation", err)
+	}
+	return res, nil
+}
+
+// UpdateStatus cancels a booked reservation or marks it as a no-show
+func (s *reservationService) UpdateStatus(ctx context.Context, id uuid.UUID, status ReservationStatus) (*Reservation, error) {
+	res, err := s.repo.GetReservation(ctx, id)
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to retrieve reservation", err)
+	}
+
+	// Validate state transitions (BUSINESS LOGIC - seated, cancelled and no-show are final)
+	if res.Status != StatusBooked {
+		return nil, apperrors.NewValidationError("invalid status transition from " + string(res.Status) + " to " + string(status))
+	}
+	switch status {
+	case StatusCancelled:
+	case StatusNoShow:
+		if time.Now().Before(res.StartsAt) {
+			return nil, apperrors.NewValidationError("a reservation cannot be a no-show before its start time")
+		}
+	default:
+		return nil, apperrors.NewValidationError("invalid status transition from " + string(res.Status) + " to " + string(status))
+	}
+
+	if err := s.repo.UpdateStatus(ctx, id, StatusBooked, status, nil); err != nil {
+		return nil, apperrors.WrapError(500, "failed to update reservation status", err)
+	}
+	return s.GetReservation(ctx, id)
+}
+
+// SeatReservation starts a dining session on the reserved table and marks the reservation as seated
+func (s *reservationService) SeatReservation(ctx context.Context, id uuid.UUID) (*Reservation, error) {
+	res, err := s.repo.GetReservation(ctx, id)
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to retrieve reservation", err)
+	}
+	if res.Status != StatusBooked {
+		return nil, apperrors.NewValidationError("only booked reservations can be seated, reservation is " + string(res.Status))
+	}
+
+	// The session service refuses tables that are still occupied by a previous party
+	sess, err := s.sessionService.CreateSession(ctx, res.TableID, res.PartySize)
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to start session for reservation", err)
+	}
+
+	if err := s.repo.UpdateStatus(ctx, id, StatusBooked, StatusSeated, &sess.ID); err != nil {
+		return nil, apperrors.WrapError(500, "failed to mark reservation as seated", err)
+	}
+	return s.GetReservation(ctx, id)
+}
+
+// SearchAvailability lists, for every bookable start time on a day, the tables that seat the party and are free
+// for the whole slot
+func (s *reservationService) SearchAvailability(ctx context.Context, date time.Time, partySize int, duration time.Duration) ([]AvailabilitySlot, error) {
+	if duration <= 0 {
+		duration = s.config.DefaultDuration
+	}
+
+	dayStart := s.startOfDay(date)
+	first := dayStart.Add(s.config.OpensAt)
+	last := dayStart.Add(s.config.LastSeating)
+
+	tables, err := s.sessionService.ListTables(ctx, false)
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to list tables", err)
+	}
+	occupancy, err := s.repo.ListOccupancy(ctx, first, last.Add(duration), s.config.SessionHold)
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to load table occupancy", err)
+	}
+	byTable := make(map[int][]Occupancy)
+	for _, o := range occupancy {
+		byTable[o.TableID] = append(byTable[o.TableID], o)
+	}
+
+	now := time.Now()
+	slots := []AvailabilitySlot{}
+	for start := first; !start.After(last); start = start.Add(s.config.SlotInterval) {
+		if start.Before(now) {
+			continue
+		}
+		end := start.Add(duration)
+
+		free := []int{}
+		for _, table := range tables {
+			if table.Seats(partySize) && !overlapsAny(byTable[table.ID], start, end) {
+				free = append(free, table.ID)
+			}
+		}
+		if len(free) > 0 {
+			slots = append(slots, AvailabilitySlot{StartsAt: start, EndsAt: end, TableIDs: free})
+		}
+	}
+	return slots, nil
+}
+
+// checkCapacity rejects parties outside the table's min and max covers (BUSINESS LOGIC)
+func (s *reservationService) checkCapacity(ctx context.Context, tableID int, partySize int) error {
+	table, err := s.sessionService.GetTable(ctx, tableID)
+	if err != nil {
+		return apperrors.WrapError(500, "failed to retrieve table", err)
+	}
+	if !table.Seats(partySize) {
+		return apperrors.NewValidationError(fmt.Sprintf("table %d seats %d to %d guests", table.ID, table.MinCovers, table.MaxCovers))
+	}
+	return nil
+}
+
+// startOfDay returns midnight of the calendar day of date in the configured time zone
+func (s *reservationService) startOfDay(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, s.config.Location)
+}
+
+// overlapsAny reports whether [start, end) intersects any of the spans
+func overlapsAny(spans []Occupancy, start time.Time, end time.Time) bool {
+	for _, o := range spans {
+		if o.StartsAt.Before(end) && o.EndsAt.After(start) {
+			return true
+		}
+	}
+	return false
+}
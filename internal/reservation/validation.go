@@ -0,0 +1,81 @@
+package reservation
+
+import (
+	"errors"
+	"time"
+)
+
+// CreateReservationRequest represents the request to book a table
+type CreateReservationRequest struct {
+	TableID         int       `json:"table_id" validate:"required,gt=0"`
+	PartySize       int       `json:"party_size" validate:"required,min=1,max=100"`
+	StartsAt        time.Time `json:"starts_at" validate:"required"`
+	DurationMinutes int       `json:"duration_minutes" validate:"omitempty,min=15,max=480"`
+	GuestName       string    `json:"guest_name" validate:"required,min=1,max=100"`
+	GuestPhone      string    `json:"guest_phone" validate:"required_without=GuestEmail,omitempty,max=30"`
+	GuestEmail      string    `json:"guest_email" validate:"required_without=GuestPhone,omitempty,email,max=255"`
+	Notes           string    `json:"notes" validate:"max=1000"`
+}
+
+// UpdateReservationRequest represents a partial change to a booked reservation; omitted fields are kept
+type UpdateReservationRequest struct {
+	TableID         int        `json:"table_id" validate:"omitempty,gt=0"`
+	PartySize       int        `json:"party_size" validate:"omitempty,min=1,max=100"`
+	StartsAt        *time.Time `json:"starts_at" validate:"omitempty"`
+	DurationMinutes int        `json:"duration_minutes" validate:"omitempty,min=15,max=480"`
+	GuestName       string     `json:"guest_name" validate:"omitempty,min=1,max=100"`
+	GuestPhone      *string    `json:"guest_phone" validate:"omitempty,max=30"`
+	GuestEmail      *string    `json:"guest_email" validate:"omitempty,max=255"`
+	Notes           *string    `json:"notes" validate:"omitempty,max=1000"`
+}
+
+// UpdateReservationStatusRequest represents the request to cancel a reservation or mark it as a no-show
+type UpdateReservationStatusRequest struct {
+	Status ReservationStatus `json:"status" validate:"required,oneof=cancelled no_show"`
+}
+
+// ListReservationsRequest represents the booking calendar query for one day
+type ListReservationsRequest struct {
+	Date   string `form:"date" validate:"omitempty,datetime=2006-01-02"`
+	Status string `form:"status" validate:"omitempty,oneof=booked seated no_show cancelled"`
+}
+
+// AvailabilityRequest represents a search for free tables on a day
+type AvailabilityRequest struct {
+	Date            string `form:"date" validate:"required,datetime=2006-01-02"`
+	PartySize       int    `form:"party_size" validate:"required,min=1,max=100"`
+	DurationMinutes int    `form:"duration_minutes" validate:"omitempty,min=15,max=480"`
+}
+
+// ValidateCreateReservation validates the create reservation request
+func ValidateCreateReservation(req CreateReservationRequest) error {
+	return ValidateStruct(req)
+}
+
+// ValidateUpdateReservation validates the update reservation request
+func ValidateUpdateReservation(req UpdateReservationRequest) error {
+	if err := ValidateStruct(req); err != nil {
+		return err
+	}
+	if req.GuestEmail != nil && *req.GuestEmail != "" {
+		if err := GetValidator().Var(*req.GuestEmail, "email"); err != nil {
+			return errors.New("guest_email must be a valid email address")
+		}
+	}
+	return nil
+}
+
+// ValidateUpdateReservationStatus validates the update reservation status request
+func ValidateUpdateReservationStatus(req UpdateReservationStatusRequest) error {
+	return ValidateStruct(req)
+}
+
+// ValidateListReservations validates the list reservations request
+func ValidateListReservations(req ListReservationsRequest) error {
+	return ValidateStruct(req)
+}
+
+// ValidateAvailability validates the availability search request
+func ValidateAvailability(req AvailabilityRequest) error {
+	return ValidateStruct(req)
+}
@@ -0,0 +1,224 @@
+package session
+
+import (
+	"context"
+	"strconv"
+
+	"restaurant/internal/cache"
+
+	"github.com/google/uuid"
+)
+
+// cachedSessionService decorates a SessionService, caching session and table reads. Every write invalidates
+// the sessions or tables it touches and their lists. Reads return copies, so callers cannot change cached
+// values.
+type cachedSessionService struct {
+	SessionService
+	cache *cache.Cache[string, any]
+}
+
+// activeSessionsCacheKey is the cache key of the active session list
+var activeSessionsCacheKey = cache.ListCacheKey("session", "active")
+
+// NewCachedService wraps a session service with a cache. The cache should be used by this service only, with
+// a short TTL, since other replicas do not invalidate it.
+func NewCachedService(svc SessionService, c *cache.Cache[string, any]) SessionService {
+	return &cachedSessionService{SessionService: svc, cache: c}
+}
+
+// GetSession retrieves a session through the cache
+func (s *cachedSessionService) GetSession(ctx context.Context, id uuid.UUID) (*Session, error) {
+	session, err := cache.Load(s.cache, cache.SessionCacheKey(id.String()), func() (*Session, error) {
+		return s.SessionService.GetSession(ctx, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return copySession(session), nil
+}
+
+// ListActiveSessions lists active sessions through the cache
+func (s *cachedSessionService) ListActiveSessions(ctx context.Context) ([]*Session, error) {
+	sessions, err := cache.Load(s.cache, activeSessionsCacheKey, func() ([]*Session, error) {
+		return s.SessionService.ListActiveSessions(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	copies := make([]*Session, len(sessions))
+	for i, session := range sessions {
+		copies[i] = copySession(session)
+	}
+	return copies, nil
+}
+
+// GetTable retrieves a table through the cache
+func (s *cachedSessionService) GetTable(ctx context.Context, id int) (*Table, error) {
+	table, err := cache.Load(s.cache, cache.TableCacheKey(strconv.Itoa(id)), func() (*Table, error) {
+		return s.SessionService.GetTable(ctx, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	t := *table
+	return &t, nil
+}
+
+// ListTables lists tables through the cache
+func (s *cachedSessionService) ListTables(ctx context.Context, includeDeleted bool) ([]*Table, error) {
+	key := cache.ListCacheKey("table", "deleted="+strconv.FormatBool(includeDeleted))
+	tables, err := cache.Load(s.cache, key, func() ([]*Table, error) {
+		return s.SessionService.ListTables(ctx, includeDeleted)
+	})
+	if err != nil {
+		return nil, err
+	}
+	copies := make([]*Table, len(tables))
+	for i, table := range tables {
+		t := *table
+		copies[i] = &t
+	}
+	return copies, nil
+}
+
+// invalidateSessions removes sessions and every session list
+func (s *cachedSessionService) invalidateSessions(ids ...uuid.UUID) {
+	for _, id := range ids {
+		s.cache.Delete(cache.SessionCacheKey(id.String()))
+	}
+	cache.DeletePrefix(s.cache, cache.ListCachePrefix("session"))
+}
+
+// invalidateTables removes tables and every table list
+func (s *cachedSessionService) invalidateTables(ids ...int) {
+	for _, id := range ids {
+		s.cache.Delete(cache.TableCacheKey(strconv.Itoa(id)))
+	}
+	cache.DeletePrefix(s.cache, cache.ListCachePrefix("table"))
+}
+
+// CreateSession creates a session and invalidates session lists
+func (s *cachedSessionService) CreateSession(ctx context.Context, tableID int, guestCount int) (*Session, error) {
+	defer s.invalidateSessions()
+	return s.SessionService.CreateSession(ctx, tableID, guestCount)
+}
+
+// UpdateSession changes the status of a session and invalidates it
+func (s *cachedSessionService) UpdateSession(ctx context.Context, id uuid.UUID, status SessionStatus, version int) (*Session, error) {
+	defer s.invalidateSessions(id)
+	return s.SessionService.UpdateSession(ctx, id, status, version)
+}
+
+// ChangeTable moves a session to another table and invalidates it
+func (s *cachedSessionService) ChangeTable(ctx context.Context, id uuid.UUID, tableNumber int) error {
+	defer s.invalidateSessions(id)
+	return s.SessionService.ChangeTable(ctx, id, tableNumber)
+}
+
+// DeleteSession deletes a session and invalidates it
+func (s *cachedSessionService) DeleteSession(ctx context.Context, id uuid.UUID) error {
+	defer s.invalidateSessions(id)
+	return s.SessionService.DeleteSession(ctx, id)
+}
+
+// JoinTables joins tables to a session and invalidates it
+func (s *cachedSessionService) JoinTables(ctx context.Context, id uuid.UUID, tableIDs []int) (*Session, error) {
+	defer s.invalidateSessions(id)
+	return s.SessionService.JoinTables(ctx, id, tableIDs)
+}
+
+// ReleaseJoinedTable releases a joined table and invalidates the session
+func (s *cachedSessionService) ReleaseJoinedTable(ctx context.Context, id uuid.UUID, tableID int) (*Session, error) {
+	defer s.invalidateSessions(id)
+	return s.SessionService.ReleaseJoinedTable(ctx, id, tableID)
+}
+
+// SplitSession splits a session and invalidates it; the new sessions are only in lists
+func (s *cachedSessionService) SplitSession(ctx context.Context, id uuid.UUID, parts []SplitPart) ([]*Session, error) {
+	defer s.invalidateSessions(id)
+	return s.SessionService.SplitSession(ctx, id, parts)
+}
+
+// StartSessionWithToken starts a session from a table QR token and invalidates session lists
+func (s *cachedSessionService) StartSessionWithToken(ctx context.Context, token string, tableID int, guestCount int) (*GuestSession, error) {
+	defer s.invalidateSessions()
+	return s.SessionService.StartSessionWithToken(ctx, token, tableID, guestCount)
+}
+
+// JoinSessionWithToken joins or starts the session of a table and invalidates it
+func (s *cachedSessionService) JoinSessionWithToken(ctx context.Context, token string) (*GuestSession, error) {
+	guest, err := s.SessionService.JoinSessionWithToken(ctx, token)
+	if guest != nil && guest.Session != nil {
+		s.invalidateSessions(guest.Session.ID)
+	} else {
+		s.invalidateSessions()
+	}
+	return guest, err
+}
+
+// FlagIdleSession records an idle session warning and invalidates the session
+func (s *cachedSessionService) FlagIdleSession(ctx context.Context, id uuid.UUID) error {
+	defer s.invalidateSessions(id)
+	return s.SessionService.FlagIdleSession(ctx, id)
+}
+
+// CancelIdleSession cancels an idle session and invalidates it
+func (s *cachedSessionService) CancelIdleSession(ctx context.Context, session *IdleSession) (bool, error) {
+	defer s.invalidateSessions(session.ID)
+	return s.SessionService.CancelIdleSession(ctx, session)
+}
+
+// UpdateGuestCount changes the covers of a session and invalidates it
+func (s *cachedSessionService) UpdateGuestCount(ctx context.Context, id uuid.UUID, guestCount int) (*Session, error) {
+	defer s.invalidateSessions(id)
+	return s.SessionService.UpdateGuestCount(ctx, id, guestCount)
+}
+
+// ReassignServer changes the server of a session and invalidates it
+func (s *cachedSessionService) ReassignServer(ctx context.Context, id uuid.UUID, serverID uuid.UUID) (*Session, error) {
+	defer s.invalidateSessions(id)
+	return s.SessionService.ReassignServer(ctx, id, serverID)
+}
+
+// CreateTable creates a table and invalidates table lists
+func (s *cachedSessionService) CreateTable(ctx context.Context, req *CreateTableRequest) (*Table, error) {
+	defer s.invalidateTables()
+	return s.SessionService.CreateTable(ctx, req)
+}
+
+// UpdateTable updates a table and invalidates it
+func (s *cachedSessionService) UpdateTable(ctx context.Context, id int, req *UpdateTableRequest) (*Table, error) {
+	defer s.invalidateTables(id)
+	return s.SessionService.UpdateTable(ctx, id, req)
+}
+
+// DeleteTable deletes a table and invalidates it
+func (s *cachedSessionService) DeleteTable(ctx context.Context, id int) error {
+	defer s.invalidateTables(id)
+	return s.SessionService.DeleteTable(ctx, id)
+}
+
+// RestoreTable restores a table and invalidates it
+func (s *cachedSessionService) RestoreTable(ctx context.Context, id int) (*Table, error) {
+	defer s.invalidateTables(id)
+	return s.SessionService.RestoreTable(ctx, id)
+}
+
+// BulkCreateTables creates a range of tables and invalidates them
+func (s *cachedSessionService) BulkCreateTables(ctx context.Context, start, end int) error {
+	defer s.invalidateTables()
+	return s.SessionService.BulkCreateTables(ctx, start, end)
+}
+
+// RotateTableToken regenerates the QR token of a table and invalidates it
+func (s *cachedSessionService) RotateTableToken(ctx context.Context, tableID int) (*TableQRToken, error) {
+	defer s.invalidateTables(tableID)
+	return s.SessionService.RotateTableToken(ctx, tableID)
+}
+
+// copySession returns a copy of a cached session
+func copySession(session *Session) *Session {
+	c := *session
+	c.JoinedTableIDs = append([]int(nil), session.JoinedTableIDs...)
+	return &c
+}
@@ -4,33 +4,93 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"restaurant/internal/errors"
 	"restaurant/internal/middleware"
+	"restaurant/internal/qrcode"
 
 	"github.com/gin-gonic/gin"
 )
 
+// defaultQRCodeSize is the width in pixels of rendered table QR codes when none is requested
+const defaultQRCodeSize = 512
+
 // Handler handles HTTP requests for sessions
 type Handler struct {
-	svc SessionService
+	svc     SessionService
+	limiter *middleware.RateLimiter
+}
+
+// NewHandler creates a new handler. Its rate limit policies are attached to limiter, which may be nil.
+func NewHandler(svc SessionService, limiter *middleware.RateLimiter) *Handler {
+	return &Handler{svc: svc, limiter: limiter}
 }
 
-// NewHandler creates a new handler
-func NewHandler(svc SessionService) *Handler {
-	return &Handler{svc: svc}
+// sessionPermissions declares what each session and table route needs. Starting a session and scanning a
+// table are public, since the table QR token is the credential; staff starting a session without a token are
+// checked by the handler.
+var sessionPermissions = middleware.RoutePermissions{
+	"POST /sessions":                       middleware.PermPublic,
+	"POST /sessions/scan":                  middleware.PermPublic,
+	"GET /sessions/current":                middleware.PermSessionsCurrent,
+	"GET /sessions":                        middleware.PermSessionsRead,
+	"GET /sessions/active":                 middleware.PermSessionsRead,
+	"GET /sessions/reports/covers":         middleware.PermReportsRead,
+	"GET /sessions/:id":                    middleware.PermSessionsRead,
+	"PUT /sessions/:id":                    middleware.PermSessionsManage,
+	"PUT /sessions/:id/table":              middleware.PermSessionsManage,
+	"PUT /sessions/:id/guests":             middleware.PermSessionsManage,
+	"PUT /sessions/:id/server":             middleware.PermSessionsAssign,
+	"POST /sessions/:id/tables":            middleware.PermSessionsManage,
+	"DELETE /sessions/:id/tables/:tableID": middleware.PermSessionsManage,
+	"POST /sessions/:id/split":             middleware.PermSessionsManage,
+	"GET /sessions/table/:tableID":         middleware.PermSessionsRead,
+	"GET /sessions/table/:tableID/active":  middleware.PermSessionsRead,
+	"DELETE /sessions/:id":                 middleware.PermSessionsDelete,
+	"GET /sessions/tables":                 middleware.PermSessionsRead,
+	"POST /sessions/tables":                middleware.PermTablesManage,
+	"POST /sessions/tables/bulk":           middleware.PermTablesManage,
+	"GET /sessions/tables/available":       middleware.PermSessionsRead,
+	"GET /sessions/tables/floor-plan":      middleware.PermSessionsRead,
+	"GET /sessions/tables/:id":             middleware.PermSessionsRead,
+	"PUT /sessions/tables/:id":             middleware.PermTablesManage,
+	"DELETE /sessions/tables/:id":          middleware.PermTablesManage,
+	"POST /sessions/tables/:id/restore":    middleware.PermTablesManage,
+	"POST /sessions/tables/:id/qr-token":   middleware.PermTablesManage,
+	"GET /sessions/tables/:id/qr.png":      middleware.PermTablesManage,
+}
+
+// sessionRateLimits puts the public guest entry points under a tight limit per IP address
+var sessionRateLimits = middleware.RouteRateLimits{
+	"POST /sessions":      middleware.PolicyGuestEntry,
+	"POST /sessions/scan": middleware.PolicyGuestEntry,
 }
 
 // RegisterRoutes registers all session routes with the Gin router
 func (h *Handler) RegisterRoutes(router *gin.Engine) {
-	sessionGroup := router.Group("/sessions")
+	if h.limiter != nil {
+		h.limiter.Attach(sessionRateLimits)
+	}
+
+	sessionGroup := router.Group("/sessions", sessionPermissions.Authorize())
 	{
-		sessionGroup.GET("", h.ListSessions)
+		// Guest entry points
 		sessionGroup.POST("", h.CreateSession)
+		sessionGroup.POST("/scan", h.ScanTable)
+		sessionGroup.GET("/current", h.GetGuestSession)
+
+		sessionGroup.GET("", h.ListSessions)
 		sessionGroup.GET("/active", h.ListActiveSessions)
+		sessionGroup.GET("/reports/covers", h.GetCoversReport)
 		sessionGroup.GET("/:id", h.GetSession)
 		sessionGroup.PUT("/:id", h.UpdateSession)
 		sessionGroup.PUT("/:id/table", h.ChangeSessionTable)
+		sessionGroup.PUT("/:id/guests", h.UpdateGuestCount)
+		sessionGroup.PUT("/:id/server", h.ReassignServer)
+		sessionGroup.POST("/:id/tables", h.JoinTables)
+		sessionGroup.DELETE("/:id/tables/:tableID", h.ReleaseJoinedTable)
+		sessionGroup.POST("/:id/split", h.SplitSession)
 		sessionGroup.GET("/table/:tableID", h.GetSessionsByTable)
 		sessionGroup.GET("/table/:tableID/active", h.GetActiveSessionsByTable)
 		sessionGroup.DELETE("/:id", h.DeleteSession)
@@ -39,20 +99,29 @@ func (h *Handler) RegisterRoutes(router *gin.Engine) {
 		sessionGroup.GET("/tables", h.ListTables)
 		sessionGroup.POST("/tables", h.CreateTable)
 		sessionGroup.POST("/tables/bulk", h.BulkCreateTables)
+		sessionGroup.GET("/tables/available", h.FindAvailableTables)
+		sessionGroup.GET("/tables/floor-plan", h.GetFloorPlan)
 		sessionGroup.GET("/tables/:id", h.GetTable)
+		sessionGroup.PUT("/tables/:id", h.UpdateTable)
 		sessionGroup.DELETE("/tables/:id", h.DeleteTable)
+		sessionGroup.POST("/tables/:id/restore", h.RestoreTable)
+		sessionGroup.POST("/tables/:id/qr-token", h.RotateTableToken)
+		sessionGroup.GET("/tables/:id/qr.png", h.GetTableQRCode)
 	}
 }
 
 // CreateSession handles POST /sessions
 // @Summary Create a new session
-// @Description Create a new dining session for a table
+// @Description Start a dining session. Guests send the QR token of their table, with an optional table_id that must match it, and get a guest credential as from POST /sessions/scan. Staff who may manage sessions can instead send just a table_id and get the session.
 // @Tags Sessions
 // @Accept json
 // @Produce json
 // @Param request body CreateSessionRequest true "Session creation request"
-// @Success 200 {object} Session
+// @Success 201 {object} GuestSession "A Session when staff start it from a table_id"
 // @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 409 {object} middleware.ErrorResponse
 // @Failure 500 {object} middleware.ErrorResponse
 // @Router /sessions [post]
 func (h *Handler) CreateSession(c *gin.Context) {
@@ -67,13 +136,85 @@ func (h *Handler) CreateSession(c *gin.Context) {
 		return
 	}
 
-	session, err := h.svc.CreateSession(c.Request.Context(), req.TableID)
+	// Without a QR token the table is taken on the caller's word, which only staff may do
+	if req.QRToken == "" {
+		if !middleware.Authorize(c, middleware.PermSessionsManage) {
+			return
+		}
+		session, err := h.svc.CreateSession(c.Request.Context(), req.TableID, req.GuestCount)
+		if err != nil {
+			middleware.HandleError(c, err)
+			return
+		}
+		c.JSON(201, session)
+		return
+	}
+
+	guest, err := h.svc.StartSessionWithToken(c.Request.Context(), req.QRToken, req.TableID, req.GuestCount)
 	if err != nil {
 		middleware.HandleError(c, err)
 		return
 	}
 
-	c.JSON(201, session)
+	c.JSON(201, guest)
+}
+
+// ScanTable handles POST /sessions/scan
+// @Summary Join a table by QR code
+// @Description Exchange a scanned table QR token for a guest credential, joining the session running at the table or starting one
+// @Tags Sessions
+// @Accept json
+// @Produce json
+// @Param request body ScanTableRequest true "Scanned QR token"
+// @Success 200 {object} GuestSession
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 409 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /sessions/scan [post]
+func (h *Handler) ScanTable(c *gin.Context) {
+	var req ScanTableRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	if err := ValidateScanTable(req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	guest, err := h.svc.JoinSessionWithToken(c.Request.Context(), req.Token)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, guest)
+}
+
+// GetGuestSession handles GET /sessions/current
+// @Summary Get the guest's session
+// @Description Retrieve the live session a guest credential was issued for
+// @Tags Sessions
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer guest credential from POST /sessions/scan"
+// @Success 200 {object} Session
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /sessions/current [get]
+func (h *Handler) GetGuestSession(c *gin.Context) {
+	principal, _ := middleware.GetPrincipal(c) // a guest; only guests are granted PermSessionsCurrent
+
+	session, err := h.svc.GetGuestSession(c.Request.Context(), principal.SessionID)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
 }
 
 // GetSession handles GET /sessions/:id
@@ -104,19 +245,25 @@ func (h *Handler) GetSession(c *gin.Context) {
 		return
 	}
 
+	middleware.SetVersionETag(c, session.Version, "")
 	c.JSON(200, session)
 }
 
 // UpdateSession handles PUT /sessions/:id/status
 // @Summary Update session status
-// @Description Update the status of a session
+// @Description Update the status of a session. The version read is sent as If-Match, or as version in the body; if the session has changed since, nothing is updated.
 // @Tags Sessions
 // @Accept json
 // @Produce json
 // @Param id path string true "Session ID (UUID)"
+// @Param If-Match header string false "ETag of the version being updated, from the last read"
 // @Param request body UpdateSessionRequest true "Status update request"
 // @Success 200 {object} Session
 // @Failure 400 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 409 {object} middleware.ErrorResponse "Body version is out of date"
+// @Failure 412 {object} middleware.ErrorResponse "If-Match is out of date"
+// @Failure 428 {object} middleware.ErrorResponse "Neither If-Match nor a body version was sent"
 // @Failure 500 {object} middleware.ErrorResponse
 // @Router /sessions/{id} [put]
 func (h *Handler) UpdateSession(c *gin.Context) {
@@ -126,27 +273,33 @@ func (h *Handler) UpdateSession(c *gin.Context) {
 	}
 
 	if err := ValidateSessionID(id); err != nil {
-		c.JSON(400, gin.H{"error": err.Error()})
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
 		return
 	}
 
 	var req UpdateSessionRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(400, gin.H{"error": err.Error()})
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
 		return
 	}
 
 	if err := ValidateUpdateSession(req); err != nil {
-		c.JSON(400, gin.H{"error": err.Error()})
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
 		return
 	}
 
-	updatedSession, err := h.svc.UpdateSession(c.Request.Context(), id, req.Status)
+	precondition, ok := middleware.RequireVersion(c, req.Version)
+	if !ok {
+		return
+	}
+
+	updatedSession, err := h.svc.UpdateSession(c.Request.Context(), id, req.Status, precondition.Version)
 	if err != nil {
-		c.JSON(500, gin.H{"error": err.Error()})
+		precondition.HandleError(c, err)
 		return
 	}
 
+	middleware.SetVersionETag(c, updatedSession.Version, "")
 	c.JSON(200, updatedSession)
 }
 
@@ -246,6 +399,257 @@ func (h *Handler) ChangeSessionTable(c *gin.Context) {
 	c.JSON(200, gin.H{"message": "Session table changed successfully"})
 }
 
+// UpdateGuestCount handles PUT /sessions/:id/guests
+// @Summary Update guest count
+// @Description Change the number of guests of a live session; it may not exceed the capacity of the session's tables
+// @Tags Sessions
+// @Accept json
+// @Produce json
+// @Param id path string true "Session ID (UUID)"
+// @Param request body UpdateGuestCountRequest true "Guest count"
+// @Success 200 {object} Session
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 409 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /sessions/{id}/guests [put]
+func (h *Handler) UpdateGuestCount(c *gin.Context) {
+	id, ok := middleware.UUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req UpdateGuestCountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	if err := ValidateUpdateGuestCount(req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	session, err := h.svc.UpdateGuestCount(c.Request.Context(), id, req.GuestCount)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
+
+// ReassignServer handles PUT /sessions/:id/server
+// @Summary Reassign server
+// @Description Hand a live session to another server; orders not yet served or cancelled move with it
+// @Tags Sessions
+// @Accept json
+// @Produce json
+// @Param id path string true "Session ID (UUID)"
+// @Param request body ReassignServerRequest true "New server"
+// @Success 200 {object} Session
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 409 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /sessions/{id}/server [put]
+func (h *Handler) ReassignServer(c *gin.Context) {
+	id, ok := middleware.UUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req ReassignServerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	if err := ValidateReassignServer(req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	session, err := h.svc.ReassignServer(c.Request.Context(), id, req.ServerID)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
+
+// GetCoversReport handles GET /sessions/reports/covers
+// @Summary Covers report
+// @Description Covers, average party size and spend per head for sessions completed over a range of days
+// @Tags Sessions
+// @Accept json
+// @Produce json
+// @Param from query string false "First day as YYYY-MM-DD (default today)"
+// @Param to query string false "Last day as YYYY-MM-DD, inclusive (default from)"
+// @Success 200 {object} CoversReport
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /sessions/reports/covers [get]
+func (h *Handler) GetCoversReport(c *gin.Context) {
+	var req CoversReportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	if err := ValidateCoversReport(req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	// Formats already validated; days are read in the server's time zone
+	now := time.Now()
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	if req.From != "" {
+		from, _ = time.ParseInLocation("2006-01-02", req.From, time.Local)
+	}
+	to := from
+	if req.To != "" {
+		to, _ = time.ParseInLocation("2006-01-02", req.To, time.Local)
+		if req.From == "" {
+			from = to
+		}
+	}
+
+	report, err := h.svc.GetCoversReport(c.Request.Context(), from, to.AddDate(0, 0, 1))
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// JoinTables handles POST /sessions/:id/tables
+// @Summary Join tables to a session
+// @Description Push extra tables together with the session's table for a large party. Joined tables are occupied until the session completes.
+// @Tags Sessions
+// @Accept json
+// @Produce json
+// @Param id path string true "Session ID (UUID)"
+// @Param request body JoinTablesRequest true "Tables to join"
+// @Success 200 {object} Session
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 409 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /sessions/{id}/tables [post]
+func (h *Handler) JoinTables(c *gin.Context) {
+	id, ok := middleware.UUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req JoinTablesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	if err := ValidateJoinTables(req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	session, err := h.svc.JoinTables(c.Request.Context(), id, req.TableIDs)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
+
+// ReleaseJoinedTable handles DELETE /sessions/:id/tables/:tableID
+// @Summary Release a joined table
+// @Description Free one joined table while the rest of the party stays seated
+// @Tags Sessions
+// @Accept json
+// @Produce json
+// @Param id path string true "Session ID (UUID)"
+// @Param tableID path int true "Joined table ID"
+// @Success 200 {object} Session
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 409 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /sessions/{id}/tables/{tableID} [delete]
+func (h *Handler) ReleaseJoinedTable(c *gin.Context) {
+	id, ok := middleware.UUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	tableID, err := strconv.Atoi(c.Param("tableID"))
+	if err != nil {
+		middleware.HandleError(c, errors.NewValidationError("invalid table ID"))
+		return
+	}
+
+	if err := ValidateTableID(tableID); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	session, err := h.svc.ReleaseJoinedTable(c.Request.Context(), id, tableID)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
+
+// SplitSession handles POST /sessions/:id/split
+// @Summary Split a combined session
+// @Description Move joined tables into sessions of their own, taking the listed orders with them. The original session is returned first.
+// @Tags Sessions
+// @Accept json
+// @Produce json
+// @Param id path string true "Session ID (UUID)"
+// @Param request body SplitSessionRequest true "Split request"
+// @Success 200 {array} Session
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 409 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /sessions/{id}/split [post]
+func (h *Handler) SplitSession(c *gin.Context) {
+	id, ok := middleware.UUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req SplitSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	if err := ValidateSplitSession(req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	parts := make([]SplitPart, len(req.Parts))
+	for i, part := range req.Parts {
+		parts[i] = SplitPart{TableID: part.TableID, OrderIDs: part.OrderIDs, GuestCount: part.GuestCount}
+	}
+
+	sessions, err := h.svc.SplitSession(c.Request.Context(), id, parts)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sessions)
+}
+
 // GetSessionsByTable handles GET /sessions/table/:tableID
 // @Summary Get sessions for a table
 // @Description Retrieve all sessions for a specific table
@@ -347,7 +751,7 @@ func (h *Handler) CreateTable(c *gin.Context) {
 		return
 	}
 
-	table, err := h.svc.CreateTable(c.Request.Context(), &CreateTableRequest{ID: req.ID})
+	table, err := h.svc.CreateTable(c.Request.Context(), &req)
 	if err != nil {
 		middleware.HandleError(c, err)
 		return
@@ -365,6 +769,7 @@ func (h *Handler) CreateTable(c *gin.Context) {
 // @Param request body BulkCreateTablesRequest true "Bulk create tables request"
 // @Success 201 {object} map[string]string
 // @Failure 400 {object} middleware.ErrorResponse
+// @Failure 409 {object} middleware.ErrorResponse
 // @Failure 500 {object} middleware.ErrorResponse
 // @Router /sessions/tables/bulk [post]
 func (h *Handler) BulkCreateTables(c *gin.Context) {
@@ -427,11 +832,20 @@ func (h *Handler) GetTable(c *gin.Context) {
 // @Tags Tables
 // @Accept json
 // @Produce json
+// @Param include_deleted query bool false "Include soft-deleted tables"
 // @Success 200 {array} Table
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 401 {object} middleware.ErrorResponse
+// @Failure 403 {object} middleware.ErrorResponse
 // @Failure 500 {object} middleware.ErrorResponse
 // @Router /sessions/tables [get]
 func (h *Handler) ListTables(c *gin.Context) {
-	tables, err := h.svc.ListTables(c.Request.Context())
+	includeDeleted, ok := middleware.IncludeDeleted(c, middleware.PermTablesManage)
+	if !ok {
+		return
+	}
+
+	tables, err := h.svc.ListTables(c.Request.Context(), includeDeleted)
 	if err != nil {
 		middleware.HandleError(c, err)
 		return
@@ -442,7 +856,7 @@ func (h *Handler) ListTables(c *gin.Context) {
 
 // DeleteTable handles DELETE /sessions/tables/:id
 // @Summary Delete a table
-// @Description Delete a restaurant table (only if no active sessions)
+// @Description Soft delete a restaurant table (only if no active sessions)
 // @Tags Tables
 // @Accept json
 // @Produce json
@@ -474,3 +888,221 @@ func (h *Handler) DeleteTable(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Table deleted successfully"})
 }
+
+// RestoreTable handles POST /sessions/tables/:id/restore
+// @Summary Restore a table
+// @Description Restore a soft-deleted restaurant table
+// @Tags Tables
+// @Accept json
+// @Produce json
+// @Param id path int true "Table ID"
+// @Success 200 {object} Table
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /sessions/tables/{id}/restore [post]
+func (h *Handler) RestoreTable(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		middleware.HandleError(c, errors.NewValidationError("invalid table ID"))
+		return
+	}
+
+	if err := ValidateTableID(id); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	table, err := h.svc.RestoreTable(c.Request.Context(), id)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, table)
+}
+
+// RotateTableToken handles POST /sessions/tables/:id/qr-token
+// @Summary Regenerate a table QR token
+// @Description Issue a new QR token for a table; codes printed with the previous token stop working
+// @Tags Tables
+// @Accept json
+// @Produce json
+// @Param id path int true "Table ID"
+// @Success 200 {object} TableQRToken
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /sessions/tables/{id}/qr-token [post]
+func (h *Handler) RotateTableToken(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.HandleError(c, errors.NewValidationError("invalid table ID"))
+		return
+	}
+
+	if err := ValidateTableID(id); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	token, err := h.svc.RotateTableToken(c.Request.Context(), id)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, token)
+}
+
+// GetTableQRCode handles GET /sessions/tables/:id/qr.png
+// @Summary Render a table QR code
+// @Description Render the table's current QR token link as a PNG for printing
+// @Tags Tables
+// @Produce png
+// @Param id path int true "Table ID"
+// @Param size query int false "Image width in pixels (default 512)"
+// @Success 200 {file} binary
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /sessions/tables/{id}/qr.png [get]
+func (h *Handler) GetTableQRCode(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.HandleError(c, errors.NewValidationError("invalid table ID"))
+		return
+	}
+
+	if err := ValidateTableID(id); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	var req TableQRCodeRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	if err := ValidateTableQRCode(req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+	if req.Size == 0 {
+		req.Size = defaultQRCodeSize
+	}
+
+	token, err := h.svc.GetTableToken(c.Request.Context(), id)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	image, err := qrcode.PNG(token.URL, req.Size)
+	if err != nil {
+		middleware.HandleError(c, errors.NewInternalError("failed to render QR code", err))
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "image/png", image)
+}
+
+// UpdateTable handles PUT /sessions/tables/:id
+// @Summary Update a table
+// @Description Change the capacity, zone, shape or floor plan position of a table; omitted fields are kept
+// @Tags Tables
+// @Accept json
+// @Produce json
+// @Param id path int true "Table ID"
+// @Param request body UpdateTableRequest true "Table update request"
+// @Success 200 {object} Table
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /sessions/tables/{id} [put]
+func (h *Handler) UpdateTable(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		middleware.HandleError(c, errors.NewValidationError("invalid table ID"))
+		return
+	}
+
+	if err := ValidateTableID(id); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	var req UpdateTableRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	if err := ValidateUpdateTable(req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	table, err := h.svc.UpdateTable(c.Request.Context(), id, &req)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, table)
+}
+
+// FindAvailableTables handles GET /sessions/tables/available
+// @Summary Find available tables
+// @Description List free tables that seat a party, optionally in one zone, smallest fitting tables first
+// @Tags Tables
+// @Accept json
+// @Produce json
+// @Param party_size query int false "Number of guests"
+// @Param zone query string false "Zone, e.g. patio"
+// @Success 200 {array} Table
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /sessions/tables/available [get]
+func (h *Handler) FindAvailableTables(c *gin.Context) {
+	var req AvailableTablesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	if err := ValidateAvailableTables(req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	tables, err := h.svc.FindAvailableTables(c.Request.Context(), req.PartySize, req.Zone)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tables)
+}
+
+// GetFloorPlan handles GET /sessions/tables/floor-plan
+// @Summary Get the floor plan
+// @Description Live tables grouped by zone with their layout and whether they are occupied
+// @Tags Tables
+// @Accept json
+// @Produce json
+// @Success 200 {array} FloorPlanZone
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /sessions/tables/floor-plan [get]
+func (h *Handler) GetFloorPlan(c *gin.Context) {
+	zones, err := h.svc.GetFloorPlan(c.Request.Context())
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, zones)
+}
@@ -23,6 +23,39 @@ type Session struct {
 	CreatedAt   time.Time     `json:"created_at"`   // when the session was created
 	CompletedAt *time.Time    `json:"completed_at"` // when the session was completed, nil if not completed
 	Status      SessionStatus `json:"status"`       // e.g., StatusActive, StatusCompleted, or StatusPending
+	GuestCount  int           `json:"guest_count"`  // covers seated for the session, 0 if not recorded
+	ServerID    *uuid.UUID    `json:"server_id"`    // server looking after the table, nil if no one was on shift for its section
+	Version     int           `json:"version"`      // bumped by every write, for optimistic concurrency
+
+	JoinedTableIDs []int `json:"joined_table_ids,omitempty"` // extra tables pushed together with TableID for a large party
+}
+
+// SplitPart moves one joined table out of a session into a session of its own, taking the listed orders with it
+type SplitPart struct {
+	TableID    int         `json:"table_id"`
+	OrderIDs   []uuid.UUID `json:"order_ids"`
+	GuestCount int         `json:"guest_count"` // guests moving with the table, 0 if not recorded
+}
+
+// IdleSession is a live session with no recent activity, as seen by the idle session sweeper
+type IdleSession struct {
+	Session
+	LastActivity time.Time  // latest of the session start and its newest order
+	FlaggedAt    *time.Time // when the sweeper last warned about the session, nil if never
+	ServedOrders int        // served orders; payment is settled by completing the session, so these are unpaid
+}
+
+// CoversReport totals covers and spend for completed sessions that started in [From, To)
+type CoversReport struct {
+	From             time.Time `json:"from"`
+	To               time.Time `json:"to"`
+	Sessions         int       `json:"sessions"`           // completed sessions
+	CountedSessions  int       `json:"counted_sessions"`   // completed sessions with a recorded guest count
+	Covers           int       `json:"covers"`             // guests across counted sessions
+	Revenue          float64   `json:"revenue"`            // served order value across all sessions
+	CountedRevenue   float64   `json:"-"`                  // served order value across counted sessions
+	AveragePartySize float64   `json:"average_party_size"` // covers per counted session
+	SpendPerHead     float64   `json:"spend_per_head"`     // counted revenue per cover
 }
 
 type Bill struct {
@@ -34,7 +67,57 @@ type Bill struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
+// TableShape is the outline a table is drawn with on the floor plan
+type TableShape string
+
+const (
+	ShapeSquare    TableShape = "square"
+	ShapeRound     TableShape = "round"
+	ShapeRectangle TableShape = "rectangle"
+	ShapeBooth     TableShape = "booth"
+)
+
 // Table represents a physical table in the restaurant
 type Table struct {
-	ID int `json:"id" db:"id"` // table number (primary key)
+	ID        int        `json:"id" db:"id"`                           // table number (primary key)
+	MinCovers int        `json:"min_covers" db:"min_covers"`           // smallest party the table is offered to
+	MaxCovers int        `json:"max_covers" db:"max_covers"`           // largest party the table seats
+	Zone      string     `json:"zone" db:"zone"`                       // section of the restaurant, e.g. "patio", "bar", "main"
+	Shape     TableShape `json:"shape" db:"shape"`                     // outline drawn on the floor plan
+	X         float64    `json:"x" db:"pos_x"`                         // floor plan x coordinate of the table centre
+	Y         float64    `json:"y" db:"pos_y"`                         // floor plan y coordinate of the table centre
+	Rotation  int        `json:"rotation" db:"rotation"`               // floor plan rotation in degrees
+	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"` // when the table was soft deleted, nil if live
+}
+
+// Seats reports whether a party of the given size fits the table's capacity
+func (t *Table) Seats(partySize int) bool {
+	return partySize >= t.MinCovers && partySize <= t.MaxCovers
+}
+
+// FloorPlanTable is a table on the floor plan together with whether it is currently occupied
+type FloorPlanTable struct {
+	Table
+	Occupied bool `json:"occupied"` // true while the table has an active or pending session
+}
+
+// FloorPlanZone groups the tables of one section of the floor plan
+type FloorPlanZone struct {
+	Zone   string           `json:"zone"`
+	Tables []FloorPlanTable `json:"tables"`
+}
+
+// TableQRToken is the current QR token of a table and the link encoded in its QR code
+type TableQRToken struct {
+	TableID   int       `json:"table_id"`
+	Token     string    `json:"token"`
+	URL       string    `json:"url"`        // guest app link with the token appended, or the bare token if no link is configured
+	RotatedAt time.Time `json:"rotated_at"` // when the token was last regenerated; codes printed before this no longer work
+}
+
+// GuestSession is the session a guest joined by scanning a table's QR code, with the credential that proves it
+type GuestSession struct {
+	Session    *Session  `json:"session"`
+	Credential string    `json:"credential"` // guest token; send as a Bearer token or in the X-Session-Token header
+	ExpiresAt  time.Time `json:"expires_at"`
 }
@@ -5,22 +5,26 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
+	apperrors "restaurant/internal/errors"
+
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 // Repository defines methods for session database operations
 type Repository interface {
 	// CreateSession creates a new session with the given ID and table ID
-	CreateSession(ctx context.Context, id uuid.UUID, tableID int) (*Session, error)
+	CreateSession(ctx context.Context, id uuid.UUID, tableID int, guestCount int) (*Session, error)
 
 	// GetSession retrieves a session by ID
 	GetSession(ctx context.Context, id uuid.UUID) (*Session, error)
 
-	// UpdateSession updates the status of a session
-	UpdateSession(ctx context.Context, id uuid.UUID, newStatus SessionStatus) error
+	// UpdateSession updates the status of a session that is still at version
+	UpdateSession(ctx context.Context, id uuid.UUID, newStatus SessionStatus, version int) error
 
 	// ListSessions lists sessions with pagination (offset and limit)
 	ListSessions(ctx context.Context, offset int, limit int) ([]*Session, error)
@@ -41,14 +45,61 @@ type Repository interface {
 	DeleteSession(ctx context.Context, id uuid.UUID) error
 
 	// Table operations
-	CreateTable(ctx context.Context, table *CreateTableRequest) (*Table, error)
+	CreateTable(ctx context.Context, table *Table) (*Table, error)
+	UpdateTable(ctx context.Context, table *Table) error
 	GetTable(ctx context.Context, id int) (*Table, error)
 	GetTableByNumber(ctx context.Context, number int) (*Table, error)
-	ListTables(ctx context.Context) ([]*Table, error)
+	ListTables(ctx context.Context, includeDeleted bool) ([]*Table, error)
 	DeleteTable(ctx context.Context, id int) error
+	RestoreTable(ctx context.Context, id int) error
 	BulkCreateTables(ctx context.Context, tableIDs []int) error
 	TableExists(ctx context.Context, number int) (bool, error)
 	IsTableAvailable(ctx context.Context, tableID int) (bool, error)
+	FindAvailableTables(ctx context.Context, partySize int, zone string) ([]*Table, error)
+	GetFloorPlan(ctx context.Context) ([]FloorPlanTable, error)
+
+	// Table groups
+	JoinTables(ctx context.Context, sessionID uuid.UUID, tableIDs []int) error
+	ReleaseJoinedTable(ctx context.Context, sessionID uuid.UUID, tableID int) error
+	GetJoinedTableIDs(ctx context.Context, sessionID uuid.UUID) ([]int, error)
+	SplitSession(ctx context.Context, sessionID uuid.UUID, parts []SplitPart) ([]*Session, error)
+
+	// QR table tokens
+	GetTableTokenNonce(ctx context.Context, tableID int) (string, time.Time, error)
+	RotateTableToken(ctx context.Context, tableID int, nonce string, at time.Time) error
+	FindLiveSessionByTable(ctx context.Context, tableID int) (*uuid.UUID, error)
+
+	// Guest counts
+	UpdateGuestCount(ctx context.Context, id uuid.UUID, guestCount int) error
+	GetCoversReport(ctx context.Context, from time.Time, to time.Time) (*CoversReport, error)
+
+	// Server assignment
+	ReassignServer(ctx context.Context, id uuid.UUID, serverID uuid.UUID) error
+	ListServerSessions(ctx context.Context, serverID uuid.UUID) ([]*Session, error)
+
+	// Idle sessions
+	ListIdleSessions(ctx context.Context, status SessionStatus, idleBefore time.Time) ([]*IdleSession, error)
+	FlagIdleSession(ctx context.Context, id uuid.UUID, at time.Time) error
+	CancelIdleSession(ctx context.Context, id uuid.UUID, status SessionStatus, lastActivity time.Time) (bool, error)
+}
+
+// tableColumns is the column list scanned by scanTable
+const tableColumns = "id, min_covers, max_covers, zone, shape, pos_x, pos_y, rotation, deleted_at"
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanTable scans tableColumns, followed by any extra destinations, into a Table
+func scanTable(row rowScanner, extra ...interface{}) (*Table, error) {
+	var table Table
+	var shape string
+	dest := append([]interface{}{&table.ID, &table.MinCovers, &table.MaxCovers, &table.Zone, &shape, &table.X, &table.Y, &table.Rotation, &table.DeletedAt}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	table.Shape = TableShape(shape)
+	return &table, nil
 }
 
 // postgresRepository implements Repository using PostgreSQL
@@ -61,31 +112,46 @@ func NewPostgresRepository(db *sql.DB) Repository {
 	return &postgresRepository{db: db}
 }
 
-// UpdateSession updates the status of a session in the database
+// UpdateSession updates the status of a session in the database if the session is still at version
 func (r *postgresRepository) UpdateSession(
 	ctx context.Context,
 	id uuid.UUID,
 	newStatus SessionStatus,
+	version int,
 ) error {
+	var completedAt *time.Time
 	if newStatus == StatusCompleted {
 		now := time.Now()
-		_, err := r.db.ExecContext(ctx,
-			"UPDATE sessions SET status = $1, completed_at = $2 WHERE id = $3",
-			newStatus, now, id,
-		)
-		return err
+		completedAt = &now
 	}
 
-	_, err := r.db.ExecContext(ctx,
-		"UPDATE sessions SET status = $1, completed_at = NULL WHERE id = $2",
-		newStatus, id,
+	result, err := r.db.ExecContext(ctx,
+		"UPDATE sessions SET version = version + 1, status = $1, completed_at = $2 WHERE id = $3 AND version = $4",
+		newStatus, completedAt, id, version,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		var exists bool
+		if err := r.db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM sessions WHERE id = $1)", id).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			return apperrors.ErrSessionNotFound
+		}
+		return apperrors.ErrVersionConflict
+	}
+	return nil
 }
 
 // ListSessions retrieves a paginated list of sessions from the database
 func (r *postgresRepository) ListSessions(ctx context.Context, offset int, limit int) ([]*Session, error) {
-	rows, err := r.db.QueryContext(ctx, "SELECT id, table_id, created_at, completed_at, status FROM sessions ORDER BY created_at DESC OFFSET $1 LIMIT $2", offset, limit)
+	rows, err := r.db.QueryContext(ctx, "SELECT id, table_id, created_at, completed_at, status, COALESCE(guest_count, 0), server_id, version FROM sessions ORDER BY created_at DESC OFFSET $1 LIMIT $2", offset, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -96,7 +162,7 @@ func (r *postgresRepository) ListSessions(ctx context.Context, offset int, limit
 		var session Session
 		var status string
 		var completedAt *time.Time
-		err := rows.Scan(&session.ID, &session.TableID, &session.CreatedAt, &completedAt, &status)
+		err := rows.Scan(&session.ID, &session.TableID, &session.CreatedAt, &completedAt, &status, &session.GuestCount, &session.ServerID, &session.Version)
 		if err != nil {
 			return nil, err
 		}
@@ -112,7 +178,7 @@ func (r *postgresRepository) ListSessions(ctx context.Context, offset int, limit
 
 // ListActiveSessions retrieves all sessions with status "active"
 func (r *postgresRepository) ListActiveSessions(ctx context.Context) ([]*Session, error) {
-	rows, err := r.db.QueryContext(ctx, "SELECT id, table_id, created_at, completed_at, status FROM sessions WHERE status = $1", StatusActive)
+	rows, err := r.db.QueryContext(ctx, "SELECT id, table_id, created_at, completed_at, status, COALESCE(guest_count, 0), server_id, version FROM sessions WHERE status = $1", StatusActive)
 	if err != nil {
 		return nil, err
 	}
@@ -123,7 +189,7 @@ func (r *postgresRepository) ListActiveSessions(ctx context.Context) ([]*Session
 		var session Session
 		var status string
 		var completedAt *time.Time
-		err := rows.Scan(&session.ID, &session.TableID, &session.CreatedAt, &completedAt, &status)
+		err := rows.Scan(&session.ID, &session.TableID, &session.CreatedAt, &completedAt, &status, &session.GuestCount, &session.ServerID, &session.Version)
 		if err != nil {
 			return nil, err
 		}
@@ -137,17 +203,89 @@ func (r *postgresRepository) ListActiveSessions(ctx context.Context) ([]*Session
 	return sessions, nil
 }
 
-// ChangeSessionTable changes the table ID of a session by table number
+// ChangeSessionTable moves a session to another table, which is locked and must be available
 func (r *postgresRepository) ChangeSessionTable(ctx context.Context, id uuid.UUID, tableNumber int) error {
-	_, err := r.db.ExecContext(ctx, "UPDATE sessions SET table_id = $1 WHERE id = $2", tableNumber, id)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := lockAvailableTable(ctx, tx, tableNumber); err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, "UPDATE sessions SET version = version + 1, table_id = $1 WHERE id = $2", tableNumber, id)
+	if err != nil {
+		return liveTableViolation(err)
+	}
+	return tx.Commit()
+}
+
+// lockAvailableTable locks a table row and checks that it is live and not held by an active or pending session.
+// Writers that seat a session on a table take this lock first, so two of them cannot both see it free.
+func lockAvailableTable(ctx context.Context, tx *sql.Tx, tableID int) error {
+	var id int
+	err := tx.QueryRowContext(ctx, "SELECT id FROM tables WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", tableID).Scan(&id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return apperrors.ErrTableNotAvailable
+		}
+		return fmt.Errorf("failed to lock table: %w", err)
+	}
+
+	var occupied bool
+	err = tx.QueryRowContext(ctx, `SELECT EXISTS (
+		SELECT 1 FROM session_table_assignments WHERE table_id = $1 AND status IN ('active', 'pending')
+	)`, tableID).Scan(&occupied)
+	if err != nil {
+		return fmt.Errorf("failed to check table availability: %w", err)
+	}
+	if occupied {
+		return apperrors.ErrTableNotAvailable
+	}
+	return nil
+}
+
+// liveTableViolation maps a violation of the one-live-session-per-table index to ErrTableNotAvailable
+func liveTableViolation(err error) error {
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" && pqErr.Constraint == "idx_sessions_live_table" {
+		return apperrors.ErrTableNotAvailable
+	}
 	return err
 }
 
-// CreateSession inserts a new session into the database
-func (r *postgresRepository) CreateSession(ctx context.Context, id uuid.UUID, tableID int) (*Session, error) {
+// assignServerQuery picks the server for a new session on table $2: an active server on shift right now for
+// the table's zone, preferring the one looking after the fewest live sessions. It yields NULL if nobody
+// covers the section.
+const assignServerQuery = `SELECT sh.staff_id
+	FROM staff_shifts sh
+	JOIN staff st ON st.id = sh.staff_id
+	JOIN tables t ON t.zone = sh.zone
+	WHERE t.id = $2 AND st.active AND st.role = 'server' AND sh.starts_at <= NOW() AND sh.ends_at > NOW()
+	ORDER BY (SELECT COUNT(*) FROM sessions s WHERE s.server_id = sh.staff_id AND s.status IN ('active', 'pending')), sh.starts_at, sh.staff_id
+	LIMIT 1`
+
+// CreateSession inserts a new session into the database, assigning the server covering the table's section.
+// The table is locked and checked in the same transaction, so concurrent requests seat at most one session.
+func (r *postgresRepository) CreateSession(ctx context.Context, id uuid.UUID, tableID int, guestCount int) (*Session, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := lockAvailableTable(ctx, tx, tableID); err != nil {
+		return nil, err
+	}
+
 	now := time.Now()
-	_, err := r.db.ExecContext(ctx, "INSERT INTO sessions (id, table_id, created_at, completed_at, status) VALUES ($1, $2, $3, $4, $5)", id, tableID, now, nil, StatusActive)
+	var serverID *uuid.UUID
+	err = tx.QueryRowContext(ctx, "INSERT INTO sessions (id, table_id, created_at, completed_at, status, guest_count, server_id) VALUES ($1, $2, $3, $4, $5, NULLIF($6, 0), ("+assignServerQuery+")) RETURNING server_id",
+		id, tableID, now, nil, StatusActive, guestCount).Scan(&serverID)
 	if err != nil {
+		return nil, liveTableViolation(err)
+	}
+	if err := tx.Commit(); err != nil {
 		return nil, err
 	}
 	// Return the created session
@@ -157,6 +295,9 @@ func (r *postgresRepository) CreateSession(ctx context.Context, id uuid.UUID, ta
 		CreatedAt:   now,
 		CompletedAt: nil,
 		Status:      StatusActive,
+		GuestCount:  guestCount,
+		ServerID:    serverID,
+		Version:     1,
 	}, nil
 }
 
@@ -165,8 +306,8 @@ func (r *postgresRepository) GetSession(ctx context.Context, id uuid.UUID) (*Ses
 	var session Session
 	var status string
 	var completedAt *time.Time
-	err := r.db.QueryRowContext(ctx, "SELECT id, table_id, created_at, completed_at, status FROM sessions WHERE id = $1", id).Scan(
-		&session.ID, &session.TableID, &session.CreatedAt, &completedAt, &status)
+	err := r.db.QueryRowContext(ctx, "SELECT id, table_id, created_at, completed_at, status, COALESCE(guest_count, 0), server_id, version FROM sessions WHERE id = $1", id).Scan(
+		&session.ID, &session.TableID, &session.CreatedAt, &completedAt, &status, &session.GuestCount, &session.ServerID, &session.Version)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, errors.New("Session not found") // or return an error like errors.New("session not found")
@@ -180,7 +321,7 @@ func (r *postgresRepository) GetSession(ctx context.Context, id uuid.UUID) (*Ses
 
 // GetSessionsByTable retrieves all sessions for a specific table
 func (r *postgresRepository) GetSessionsByTable(ctx context.Context, tableID int) ([]*Session, error) {
-	rows, err := r.db.QueryContext(ctx, "SELECT id, table_id, created_at, completed_at, status FROM sessions WHERE table_id = $1 ORDER BY created_at DESC", tableID)
+	rows, err := r.db.QueryContext(ctx, "SELECT id, table_id, created_at, completed_at, status, COALESCE(guest_count, 0), server_id, version FROM sessions WHERE table_id = $1 ORDER BY created_at DESC", tableID)
 	if err != nil {
 		return nil, err
 	}
@@ -191,7 +332,7 @@ func (r *postgresRepository) GetSessionsByTable(ctx context.Context, tableID int
 		var session Session
 		var status string
 		var completedAt *time.Time
-		err := rows.Scan(&session.ID, &session.TableID, &session.CreatedAt, &completedAt, &status)
+		err := rows.Scan(&session.ID, &session.TableID, &session.CreatedAt, &completedAt, &status, &session.GuestCount, &session.ServerID, &session.Version)
 		if err != nil {
 			return nil, err
 		}
@@ -207,7 +348,7 @@ func (r *postgresRepository) GetSessionsByTable(ctx context.Context, tableID int
 
 // GetActiveSessionsByTable retrieves only active sessions for a specific table
 func (r *postgresRepository) GetActiveSessionsByTable(ctx context.Context, tableID int) ([]*Session, error) {
-	rows, err := r.db.QueryContext(ctx, "SELECT id, table_id, created_at, completed_at, status FROM sessions WHERE table_id = $1 AND status = $2 ORDER BY created_at DESC", tableID, StatusActive)
+	rows, err := r.db.QueryContext(ctx, "SELECT id, table_id, created_at, completed_at, status, COALESCE(guest_count, 0), server_id, version FROM sessions WHERE table_id = $1 AND status = $2 ORDER BY created_at DESC", tableID, StatusActive)
 	if err != nil {
 		return nil, err
 	}
@@ -218,7 +359,7 @@ func (r *postgresRepository) GetActiveSessionsByTable(ctx context.Context, table
 		var session Session
 		var status string
 		var completedAt *time.Time
-		err := rows.Scan(&session.ID, &session.TableID, &session.CreatedAt, &completedAt, &status)
+		err := rows.Scan(&session.ID, &session.TableID, &session.CreatedAt, &completedAt, &status, &session.GuestCount, &session.ServerID, &session.Version)
 		if err != nil {
 			return nil, err
 		}
@@ -239,40 +380,67 @@ func (r *postgresRepository) DeleteSession(ctx context.Context, id uuid.UUID) er
 }
 
 // CreateTable creates a new table in the database
-func (r *postgresRepository) CreateTable(ctx context.Context, req *CreateTableRequest) (*Table, error) {
-	// Check if table number already exists
-	exists, err := r.TableExists(ctx, req.ID)
-	if err != nil {
+func (r *postgresRepository) CreateTable(ctx context.Context, table *Table) (*Table, error) {
+	// A deleted table keeps its number, so it has to be restored rather than created again
+	var deletedAt sql.NullTime
+	err := r.db.QueryRowContext(ctx, "SELECT deleted_at FROM tables WHERE id = $1", table.ID).Scan(&deletedAt)
+	switch {
+	case err == sql.ErrNoRows:
+	case err != nil:
 		return nil, fmt.Errorf("failed to check table existence: %w", err)
-	}
-	if exists {
-		return nil, fmt.Errorf("table with id %d already exists", req.ID)
+	case deletedAt.Valid:
+		return nil, deletedTableError([]int{table.ID})
+	default:
+		return nil, tableExistsError(table.ID)
 	}
 
-	query := `INSERT INTO tables (id) VALUES ($1)`
+	query := `INSERT INTO tables (id, min_covers, max_covers, zone, shape, pos_x, pos_y, rotation) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
 
-	_, err = r.db.ExecContext(ctx, query, req.ID)
+	_, err = r.db.ExecContext(ctx, query, table.ID, table.MinCovers, table.MaxCovers, table.Zone, table.Shape, table.X, table.Y, table.Rotation)
 	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return nil, tableExistsError(table.ID) // created by a concurrent request
+		}
 		return nil, fmt.Errorf("failed to create table: %w", err)
 	}
 
-	return &Table{ID: req.ID}, nil
+	return table, nil
+}
+
+// UpdateTable updates the capacity and floor plan layout of a live table
+func (r *postgresRepository) UpdateTable(ctx context.Context, table *Table) error {
+	query := `UPDATE tables SET min_covers = $1, max_covers = $2, zone = $3, shape = $4, pos_x = $5, pos_y = $6, rotation = $7
+		WHERE id = $8 AND deleted_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, table.MinCovers, table.MaxCovers, table.Zone, table.Shape, table.X, table.Y, table.Rotation, table.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update table: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return apperrors.ErrTableNotFound
+	}
+
+	return nil
 }
 
 // GetTable retrieves a table by ID
 func (r *postgresRepository) GetTable(ctx context.Context, id int) (*Table, error) {
-	query := `SELECT id FROM tables WHERE id = $1`
+	query := `SELECT ` + tableColumns + ` FROM tables WHERE id = $1`
 
-	var tableID int
-	err := r.db.QueryRowContext(ctx, query, id).Scan(&tableID)
+	table, err := scanTable(r.db.QueryRowContext(ctx, query, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("table with id %d not found", id)
+			return nil, apperrors.ErrTableNotFound
 		}
 		return nil, fmt.Errorf("failed to get table: %w", err)
 	}
 
-	return &Table{ID: tableID}, nil
+	return table, nil
 }
 
 // GetTableByNumber retrieves a table by table number
@@ -280,11 +448,11 @@ func (r *postgresRepository) GetTableByNumber(ctx context.Context, number int) (
 	return r.GetTable(ctx, number)
 }
 
-// ListTables lists all tables
-func (r *postgresRepository) ListTables(ctx context.Context) ([]*Table, error) {
-	query := `SELECT id FROM tables ORDER BY id`
+// ListTables lists all tables, optionally including soft-deleted ones
+func (r *postgresRepository) ListTables(ctx context.Context, includeDeleted bool) ([]*Table, error) {
+	query := `SELECT ` + tableColumns + ` FROM tables WHERE ($1 OR deleted_at IS NULL) ORDER BY id`
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := r.db.QueryContext(ctx, query, includeDeleted)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list tables: %w", err)
 	}
@@ -292,12 +460,11 @@ func (r *postgresRepository) ListTables(ctx context.Context) ([]*Table, error) {
 
 	var tables []*Table
 	for rows.Next() {
-		var tableID int
-		err := rows.Scan(&tableID)
+		table, err := scanTable(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan table: %w", err)
 		}
-		tables = append(tables, &Table{ID: tableID})
+		tables = append(tables, table)
 	}
 
 	if err = rows.Err(); err != nil {
@@ -307,10 +474,10 @@ func (r *postgresRepository) ListTables(ctx context.Context) ([]*Table, error) {
 	return tables, nil
 }
 
-// DeleteTable deletes a table by ID
+// DeleteTable soft deletes a table by ID
 func (r *postgresRepository) DeleteTable(ctx context.Context, id int) error {
-	// Check if table has active sessions
-	sessionQuery := `SELECT COUNT(*) FROM sessions WHERE table_id = $1 AND status = 'active'`
+	// Check if table has active sessions, including sessions it is joined to
+	sessionQuery := `SELECT COUNT(*) FROM session_table_assignments WHERE table_id = $1 AND status = 'active'`
 	var activeSessions int
 	err := r.db.QueryRowContext(ctx, sessionQuery, id).Scan(&activeSessions)
 	if err != nil {
@@ -320,8 +487,9 @@ func (r *postgresRepository) DeleteTable(ctx context.Context, id int) error {
 		return fmt.Errorf("cannot delete table with active sessions")
 	}
 
-	query := `DELETE FROM tables WHERE id = $1`
-	result, err := r.db.ExecContext(ctx, query, id)
+	// Soft delete so past sessions keep referencing the table
+	query := `UPDATE tables SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL`
+	result, err := r.db.ExecContext(ctx, query, time.Now(), id)
 	if err != nil {
 		return fmt.Errorf("failed to delete table: %w", err)
 	}
@@ -331,7 +499,26 @@ func (r *postgresRepository) DeleteTable(ctx context.Context, id int) error {
 		return fmt.Errorf("failed to get rows affected: %w", err)
 	}
 	if rowsAffected == 0 {
-		return fmt.Errorf("table with id %d not found", id)
+		return apperrors.ErrTableNotFound
+	}
+
+	return nil
+}
+
+// RestoreTable clears the soft delete marker of a table
+func (r *postgresRepository) RestoreTable(ctx context.Context, id int) error {
+	query := `UPDATE tables SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL`
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to restore table: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return apperrors.ErrTableNotFound
 	}
 
 	return nil
@@ -348,12 +535,33 @@ func (r *postgresRepository) TableExists(ctx context.Context, number int) (bool,
 	return count > 0, nil
 }
 
-// BulkCreateTables creates multiple tables in a single query
+// BulkCreateTables creates multiple tables in a single query, skipping tables that already exist. Deleted
+// tables in the range are not skipped silently: the request is refused so they can be restored instead.
 func (r *postgresRepository) BulkCreateTables(ctx context.Context, tableIDs []int) error {
 	if len(tableIDs) == 0 {
 		return nil
 	}
 
+	rows, err := r.db.QueryContext(ctx, "SELECT id FROM tables WHERE id = ANY($1) AND deleted_at IS NOT NULL ORDER BY id", pq.Array(tableIDs))
+	if err != nil {
+		return fmt.Errorf("failed to check for deleted tables: %w", err)
+	}
+	defer rows.Close()
+	var deleted []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return fmt.Errorf("failed to check for deleted tables: %w", err)
+		}
+		deleted = append(deleted, id)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to check for deleted tables: %w", err)
+	}
+	if len(deleted) > 0 {
+		return deletedTableError(deleted)
+	}
+
 	// Build the VALUES clause
 	valueStrings := make([]string, 0, len(tableIDs))
 	valueArgs := make([]interface{}, 0, len(tableIDs))
@@ -364,14 +572,34 @@ func (r *postgresRepository) BulkCreateTables(ctx context.Context, tableIDs []in
 
 	query := fmt.Sprintf("INSERT INTO tables (id) VALUES %s ON CONFLICT (id) DO NOTHING", strings.Join(valueStrings, ","))
 
-	_, err := r.db.ExecContext(ctx, query, valueArgs...)
+	_, err = r.db.ExecContext(ctx, query, valueArgs...)
 	return err
 }
 
-// IsTableAvailable checks if a table has no active or pending sessions
+// tableExistsError reports that a live table already has the number id
+func tableExistsError(id int) error {
+	return apperrors.NewConflictError(fmt.Sprintf("table %d already exists", id))
+}
+
+// deletedTableError reports that tables being created were deleted earlier and still hold their numbers
+func deletedTableError(ids []int) error {
+	if len(ids) == 1 {
+		return apperrors.NewConflictError(fmt.Sprintf("table %d was deleted; restore it with POST /sessions/tables/%d/restore", ids[0], ids[0]))
+	}
+	numbers := make([]string, len(ids))
+	for i, id := range ids {
+		numbers[i] = strconv.Itoa(id)
+	}
+	return apperrors.NewConflictError(fmt.Sprintf("tables %s were deleted; restore them with POST /sessions/tables/:id/restore", strings.Join(numbers, ", ")))
+}
+
+// IsTableAvailable checks if a table is live and is not held by an active or pending session,
+// either as the session's own table or as one joined to it
 func (r *postgresRepository) IsTableAvailable(ctx context.Context, tableID int) (bool, error) {
-	query := `SELECT NOT EXISTS (
-		SELECT 1 FROM sessions
+	query := `SELECT EXISTS (
+		SELECT 1 FROM tables WHERE id = $1 AND deleted_at IS NULL
+	) AND NOT EXISTS (
+		SELECT 1 FROM session_table_assignments
 		WHERE table_id = $1
 			AND status IN ('active', 'pending')
 	)`
@@ -382,3 +610,463 @@ func (r *postgresRepository) IsTableAvailable(ctx context.Context, tableID int)
 	}
 	return available, nil
 }
+
+// FindAvailableTables lists live, unoccupied tables that seat the party (any size when partySize is 0) in the
+// zone (any zone when empty), smallest fitting tables first
+func (r *postgresRepository) FindAvailableTables(ctx context.Context, partySize int, zone string) ([]*Table, error) {
+	query := `SELECT ` + tableColumns + ` FROM tables t
+		WHERE t.deleted_at IS NULL
+			AND ($1 = 0 OR (t.min_covers <= $1 AND t.max_covers >= $1))
+			AND ($2 = '' OR LOWER(t.zone) = LOWER($2))
+			AND NOT EXISTS (
+				SELECT 1 FROM session_table_assignments a
+				WHERE a.table_id = t.id AND a.status IN ('active', 'pending')
+			)
+		ORDER BY t.max_covers, t.id`
+
+	rows, err := r.db.QueryContext(ctx, query, partySize, zone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find available tables: %w", err)
+	}
+	defer rows.Close()
+
+	tables := []*Table{}
+	for rows.Next() {
+		table, err := scanTable(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan table: %w", err)
+		}
+		tables = append(tables, table)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tables: %w", err)
+	}
+
+	return tables, nil
+}
+
+// GetFloorPlan lists every live table with whether it currently has an active or pending session
+func (r *postgresRepository) GetFloorPlan(ctx context.Context) ([]FloorPlanTable, error) {
+	query := `SELECT ` + tableColumns + `, EXISTS (
+			SELECT 1 FROM session_table_assignments a
+			WHERE a.table_id = tables.id AND a.status IN ('active', 'pending')
+		) FROM tables
+		WHERE deleted_at IS NULL
+		ORDER BY zone, id`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load floor plan: %w", err)
+	}
+	defer rows.Close()
+
+	var plan []FloorPlanTable
+	for rows.Next() {
+		var occupied bool
+		table, err := scanTable(rows, &occupied)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan table: %w", err)
+		}
+		plan = append(plan, FloorPlanTable{Table: *table, Occupied: occupied})
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tables: %w", err)
+	}
+
+	return plan, nil
+}
+
+// lockLiveSession locks a session row for the rest of the transaction and checks it is active or pending. Every
+// caller changes the session, so the lock also bumps its version; a failed check rolls the bump back.
+func lockLiveSession(ctx context.Context, tx *sql.Tx, sessionID uuid.UUID) (*Session, error) {
+	var session Session
+	var status string
+	err := tx.QueryRowContext(ctx, "UPDATE sessions SET version = version + 1 WHERE id = $1 RETURNING id, table_id, created_at, status, server_id, version", sessionID).Scan(
+		&session.ID, &session.TableID, &session.CreatedAt, &status, &session.ServerID, &session.Version)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, apperrors.ErrSessionNotFound
+		}
+		return nil, err
+	}
+	session.Status = SessionStatus(status)
+	if session.Status != StatusActive && session.Status != StatusPending {
+		return nil, apperrors.NewConflictError("session is " + status + ", only active or pending sessions can be changed")
+	}
+	return &session, nil
+}
+
+// JoinTables pushes extra tables together with a live session's table. Each table is locked and must be live
+// and not held by any active or pending session.
+func (r *postgresRepository) JoinTables(ctx context.Context, sessionID uuid.UUID, tableIDs []int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := lockLiveSession(ctx, tx, sessionID); err != nil {
+		return err
+	}
+
+	for _, tableID := range tableIDs {
+		var id int
+		err := tx.QueryRowContext(ctx, "SELECT id FROM tables WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", tableID).Scan(&id)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return apperrors.NewNotFoundError(fmt.Sprintf("table %d not found", tableID))
+			}
+			return fmt.Errorf("failed to lock table: %w", err)
+		}
+
+		var occupied bool
+		err = tx.QueryRowContext(ctx, `SELECT EXISTS (
+			SELECT 1 FROM session_table_assignments WHERE table_id = $1 AND status IN ('active', 'pending')
+		)`, tableID).Scan(&occupied)
+		if err != nil {
+			return fmt.Errorf("failed to check table availability: %w", err)
+		}
+		if occupied {
+			return apperrors.NewConflictError(fmt.Sprintf("table %d is not available", tableID))
+		}
+
+		_, err = tx.ExecContext(ctx, "INSERT INTO session_tables (session_id, table_id, joined_at) VALUES ($1, $2, $3)", sessionID, tableID, time.Now())
+		if err != nil {
+			return fmt.Errorf("failed to join table: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// ReleaseJoinedTable removes one joined table from a live session
+func (r *postgresRepository) ReleaseJoinedTable(ctx context.Context, sessionID uuid.UUID, tableID int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := lockLiveSession(ctx, tx, sessionID); err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, "DELETE FROM session_tables WHERE session_id = $1 AND table_id = $2", sessionID, tableID)
+	if err != nil {
+		return fmt.Errorf("failed to release table: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return apperrors.NewNotFoundError(fmt.Sprintf("table %d is not joined to this session", tableID))
+	}
+
+	return tx.Commit()
+}
+
+// GetJoinedTableIDs lists the extra tables joined to a session
+func (r *postgresRepository) GetJoinedTableIDs(ctx context.Context, sessionID uuid.UUID) ([]int, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT table_id FROM session_tables WHERE session_id = $1 ORDER BY table_id", sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list joined tables: %w", err)
+	}
+	defer rows.Close()
+
+	var tableIDs []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan joined table: %w", err)
+		}
+		tableIDs = append(tableIDs, id)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating joined tables: %w", err)
+	}
+	return tableIDs, nil
+}
+
+// SplitSession detaches joined tables from a live session, starting an active session on each and moving the
+// listed orders over, all in one transaction. Orders must belong to the session being split.
+func (r *postgresRepository) SplitSession(ctx context.Context, sessionID uuid.UUID, parts []SplitPart) ([]*Session, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	original, err := lockLiveSession(ctx, tx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	sessions := make([]*Session, 0, len(parts))
+	movedGuests := 0
+	for _, part := range parts {
+		result, err := tx.ExecContext(ctx, "DELETE FROM session_tables WHERE session_id = $1 AND table_id = $2", sessionID, part.TableID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to detach table: %w", err)
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rowsAffected == 0 {
+			return nil, apperrors.NewValidationError(fmt.Sprintf("table %d is not joined to this session", part.TableID))
+		}
+
+		// The split-off party keeps the server who was already looking after it
+		session := &Session{ID: uuid.New(), TableID: part.TableID, CreatedAt: now, Status: StatusActive, GuestCount: part.GuestCount, ServerID: original.ServerID}
+		_, err = tx.ExecContext(ctx, "INSERT INTO sessions (id, table_id, created_at, completed_at, status, guest_count, server_id) VALUES ($1, $2, $3, $4, $5, NULLIF($6, 0), $7)",
+			session.ID, session.TableID, session.CreatedAt, nil, session.Status, session.GuestCount, session.ServerID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create session: %w", err)
+		}
+
+		if len(part.OrderIDs) > 0 {
+			orderIDs := make([]string, len(part.OrderIDs))
+			for i, id := range part.OrderIDs {
+				orderIDs[i] = id.String()
+			}
+			result, err := tx.ExecContext(ctx, "UPDATE orders SET version = version + 1, session_id = $1 WHERE session_id = $2 AND id = ANY($3)",
+				session.ID, sessionID, pq.Array(orderIDs))
+			if err != nil {
+				return nil, fmt.Errorf("failed to move orders: %w", err)
+			}
+			moved, err := result.RowsAffected()
+			if err != nil {
+				return nil, fmt.Errorf("failed to get rows affected: %w", err)
+			}
+			if int(moved) != len(orderIDs) {
+				return nil, apperrors.NewValidationError(fmt.Sprintf("orders for table %d must all belong to the session being split", part.TableID))
+			}
+		}
+
+		sessions = append(sessions, session)
+		movedGuests += part.GuestCount
+	}
+
+	// Guests who moved with their table no longer count towards the original session
+	if movedGuests > 0 {
+		_, err := tx.ExecContext(ctx, "UPDATE sessions SET guest_count = guest_count - $1 WHERE id = $2 AND guest_count IS NOT NULL", movedGuests, sessionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update guest count: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit split: %w", err)
+	}
+	return sessions, nil
+}
+
+// GetTableTokenNonce returns the QR token nonce of a live table and when it was last rotated
+func (r *postgresRepository) GetTableTokenNonce(ctx context.Context, tableID int) (string, time.Time, error) {
+	var nonce string
+	var rotatedAt time.Time
+	err := r.db.QueryRowContext(ctx, "SELECT qr_token_nonce, qr_token_rotated_at FROM tables WHERE id = $1 AND deleted_at IS NULL", tableID).Scan(&nonce, &rotatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", time.Time{}, apperrors.NewNotFoundError(fmt.Sprintf("table %d not found", tableID))
+		}
+		return "", time.Time{}, fmt.Errorf("failed to get table token: %w", err)
+	}
+	return nonce, rotatedAt, nil
+}
+
+// RotateTableToken replaces the QR token nonce of a live table
+func (r *postgresRepository) RotateTableToken(ctx context.Context, tableID int, nonce string, at time.Time) error {
+	result, err := r.db.ExecContext(ctx, "UPDATE tables SET qr_token_nonce = $1, qr_token_rotated_at = $2 WHERE id = $3 AND deleted_at IS NULL", nonce, at, tableID)
+	if err != nil {
+		return fmt.Errorf("failed to rotate table token: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return apperrors.NewNotFoundError(fmt.Sprintf("table %d not found", tableID))
+	}
+	return nil
+}
+
+// FindLiveSessionByTable returns the active or pending session holding a table, directly or as a joined
+// table, or nil if the table is free
+func (r *postgresRepository) FindLiveSessionByTable(ctx context.Context, tableID int) (*uuid.UUID, error) {
+	var id uuid.UUID
+	err := r.db.QueryRowContext(ctx, `SELECT session_id FROM session_table_assignments
+		WHERE table_id = $1 AND status IN ('active', 'pending') ORDER BY created_at LIMIT 1`, tableID).Scan(&id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find session for table: %w", err)
+	}
+	return &id, nil
+}
+
+// ListIdleSessions lists sessions in a status whose last activity, the session start or newest order, is
+// before idleBefore, least recently active first
+func (r *postgresRepository) ListIdleSessions(ctx context.Context, status SessionStatus, idleBefore time.Time) ([]*IdleSession, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT s.id, s.table_id, s.created_at, s.status, s.idle_flagged_at, a.last_activity, a.served_orders
+		FROM sessions s
+		CROSS JOIN LATERAL (
+			SELECT GREATEST(s.created_at, MAX(o.created_at)) AS last_activity,
+				COUNT(*) FILTER (WHERE o.status = 'served') AS served_orders
+			FROM orders o WHERE o.session_id = s.id
+		) a
+		WHERE s.status = $1 AND a.last_activity < $2
+		ORDER BY a.last_activity`, status, idleBefore)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list idle sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []*IdleSession
+	for rows.Next() {
+		var s IdleSession
+		var status string
+		if err := rows.Scan(&s.ID, &s.TableID, &s.CreatedAt, &status, &s.FlaggedAt, &s.LastActivity, &s.ServedOrders); err != nil {
+			return nil, fmt.Errorf("failed to scan idle session: %w", err)
+		}
+		s.Status = SessionStatus(status)
+		sessions = append(sessions, &s)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating idle sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+// FlagIdleSession records that the sweeper warned about an idle session
+func (r *postgresRepository) FlagIdleSession(ctx context.Context, id uuid.UUID, at time.Time) error {
+	_, err := r.db.ExecContext(ctx, "UPDATE sessions SET idle_flagged_at = $1 WHERE id = $2", at, id)
+	if err != nil {
+		return fmt.Errorf("failed to flag idle session: %w", err)
+	}
+	return nil
+}
+
+// CancelIdleSession cancels a session only if it is still in the given status, has no served orders and has
+// had no order placed since lastActivity, so a party that ordered in the meantime keeps its table
+func (r *postgresRepository) CancelIdleSession(ctx context.Context, id uuid.UUID, status SessionStatus, lastActivity time.Time) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `UPDATE sessions s SET version = version + 1, status = $1, completed_at = NULL
+		WHERE s.id = $2 AND s.status = $3 AND NOT EXISTS (
+			SELECT 1 FROM orders o WHERE o.session_id = s.id AND (o.status = 'served' OR o.created_at > $4)
+		)`, StatusCancelled, id, status, lastActivity)
+	if err != nil {
+		return false, fmt.Errorf("failed to cancel idle session: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+// UpdateGuestCount changes the guest count of a live session
+func (r *postgresRepository) UpdateGuestCount(ctx context.Context, id uuid.UUID, guestCount int) error {
+	result, err := r.db.ExecContext(ctx, "UPDATE sessions SET version = version + 1, guest_count = $1 WHERE id = $2 AND status IN ('active', 'pending')", guestCount, id)
+	if err != nil {
+		return fmt.Errorf("failed to update guest count: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return apperrors.NewConflictError("only active or pending sessions can change their guest count")
+	}
+	return nil
+}
+
+// GetCoversReport totals covers and served order revenue of sessions completed that started in [from, to).
+// Orders are priced from the menu price history at the time they were placed.
+func (r *postgresRepository) GetCoversReport(ctx context.Context, from time.Time, to time.Time) (*CoversReport, error) {
+	report := CoversReport{From: from, To: to}
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*), COUNT(s.guest_count), COALESCE(SUM(s.guest_count), 0),
+			COALESCE(SUM(r.revenue), 0), COALESCE(SUM(r.revenue) FILTER (WHERE s.guest_count IS NOT NULL), 0)
+		FROM sessions s
+		CROSS JOIN LATERAL (
+			SELECT SUM(oi.quantity * COALESCE(p.price, mi.price)) AS revenue
+			FROM orders o
+			JOIN order_items oi ON oi.order_id = o.id
+			JOIN menu_items mi ON mi.id = oi.menu_item_id
+			LEFT JOIN menu_item_prices p ON p.menu_item_id = oi.menu_item_id
+				AND p.effective_from <= o.created_at AND (p.effective_to IS NULL OR p.effective_to > o.created_at)
+			WHERE o.session_id = s.id AND o.status = 'served'
+		) r
+		WHERE s.status = 'completed' AND s.created_at >= $1 AND s.created_at < $2`, from, to).Scan(
+		&report.Sessions, &report.CountedSessions, &report.Covers, &report.Revenue, &report.CountedRevenue)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build covers report: %w", err)
+	}
+	return &report, nil
+}
+
+// ReassignServer hands a live session, and its orders that are not yet served or cancelled, to another server.
+// The new server must be an active staff member with the server role.
+func (r *postgresRepository) ReassignServer(ctx context.Context, id uuid.UUID, serverID uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var role string
+	var active bool
+	err = tx.QueryRowContext(ctx, "SELECT role, active FROM staff WHERE id = $1 FOR SHARE", serverID).Scan(&role, &active)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return apperrors.ErrStaffNotFound
+		}
+		return fmt.Errorf("failed to get server: %w", err)
+	}
+	if role != "server" || !active {
+		return apperrors.NewValidationError("sessions can only be assigned to active servers")
+	}
+
+	session, err := lockLiveSession(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE sessions SET server_id = $1 WHERE id = $2", serverID, session.ID); err != nil {
+		return fmt.Errorf("failed to reassign session: %w", err)
+	}
+	_, err = tx.ExecContext(ctx, "UPDATE orders SET version = version + 1, server_id = $1 WHERE session_id = $2 AND status NOT IN ('served', 'cancelled')", serverID, session.ID)
+	if err != nil {
+		return fmt.Errorf("failed to reassign orders: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit reassignment: %w", err)
+	}
+	return nil
+}
+
+// ListServerSessions lists the active and pending sessions looked after by a server, oldest first
+func (r *postgresRepository) ListServerSessions(ctx context.Context, serverID uuid.UUID) ([]*Session, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT id, table_id, created_at, completed_at, status, COALESCE(guest_count, 0), server_id, version FROM sessions WHERE server_id = $1 AND status IN ('active', 'pending') ORDER BY created_at", serverID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list server sessions: %w", err)
+	}
+	defer rows.Close()
+
+	sessions := []*Session{}
+	for rows.Next() {
+		var session Session
+		var status string
+		if err := rows.Scan(&session.ID, &session.TableID, &session.CreatedAt, &session.CompletedAt, &status, &session.GuestCount, &session.ServerID, &session.Version); err != nil {
+			return nil, fmt.Errorf("failed to scan session: %w", err)
+		}
+		session.Status = SessionStatus(status)
+		sessions = append(sessions, &session)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating sessions: %w", err)
+	}
+	return sessions, nil
+}
@@ -0,0 +1,110 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"testing"
+
+	"restaurant/internal/dbtest"
+	apperrors "restaurant/internal/errors"
+
+	"github.com/google/uuid"
+)
+
+// racers is how many requests try to take the same table at once
+const racers = 20
+
+// liveSessions counts the active and pending sessions whose own table is tableID
+func liveSessions(t *testing.T, db *sql.DB, tableID int) int {
+	t.Helper()
+	var count int
+	err := db.QueryRow("SELECT COUNT(*) FROM sessions WHERE table_id = $1 AND status IN ('active', 'pending')", tableID).Scan(&count)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return count
+}
+
+func TestCreateSessionConcurrent(t *testing.T) {
+	db := dbtest.Open(t)
+	repo := NewPostgresRepository(db)
+	tableID := dbtest.NewTable(t, db)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var seated int
+	for range racers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := repo.CreateSession(context.Background(), uuid.New(), tableID, 0)
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				seated++
+			case err != apperrors.ErrTableNotAvailable:
+				t.Errorf("CreateSession: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if seated != 1 {
+		t.Errorf("%d of %d concurrent creates succeeded, want 1", seated, racers)
+	}
+	if n := liveSessions(t, db, tableID); n != 1 {
+		t.Errorf("table has %d live sessions, want 1", n)
+	}
+}
+
+func TestChangeSessionTableConcurrent(t *testing.T) {
+	db := dbtest.Open(t)
+	repo := NewPostgresRepository(db)
+	from := dbtest.NewTable(t, db)
+	to := dbtest.NewTable(t, db)
+	ctx := context.Background()
+
+	moving, err := repo.CreateSession(ctx, uuid.New(), from, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var winners int
+	record := func(op string, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case err == nil:
+			winners++
+		case err != apperrors.ErrTableNotAvailable:
+			t.Errorf("%s: %v", op, err)
+		}
+	}
+	for i := range racers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := repo.CreateSession(ctx, uuid.New(), to, 0)
+			record("CreateSession", err)
+		}()
+		if i == racers/2 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				record("ChangeSessionTable", repo.ChangeSessionTable(ctx, moving.ID, to))
+			}()
+		}
+	}
+	wg.Wait()
+
+	if winners != 1 {
+		t.Errorf("%d sessions took the table, want 1", winners)
+	}
+	if n := liveSessions(t, db, to); n != 1 {
+		t.Errorf("table has %d live sessions, want 1", n)
+	}
+}
@@ -2,8 +2,14 @@ package session
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"fmt"
+	"math"
+	"net/url"
 	apperrors "restaurant/internal/errors"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/lib/pq"
@@ -11,9 +17,9 @@ import (
 
 // SessionService defines business logic for sessions
 type SessionService interface {
-	CreateSession(ctx context.Context, tableID int) (*Session, error)
+	CreateSession(ctx context.Context, tableID int, guestCount int) (*Session, error)
 	GetSession(ctx context.Context, id uuid.UUID) (*Session, error)
-	UpdateSession(ctx context.Context, id uuid.UUID, status SessionStatus) (*Session, error)
+	UpdateSession(ctx context.Context, id uuid.UUID, status SessionStatus, version int) (*Session, error)
 	ListSessions(ctx context.Context, offset, limit int) ([]*Session, error)
 	ListActiveSessions(ctx context.Context) ([]*Session, error)
 	ChangeTable(ctx context.Context, id uuid.UUID, tableNumber int) error
@@ -23,39 +29,113 @@ type SessionService interface {
 
 	// Table operations
 	CreateTable(ctx context.Context, req *CreateTableRequest) (*Table, error)
+	UpdateTable(ctx context.Context, id int, req *UpdateTableRequest) (*Table, error)
 	GetTable(ctx context.Context, id int) (*Table, error)
 	GetTableByNumber(ctx context.Context, number int) (*Table, error)
-	ListTables(ctx context.Context) ([]*Table, error)
+	ListTables(ctx context.Context, includeDeleted bool) ([]*Table, error)
 	DeleteTable(ctx context.Context, id int) error
+	RestoreTable(ctx context.Context, id int) (*Table, error)
 	BulkCreateTables(ctx context.Context, start, end int) error
 	IsTableAvailable(ctx context.Context, tableID int) (bool, error)
+	FindAvailableTables(ctx context.Context, partySize int, zone string) ([]*Table, error)
+	GetFloorPlan(ctx context.Context) ([]FloorPlanZone, error)
+
+	// Table groups
+	JoinTables(ctx context.Context, id uuid.UUID, tableIDs []int) (*Session, error)
+	ReleaseJoinedTable(ctx context.Context, id uuid.UUID, tableID int) (*Session, error)
+	SplitSession(ctx context.Context, id uuid.UUID, parts []SplitPart) ([]*Session, error)
+
+	// QR table tokens
+	GetTableToken(ctx context.Context, tableID int) (*TableQRToken, error)
+	RotateTableToken(ctx context.Context, tableID int) (*TableQRToken, error)
+	StartSessionWithToken(ctx context.Context, token string, tableID int, guestCount int) (*GuestSession, error)
+	JoinSessionWithToken(ctx context.Context, token string) (*GuestSession, error)
+	GetGuestSession(ctx context.Context, id uuid.UUID) (*Session, error)
+
+	// Idle sessions
+	ListIdleSessions(ctx context.Context, status SessionStatus, idleFor time.Duration) ([]*IdleSession, error)
+	FlagIdleSession(ctx context.Context, id uuid.UUID) error
+	CancelIdleSession(ctx context.Context, session *IdleSession) (bool, error)
+
+	// Guest counts
+	UpdateGuestCount(ctx context.Context, id uuid.UUID, guestCount int) (*Session, error)
+	GetCoversReport(ctx context.Context, from time.Time, to time.Time) (*CoversReport, error)
+
+	// Server assignment
+	ReassignServer(ctx context.Context, id uuid.UUID, serverID uuid.UUID) (*Session, error)
+	ListServerSessions(ctx context.Context, serverID uuid.UUID) ([]*Session, error)
+}
+
+// Defaults for tables created without layout details
+const (
+	defaultTableZone      = "main"
+	defaultTableMaxCovers = 4
+)
+
+// Config holds session service configuration
+type Config struct {
+	TokenSecret []byte // HMAC key for table QR tokens; must be stable across restarts
+	QRBaseURL   string // guest app link encoded in QR codes, with the token appended as ?t=; empty encodes the bare token
+}
+
+// DefaultConfig returns the session configuration used when none is provided
+func DefaultConfig() Config {
+	return Config{}
+}
+
+// GuestTokenIssuer issues the token that limits a guest who scanned a table QR code to one session
+type GuestTokenIssuer interface {
+	IssueGuestToken(sessionID uuid.UUID, tableID int) (string, time.Time, error)
 }
 
 // sessionService implements Service
 type sessionService struct {
-	repo Repository
+	repo        Repository
+	tokens      *tokenSigner
+	guestTokens GuestTokenIssuer
+	qrBaseURL   string
 }
 
-// NewService creates a new session service
-func NewService(repo Repository) SessionService {
-	return &sessionService{repo: repo}
+// NewService creates a new session service. Without a token secret, a random one is generated, so printed QR
+// codes stop working when the process restarts.
+func NewService(repo Repository, config Config, guestTokens GuestTokenIssuer) SessionService {
+	if len(config.TokenSecret) == 0 {
+		config.TokenSecret = make([]byte, 32)
+		if _, err := rand.Read(config.TokenSecret); err != nil {
+			panic("session: failed to generate token secret: " + err.Error())
+		}
+	}
+	return &sessionService{
+		repo:        repo,
+		tokens:      &tokenSigner{secret: config.TokenSecret},
+		guestTokens: guestTokens,
+		qrBaseURL:   config.QRBaseURL,
+	}
 }
 
-// CreateSession creates a new session
-func (s *sessionService) CreateSession(ctx context.Context, tableID int) (*Session, error) {
-	// Check if table is available (no active or pending sessions)
+// CreateSession creates a new session; guestCount is optional (0) but may not exceed the table's capacity
+func (s *sessionService) CreateSession(ctx context.Context, tableID int, guestCount int) (*Session, error) {
+	// Check if table is available (live, no active or pending sessions); the repository checks again under a lock
 	available, err := s.IsTableAvailable(ctx, tableID)
 	if err != nil {
 		return nil, apperrors.WrapError(500, "failed to check table availability", err)
 	}
 	if !available {
-		return nil, apperrors.WrapError(409, "table is not available (has active or pending session)", nil)
+		return nil, apperrors.ErrTableNotAvailable
+	}
+
+	if err := s.checkCapacity(ctx, []int{tableID}, guestCount); err != nil {
+		return nil, err
 	}
 
 	// Shape validation (tableID > 0) already done by handler using ValidateStruct
 	id := uuid.New()
-	session, err := s.repo.CreateSession(ctx, id, tableID)
+	session, err := s.repo.CreateSession(ctx, id, tableID, guestCount)
 	if err != nil {
+		// Another request seated a session on the table after the check above
+		if err == apperrors.ErrTableNotAvailable {
+			return nil, err
+		}
 		// Check for foreign key constraint violation
 		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
 			return nil, apperrors.ErrForeignKeyViolation
@@ -66,23 +146,32 @@ func (s *sessionService) CreateSession(ctx context.Context, tableID int) (*Sessi
 	return session, nil
 }
 
-// GetSession retrieves a session
+// GetSession retrieves a session together with any tables joined to it
 func (s *sessionService) GetSession(ctx context.Context, id uuid.UUID) (*Session, error) {
 	session, err := s.repo.GetSession(ctx, id)
 	if err != nil {
 		return nil, apperrors.WrapError(500, "failed to retrieve session", err)
 	}
+	session.JoinedTableIDs, err = s.repo.GetJoinedTableIDs(ctx, id)
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to retrieve joined tables", err)
+	}
 	return session, nil
 }
 
-// UpdateSession updates the status of a session
-func (s *sessionService) UpdateSession(ctx context.Context, id uuid.UUID, status SessionStatus) (*Session, error) {
+// UpdateSession updates the status of a session that is still at version
+func (s *sessionService) UpdateSession(ctx context.Context, id uuid.UUID, status SessionStatus, version int) (*Session, error) {
 	// Get current session to validate state transition (BUSINESS LOGIC)
 	currentSession, err := s.repo.GetSession(ctx, id)
 	if err != nil {
 		return nil, apperrors.WrapError(500, "failed to retrieve session", err)
 	}
 
+	// The transition is checked against the version the caller saw, not whatever is current
+	if currentSession.Version != version {
+		return nil, apperrors.ErrVersionConflict
+	}
+
 	// Validate state transitions (BUSINESS LOGIC - cannot change completed/cancelled sessions)
 	validTransitions := map[SessionStatus][]SessionStatus{
 		"active":    {"pending", "cancelled"},
@@ -108,8 +197,11 @@ func (s *sessionService) UpdateSession(ctx context.Context, id uuid.UUID, status
 	}
 
 	// Shape validation (format, ranges) already done by handler using ValidateStruct
-	err = s.repo.UpdateSession(ctx, id, status)
+	err = s.repo.UpdateSession(ctx, id, status, version)
 	if err != nil {
+		if err == apperrors.ErrVersionConflict {
+			return nil, err
+		}
 		return nil, apperrors.WrapError(500, "failed to update session", err)
 	}
 
@@ -150,6 +242,10 @@ func (s *sessionService) ChangeTable(ctx context.Context, id uuid.UUID, tableNum
 
 	err = s.repo.ChangeSessionTable(ctx, id, tableNumber)
 	if err != nil {
+		// Another request seated a session on the table after the check above
+		if err == apperrors.ErrTableNotAvailable {
+			return apperrors.NewValidationError("table is not available")
+		}
 		// Check for foreign key constraint violation
 		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" {
 			return apperrors.ErrForeignKeyViolation
@@ -205,18 +301,43 @@ func (s *sessionService) BulkCreateTables(ctx context.Context, start, end int) e
 	}
 	err := s.repo.BulkCreateTables(ctx, tableIDs)
 	if err != nil {
+		if apperrors.AsAppError(err) != nil {
+			return err
+		}
 		return apperrors.WrapError(500, "failed to bulk create tables", err)
 	}
 	return nil
 }
 
-// CreateTable creates a new table
+// CreateTable creates a new table, filling in default capacity, zone and shape
 func (s *sessionService) CreateTable(ctx context.Context, req *CreateTableRequest) (*Table, error) {
-	table, err := s.repo.CreateTable(ctx, req)
+	table := &Table{
+		ID:        req.ID,
+		MinCovers: req.MinCovers,
+		MaxCovers: req.MaxCovers,
+		Zone:      normalizeZone(req.Zone),
+		Shape:     TableShape(req.Shape),
+		X:         req.X,
+		Y:         req.Y,
+		Rotation:  req.Rotation,
+	}
+	if table.MinCovers == 0 {
+		table.MinCovers = 1
+	}
+	if table.MaxCovers == 0 {
+		table.MaxCovers = max(defaultTableMaxCovers, table.MinCovers)
+	}
+	if table.MinCovers > table.MaxCovers {
+		return nil, apperrors.NewValidationError("min_covers must be less than or equal to max_covers")
+	}
+	if table.Shape == "" {
+		table.Shape = ShapeSquare
+	}
+
+	table, err := s.repo.CreateTable(ctx, table)
 	if err != nil {
-		// Check for unique constraint violation
-		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
-			return nil, apperrors.ErrConflict
+		if apperrors.AsAppError(err) != nil {
+			return nil, err // an existing or deleted table
 		}
 		return nil, apperrors.WrapError(500, "failed to create table", err)
 	}
@@ -242,8 +363,8 @@ func (s *sessionService) GetTableByNumber(ctx context.Context, number int) (*Tab
 }
 
 // ListTables lists all tables
-func (s *sessionService) ListTables(ctx context.Context) ([]*Table, error) {
-	tables, err := s.repo.ListTables(ctx)
+func (s *sessionService) ListTables(ctx context.Context, includeDeleted bool) ([]*Table, error) {
+	tables, err := s.repo.ListTables(ctx, includeDeleted)
 	if err != nil {
 		return nil, apperrors.WrapError(500, "failed to list tables", err)
 	}
@@ -259,6 +380,15 @@ func (s *sessionService) DeleteTable(ctx context.Context, id int) error {
 	return nil
 }
 
+// RestoreTable brings a soft-deleted table back into service
+func (s *sessionService) RestoreTable(ctx context.Context, id int) (*Table, error) {
+	err := s.repo.RestoreTable(ctx, id)
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to restore table", err)
+	}
+	return s.GetTable(ctx, id)
+}
+
 // IsTableAvailable checks if a table has no active or pending sessions
 func (s *sessionService) IsTableAvailable(ctx context.Context, tableID int) (bool, error) {
 	available, err := s.repo.IsTableAvailable(ctx, tableID)
@@ -267,3 +397,368 @@ func (s *sessionService) IsTableAvailable(ctx context.Context, tableID int) (boo
 	}
 	return available, nil
 }
+
+// UpdateTable changes the capacity or floor plan layout of a table
+func (s *sessionService) UpdateTable(ctx context.Context, id int, req *UpdateTableRequest) (*Table, error) {
+	table, err := s.repo.GetTable(ctx, id)
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to retrieve table", err)
+	}
+	if table.DeletedAt != nil {
+		return nil, apperrors.NewNotFoundError("table is deleted, restore it first")
+	}
+
+	if req.MinCovers != nil {
+		table.MinCovers = *req.MinCovers
+	}
+	if req.MaxCovers != nil {
+		table.MaxCovers = *req.MaxCovers
+	}
+	if req.Zone != nil {
+		table.Zone = normalizeZone(*req.Zone)
+	}
+	if req.Shape != nil {
+		table.Shape = TableShape(*req.Shape)
+	}
+	if req.X != nil {
+		table.X = *req.X
+	}
+	if req.Y != nil {
+		table.Y = *req.Y
+	}
+	if req.Rotation != nil {
+		table.Rotation = *req.Rotation
+	}
+	if table.MinCovers > table.MaxCovers {
+		return nil, apperrors.NewValidationError("min_covers must be less than or equal to max_covers")
+	}
+
+	if err := s.repo.UpdateTable(ctx, table); err != nil {
+		return nil, apperrors.WrapError(500, "failed to update table", err)
+	}
+	return table, nil
+}
+
+// FindAvailableTables lists free tables that seat the party in the zone, smallest fitting tables first
+func (s *sessionService) FindAvailableTables(ctx context.Context, partySize int, zone string) ([]*Table, error) {
+	tables, err := s.repo.FindAvailableTables(ctx, partySize, strings.TrimSpace(zone))
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to find available tables", err)
+	}
+	return tables, nil
+}
+
+// GetFloorPlan returns the live tables grouped by zone, with their current occupancy
+func (s *sessionService) GetFloorPlan(ctx context.Context) ([]FloorPlanZone, error) {
+	tables, err := s.repo.GetFloorPlan(ctx)
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to load floor plan", err)
+	}
+
+	// Tables arrive ordered by zone, so each zone is a contiguous run
+	zones := []FloorPlanZone{}
+	for _, table := range tables {
+		if len(zones) == 0 || zones[len(zones)-1].Zone != table.Zone {
+			zones = append(zones, FloorPlanZone{Zone: table.Zone, Tables: []FloorPlanTable{}})
+		}
+		last := &zones[len(zones)-1]
+		last.Tables = append(last.Tables, table)
+	}
+	return zones, nil
+}
+
+// normalizeZone trims and lowercases a zone name so "Patio" and "patio " are the same section
+func normalizeZone(zone string) string {
+	zone = strings.ToLower(strings.TrimSpace(zone))
+	if zone == "" {
+		return defaultTableZone
+	}
+	return zone
+}
+
+// JoinTables pushes extra tables together with a session's table for a large party. Joined tables count as
+// occupied for as long as the session is active or pending and are released with it.
+func (s *sessionService) JoinTables(ctx context.Context, id uuid.UUID, tableIDs []int) (*Session, error) {
+	session, err := s.GetSession(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, tableID := range tableIDs {
+		if tableID == session.TableID {
+			return nil, apperrors.NewValidationError(fmt.Sprintf("table %d is already the session's table", tableID))
+		}
+	}
+
+	if err := s.repo.JoinTables(ctx, id, tableIDs); err != nil {
+		return nil, apperrors.WrapError(500, "failed to join tables", err)
+	}
+	return s.GetSession(ctx, id)
+}
+
+// ReleaseJoinedTable frees one joined table while the rest of the party stays seated
+func (s *sessionService) ReleaseJoinedTable(ctx context.Context, id uuid.UUID, tableID int) (*Session, error) {
+	if err := s.repo.ReleaseJoinedTable(ctx, id, tableID); err != nil {
+		return nil, apperrors.WrapError(500, "failed to release table", err)
+	}
+	return s.GetSession(ctx, id)
+}
+
+// SplitSession turns joined tables back into separate sessions, dividing the orders between them.
+// The original session keeps its own table and every order not listed. It is returned first, followed by
+// the new sessions in the order of parts.
+func (s *sessionService) SplitSession(ctx context.Context, id uuid.UUID, parts []SplitPart) ([]*Session, error) {
+	// Shape validation (distinct tables and orders) already done by handler using ValidateSplitSession
+	session, err := s.GetSession(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	movedGuests := 0
+	for _, part := range parts {
+		if err := s.checkCapacity(ctx, []int{part.TableID}, part.GuestCount); err != nil {
+			return nil, err
+		}
+		movedGuests += part.GuestCount
+	}
+	if session.GuestCount > 0 && movedGuests >= session.GuestCount {
+		return nil, apperrors.NewValidationError(fmt.Sprintf("split moves %d of the session's %d guests, at least one must stay", movedGuests, session.GuestCount))
+	}
+
+	created, err := s.repo.SplitSession(ctx, id, parts)
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to split session", err)
+	}
+
+	original, err := s.GetSession(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return append([]*Session{original}, created...), nil
+}
+
+// GetTableToken returns the current QR token of a live table
+func (s *sessionService) GetTableToken(ctx context.Context, tableID int) (*TableQRToken, error) {
+	nonce, rotatedAt, err := s.repo.GetTableTokenNonce(ctx, tableID)
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to retrieve table token", err)
+	}
+	return s.tableQRToken(tableID, nonce, rotatedAt), nil
+}
+
+// RotateTableToken issues a new QR token for a table, revoking the old one
+func (s *sessionService) RotateTableToken(ctx context.Context, tableID int) (*TableQRToken, error) {
+	nonce, err := newTableTokenNonce()
+	if err != nil {
+		return nil, apperrors.NewInternalError("failed to generate table token", err)
+	}
+	now := time.Now()
+	if err := s.repo.RotateTableToken(ctx, tableID, nonce, now); err != nil {
+		return nil, apperrors.WrapError(500, "failed to rotate table token", err)
+	}
+	return s.tableQRToken(tableID, nonce, now), nil
+}
+
+// StartSessionWithToken starts a session on the table named by a QR token and issues the guest a credential
+// for it. A table ID sent alongside the token must match it, so a guest cannot open a session on a table they
+// are not sitting at.
+func (s *sessionService) StartSessionWithToken(ctx context.Context, token string, tableID int, guestCount int) (*GuestSession, error) {
+	tokenTableID, err := s.verifyTableToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if tableID != 0 && tableID != tokenTableID {
+		return nil, apperrors.ErrTableTokenMismatch
+	}
+	session, err := s.CreateSession(ctx, tokenTableID, guestCount)
+	if err != nil {
+		return nil, err
+	}
+	return s.guestSession(session, tokenTableID)
+}
+
+// JoinSessionWithToken exchanges a scanned QR token for a guest credential. Guests join the session already
+// running at the table, or start one if the table is free.
+func (s *sessionService) JoinSessionWithToken(ctx context.Context, token string) (*GuestSession, error) {
+	tableID, err := s.verifyTableToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionID, err := s.repo.FindLiveSessionByTable(ctx, tableID)
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to find session for table", err)
+	}
+	if sessionID == nil {
+		created, err := s.CreateSession(ctx, tableID, 0)
+		if err != nil {
+			// Another guest at the same table may have started the session first
+			if sessionID, _ = s.repo.FindLiveSessionByTable(ctx, tableID); sessionID == nil {
+				return nil, err
+			}
+		} else {
+			sessionID = &created.ID
+		}
+	}
+
+	session, err := s.GetSession(ctx, *sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return s.guestSession(session, tableID)
+}
+
+// guestSession issues a guest credential for a session seated at tableID
+func (s *sessionService) guestSession(session *Session, tableID int) (*GuestSession, error) {
+	credential, expiresAt, err := s.guestTokens.IssueGuestToken(session.ID, tableID)
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to issue guest credential", err)
+	}
+	return &GuestSession{Session: session, Credential: credential, ExpiresAt: expiresAt}, nil
+}
+
+// GetGuestSession returns the session a guest credential was issued for, as long as it is still live
+func (s *sessionService) GetGuestSession(ctx context.Context, id uuid.UUID) (*Session, error) {
+	session, err := s.GetSession(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status != StatusActive && session.Status != StatusPending {
+		return nil, apperrors.ErrInvalidGuestCredential
+	}
+	return session, nil
+}
+
+// verifyTableToken checks a table token's signature and that it has not been rotated out
+func (s *sessionService) verifyTableToken(ctx context.Context, token string) (int, error) {
+	tableID, nonce, err := s.tokens.parseTableToken(token)
+	if err != nil {
+		return 0, err
+	}
+	current, _, err := s.repo.GetTableTokenNonce(ctx, tableID)
+	if err != nil {
+		if appErr := apperrors.AsAppError(err); appErr != nil && appErr.Code == 404 {
+			return 0, apperrors.ErrInvalidTableToken
+		}
+		return 0, apperrors.WrapError(500, "failed to verify table token", err)
+	}
+	if !hmac.Equal([]byte(nonce), []byte(current)) {
+		return 0, apperrors.ErrInvalidTableToken
+	}
+	return tableID, nil
+}
+
+// tableQRToken builds the token and QR link for a table nonce
+func (s *sessionService) tableQRToken(tableID int, nonce string, rotatedAt time.Time) *TableQRToken {
+	token := s.tokens.signTableToken(tableID, nonce)
+	link := token
+	if s.qrBaseURL != "" {
+		separator := "?"
+		if strings.Contains(s.qrBaseURL, "?") {
+			separator = "&"
+		}
+		link = s.qrBaseURL + separator + "t=" + url.QueryEscape(token)
+	}
+	return &TableQRToken{TableID: tableID, Token: token, URL: link, RotatedAt: rotatedAt}
+}
+
+// ListIdleSessions lists sessions in a status that have had no activity for at least idleFor
+func (s *sessionService) ListIdleSessions(ctx context.Context, status SessionStatus, idleFor time.Duration) ([]*IdleSession, error) {
+	sessions, err := s.repo.ListIdleSessions(ctx, status, time.Now().Add(-idleFor))
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to list idle sessions", err)
+	}
+	return sessions, nil
+}
+
+// FlagIdleSession records that staff were warned about an idle session
+func (s *sessionService) FlagIdleSession(ctx context.Context, id uuid.UUID) error {
+	if err := s.repo.FlagIdleSession(ctx, id, time.Now()); err != nil {
+		return apperrors.WrapError(500, "failed to flag idle session", err)
+	}
+	return nil
+}
+
+// CancelIdleSession cancels an idle session to free its table. It reports false without error when the
+// session changed since it was listed: it moved on, got a new order, or has unpaid served orders.
+func (s *sessionService) CancelIdleSession(ctx context.Context, session *IdleSession) (bool, error) {
+	cancelled, err := s.repo.CancelIdleSession(ctx, session.ID, session.Status, session.LastActivity)
+	if err != nil {
+		return false, apperrors.WrapError(500, "failed to cancel idle session", err)
+	}
+	return cancelled, nil
+}
+
+// UpdateGuestCount corrects the number of guests of a live session, e.g. when late arrivals join the party
+func (s *sessionService) UpdateGuestCount(ctx context.Context, id uuid.UUID, guestCount int) (*Session, error) {
+	session, err := s.GetSession(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkCapacity(ctx, append([]int{session.TableID}, session.JoinedTableIDs...), guestCount); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateGuestCount(ctx, id, guestCount); err != nil {
+		return nil, apperrors.WrapError(500, "failed to update guest count", err)
+	}
+	return s.GetSession(ctx, id)
+}
+
+// GetCoversReport reports covers, average party size and spend per head for sessions completed in [from, to).
+// Sessions without a recorded guest count are left out of the per-head figures.
+func (s *sessionService) GetCoversReport(ctx context.Context, from time.Time, to time.Time) (*CoversReport, error) {
+	report, err := s.repo.GetCoversReport(ctx, from, to)
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to build covers report", err)
+	}
+	if report.CountedSessions > 0 {
+		report.AveragePartySize = math.Round(float64(report.Covers)/float64(report.CountedSessions)*100) / 100
+	}
+	if report.Covers > 0 {
+		report.SpendPerHead = math.Round(report.CountedRevenue/float64(report.Covers)*100) / 100
+	}
+	return report, nil
+}
+
+// ReassignServer hands a live session to another server. Orders still in progress move with it; served and
+// cancelled orders stay credited to the server who handled them.
+func (s *sessionService) ReassignServer(ctx context.Context, id uuid.UUID, serverID uuid.UUID) (*Session, error) {
+	if err := s.repo.ReassignServer(ctx, id, serverID); err != nil {
+		return nil, apperrors.WrapError(500, "failed to reassign server", err)
+	}
+	return s.GetSession(ctx, id)
+}
+
+// ListServerSessions lists the live sessions a server is looking after, with their joined tables
+func (s *sessionService) ListServerSessions(ctx context.Context, serverID uuid.UUID) ([]*Session, error) {
+	sessions, err := s.repo.ListServerSessions(ctx, serverID)
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to list server sessions", err)
+	}
+	for _, session := range sessions {
+		session.JoinedTableIDs, err = s.repo.GetJoinedTableIDs(ctx, session.ID)
+		if err != nil {
+			return nil, apperrors.WrapError(500, "failed to retrieve joined tables", err)
+		}
+	}
+	return sessions, nil
+}
+
+// checkCapacity rejects a guest count larger than the combined max covers of the tables (BUSINESS LOGIC).
+// Min covers only limit which tables are offered to a party, so smaller parties are allowed.
+func (s *sessionService) checkCapacity(ctx context.Context, tableIDs []int, guestCount int) error {
+	if guestCount <= 0 {
+		return nil
+	}
+	capacity := 0
+	for _, tableID := range tableIDs {
+		table, err := s.repo.GetTable(ctx, tableID)
+		if err != nil {
+			return apperrors.WrapError(500, "failed to retrieve table", err)
+		}
+		capacity += table.MaxCovers
+	}
+	if guestCount > capacity {
+		return apperrors.NewValidationError(fmt.Sprintf("%d guests exceed the seating capacity of %d", guestCount, capacity))
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package session
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IdleTimeout sets when a live session in one status counts as idle
+type IdleTimeout struct {
+	WarnAfter   time.Duration // idle time after which staff are alerted and the session is flagged
+	CancelAfter time.Duration // idle time after which a flagged session is cancelled; zero only flags
+}
+
+// SweeperConfig holds idle session sweeper configuration
+type SweeperConfig struct {
+	Interval time.Duration                 // time between sweeps
+	Timeouts map[SessionStatus]IdleTimeout // thresholds per live status; statuses not listed are never swept
+}
+
+// DefaultSweeperConfig returns the sweeper configuration used when none is provided
+func DefaultSweeperConfig() SweeperConfig {
+	return SweeperConfig{
+		Interval: 5 * time.Minute,
+		Timeouts: map[SessionStatus]IdleTimeout{
+			StatusActive:  {WarnAfter: 3 * time.Hour, CancelAfter: 6 * time.Hour},
+			StatusPending: {WarnAfter: time.Hour, CancelAfter: 3 * time.Hour},
+		},
+	}
+}
+
+// IdleAlertKind says what the sweeper did, or could not do, about an idle session
+type IdleAlertKind string
+
+const (
+	IdleAlertWarning   IdleAlertKind = "warning"   // the session passed WarnAfter and will be cancelled if it stays idle
+	IdleAlertBlocked   IdleAlertKind = "blocked"   // the session passed CancelAfter but has unpaid served orders
+	IdleAlertCancelled IdleAlertKind = "cancelled" // the session was cancelled and its table freed
+)
+
+// IdleAlert describes an idle session that staff should know about
+type IdleAlert struct {
+	Kind    IdleAlertKind
+	Session *IdleSession
+	IdleFor time.Duration
+}
+
+// Alerter delivers idle session alerts to staff
+type Alerter interface {
+	IdleSession(ctx context.Context, alert IdleAlert)
+}
+
+// LogAlerter writes idle session alerts to the application log
+type LogAlerter struct{}
+
+// IdleSession logs the alert
+func (LogAlerter) IdleSession(ctx context.Context, alert IdleAlert) {
+	log.Printf("Session sweeper: %s: session %s on table %d (%s) idle for %s",
+		alert.Kind, alert.Session.ID, alert.Session.TableID, alert.Session.Status, alert.IdleFor.Round(time.Minute))
+}
+
+// Sweeper periodically alerts on and cancels sessions left active or pending by mistake, so their tables do
+// not stay blocked. A session is always alerted on in one sweep before it can be cancelled in a later one,
+// and sessions with unpaid served orders are never cancelled.
+type Sweeper struct {
+	svc     SessionService
+	config  SweeperConfig
+	alerter Alerter
+	blocked map[uuid.UUID]bool // sessions already reported as blocked, so the alert is not repeated every sweep
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// NewSweeper creates an idle session sweeper; a nil alerter logs alerts
+func NewSweeper(svc SessionService, config SweeperConfig, alerter Alerter) *Sweeper {
+	if config.Interval <= 0 {
+		config.Interval = DefaultSweeperConfig().Interval
+	}
+	if config.Timeouts == nil {
+		config.Timeouts = DefaultSweeperConfig().Timeouts
+	}
+	if alerter == nil {
+		alerter = LogAlerter{}
+	}
+	return &Sweeper{
+		svc:     svc,
+		config:  config,
+		alerter: alerter,
+		blocked: make(map[uuid.UUID]bool),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+// Start runs the sweeper in the background
+func (s *Sweeper) Start() {
+	go s.run()
+}
+
+// Stop halts the sweeper and waits for an in-flight sweep to finish
+func (s *Sweeper) Stop(ctx context.Context) error {
+	close(s.stop)
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Sweeper) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		s.sweep()
+		select {
+		case <-ticker.C:
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+func (s *Sweeper) sweep() {
+	ctx, cancel := context.WithTimeout(context.Background(), s.config.Interval)
+	defer cancel()
+
+	seen := make(map[uuid.UUID]bool)
+	for status, timeout := range s.config.Timeouts {
+		if timeout.WarnAfter <= 0 {
+			continue
+		}
+		sessions, err := s.svc.ListIdleSessions(ctx, status, timeout.WarnAfter)
+		if err != nil {
+			log.Printf("Session sweeper: %v", err)
+			continue
+		}
+		for _, session := range sessions {
+			seen[session.ID] = true
+			s.handle(ctx, session, timeout)
+		}
+	}
+
+	for id := range s.blocked {
+		if !seen[id] {
+			delete(s.blocked, id)
+		}
+	}
+}
+
+// handle warns about a newly idle session, or cancels one that was already warned about and stayed idle
+func (s *Sweeper) handle(ctx context.Context, session *IdleSession, timeout IdleTimeout) {
+	idleFor := time.Since(session.LastActivity)
+
+	// A flag from before the latest order belongs to an earlier idle spell
+	if session.FlaggedAt == nil || session.FlaggedAt.Before(session.LastActivity) {
+		s.alerter.IdleSession(ctx, IdleAlert{Kind: IdleAlertWarning, Session: session, IdleFor: idleFor})
+		if err := s.svc.FlagIdleSession(ctx, session.ID); err != nil {
+			log.Printf("Session sweeper: %v", err)
+		}
+		return
+	}
+
+	if timeout.CancelAfter <= 0 || idleFor < timeout.CancelAfter {
+		return
+	}
+	if session.ServedOrders > 0 {
+		if !s.blocked[session.ID] {
+			s.blocked[session.ID] = true
+			s.alerter.IdleSession(ctx, IdleAlert{Kind: IdleAlertBlocked, Session: session, IdleFor: idleFor})
+		}
+		return
+	}
+
+	cancelled, err := s.svc.CancelIdleSession(ctx, session)
+	if err != nil {
+		log.Printf("Session sweeper: %v", err)
+		return
+	}
+	if cancelled {
+		s.alerter.IdleSession(ctx, IdleAlert{Kind: IdleAlertCancelled, Session: session, IdleFor: idleFor})
+	}
+}
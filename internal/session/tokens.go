@@ -0,0 +1,68 @@
+package session
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strconv"
+	"strings"
+
+	apperrors "restaurant/internal/errors"
+)
+
+// tableTokenPrefix doubles as the token format version
+const tableTokenPrefix = "t1"
+
+// tableTokenMACBytes truncates table token signatures so the QR code stays small; 128 bits is plenty for a
+// token that is also checked against the table's current nonce
+const tableTokenMACBytes = 16
+
+// tokenSigner signs and verifies table QR tokens with HMAC-SHA256.
+//
+// A table token is "t1.<table id>.<nonce>.<mac>". The nonce is stored on the table, so rotating it revokes
+// every printed code for that table.
+type tokenSigner struct {
+	secret []byte
+}
+
+// newTableTokenNonce returns a random nonce for a table token
+func newTableTokenNonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// signTableToken builds the token printed in a table's QR code
+func (s *tokenSigner) signTableToken(tableID int, nonce string) string {
+	payload := tableTokenPrefix + "." + strconv.Itoa(tableID) + "." + nonce
+	return payload + "." + s.mac(payload, tableTokenMACBytes)
+}
+
+// parseTableToken checks the signature of a table token and returns the table and nonce it names.
+// The caller must still compare the nonce with the table's current one.
+func (s *tokenSigner) parseTableToken(token string) (int, string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 4 || parts[0] != tableTokenPrefix {
+		return 0, "", apperrors.ErrInvalidTableToken
+	}
+	payload := strings.Join(parts[:3], ".")
+	if !hmac.Equal([]byte(parts[3]), []byte(s.mac(payload, tableTokenMACBytes))) {
+		return 0, "", apperrors.ErrInvalidTableToken
+	}
+	tableID, err := strconv.Atoi(parts[1])
+	if err != nil || tableID <= 0 {
+		return 0, "", apperrors.ErrInvalidTableToken
+	}
+	return tableID, parts[2], nil
+}
+
+// mac returns the first n bytes of the HMAC of payload, base64url encoded without padding
+func (s *tokenSigner) mac(payload string, n int) string {
+	h := hmac.New(sha256.New, s.secret)
+	h.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil)[:n])
+}
@@ -2,18 +2,48 @@ package session
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/google/uuid"
 )
 
 // CreateSessionRequest represents the request to create a session
 type CreateSessionRequest struct {
-	TableID int `json:"table_id" validate:"required,gt=0"`
+	QRToken    string `json:"qr_token" validate:"omitempty,max=200"`                       // required unless the caller may manage sessions
+	TableID    int    `json:"table_id" validate:"required_without=QRToken,omitempty,gt=0"` // must match the table in the QR token
+	GuestCount int    `json:"guest_count" validate:"omitempty,min=1,max=100"`              // optional; may not exceed the table's max covers
+}
+
+// UpdateGuestCountRequest represents the request to change the number of guests of a session
+type UpdateGuestCountRequest struct {
+	GuestCount int `json:"guest_count" validate:"required,min=1,max=100"`
+}
+
+// ReassignServerRequest represents the request to hand a session to another server
+type ReassignServerRequest struct {
+	ServerID uuid.UUID `json:"server_id" validate:"required"`
+}
+
+// CoversReportRequest represents the covers report query; dates are inclusive calendar days
+type CoversReportRequest struct {
+	From string `form:"from" validate:"omitempty,datetime=2006-01-02"`
+	To   string `form:"to" validate:"omitempty,datetime=2006-01-02"`
+}
+
+// ScanTableRequest represents a guest exchanging a scanned table QR token for a session credential
+type ScanTableRequest struct {
+	Token string `json:"token" validate:"required,max=200"`
+}
+
+// TableQRCodeRequest represents the query for rendering a table QR code
+type TableQRCodeRequest struct {
+	Size int `form:"size" validate:"omitempty,min=64,max=2048"`
 }
 
 // UpdateSessionRequest represents the request to update a session
 type UpdateSessionRequest struct {
-	Status SessionStatus `json:"status" validate:"required,oneof=active completed pending cancelled"`
+	Status  SessionStatus `json:"status" validate:"required,oneof=active completed pending cancelled"`
+	Version *int          `json:"version" validate:"omitempty,min=1"` // version being updated, if not sent in If-Match
 }
 
 // ListSessionsRequest represents the request to list sessions with pagination
@@ -27,11 +57,59 @@ type ChangeSessionTableRequest struct {
 	TableID int `json:"table_id" validate:"required,gt=0"`
 }
 
+// JoinTablesRequest represents the request to push extra tables together with a session's table
+type JoinTablesRequest struct {
+	TableIDs []int `json:"table_ids" validate:"required,min=1,max=20,dive,gt=0"`
+}
+
+// SplitPartRequest represents one table split off a combined session, with the orders that go with it
+type SplitPartRequest struct {
+	TableID    int         `json:"table_id" validate:"required,gt=0"`
+	OrderIDs   []uuid.UUID `json:"order_ids" validate:"max=200"`
+	GuestCount int         `json:"guest_count" validate:"omitempty,min=1,max=100"`
+}
+
+// SplitSessionRequest represents the request to split a combined session back into separate tables
+type SplitSessionRequest struct {
+	Parts []SplitPartRequest `json:"parts" validate:"required,min=1,max=20,dive"`
+}
+
 // ValidateCreateSession validates the create session request
 func ValidateCreateSession(req CreateSessionRequest) error {
 	return ValidateStruct(req)
 }
 
+// ValidateUpdateGuestCount validates the update guest count request
+func ValidateUpdateGuestCount(req UpdateGuestCountRequest) error {
+	return ValidateStruct(req)
+}
+
+// ValidateReassignServer validates the reassign server request
+func ValidateReassignServer(req ReassignServerRequest) error {
+	return ValidateStruct(req)
+}
+
+// ValidateCoversReport validates the covers report request
+func ValidateCoversReport(req CoversReportRequest) error {
+	if err := ValidateStruct(req); err != nil {
+		return err
+	}
+	if req.From != "" && req.To != "" && req.To < req.From {
+		return errors.New("to must not be before from")
+	}
+	return nil
+}
+
+// ValidateScanTable validates the scan table request
+func ValidateScanTable(req ScanTableRequest) error {
+	return ValidateStruct(req)
+}
+
+// ValidateTableQRCode validates the table QR code request
+func ValidateTableQRCode(req TableQRCodeRequest) error {
+	return ValidateStruct(req)
+}
+
 // ValidateUpdateSession validates the update session request
 func ValidateUpdateSession(req UpdateSessionRequest) error {
 	return ValidateStruct(req)
@@ -55,9 +133,33 @@ func ValidateSessionID(id uuid.UUID) error {
 	return nil
 }
 
-// CreateTableRequest represents the request to create a table
+// CreateTableRequest represents the request to create a table; omitted layout fields get defaults
 type CreateTableRequest struct {
-	ID int `json:"id" validate:"required,min=1"`
+	ID        int     `json:"id" validate:"required,min=1"`
+	MinCovers int     `json:"min_covers" validate:"omitempty,min=1,max=100"`
+	MaxCovers int     `json:"max_covers" validate:"omitempty,min=1,max=100"`
+	Zone      string  `json:"zone" validate:"omitempty,min=1,max=50"`
+	Shape     string  `json:"shape" validate:"omitempty,oneof=square round rectangle booth"`
+	X         float64 `json:"x" validate:"min=0"`
+	Y         float64 `json:"y" validate:"min=0"`
+	Rotation  int     `json:"rotation" validate:"min=0,max=359"`
+}
+
+// UpdateTableRequest represents a partial change to a table's capacity or layout; omitted fields are kept
+type UpdateTableRequest struct {
+	MinCovers *int     `json:"min_covers" validate:"omitempty,min=1,max=100"`
+	MaxCovers *int     `json:"max_covers" validate:"omitempty,min=1,max=100"`
+	Zone      *string  `json:"zone" validate:"omitempty,min=1,max=50"`
+	Shape     *string  `json:"shape" validate:"omitempty,oneof=square round rectangle booth"`
+	X         *float64 `json:"x" validate:"omitempty,min=0"`
+	Y         *float64 `json:"y" validate:"omitempty,min=0"`
+	Rotation  *int     `json:"rotation" validate:"omitempty,min=0,max=359"`
+}
+
+// AvailableTablesRequest represents a search for free tables, e.g. "a table for 6 in the patio"
+type AvailableTablesRequest struct {
+	PartySize int    `form:"party_size" validate:"omitempty,min=1,max=100"`
+	Zone      string `form:"zone" validate:"omitempty,max=50"`
 }
 
 // BulkCreateTablesRequest represents the request to create multiple tables in a range
@@ -68,6 +170,28 @@ type BulkCreateTablesRequest struct {
 
 // ValidateCreateTable validates the create table request
 func ValidateCreateTable(req CreateTableRequest) error {
+	if err := ValidateStruct(req); err != nil {
+		return err
+	}
+	if req.MinCovers > 0 && req.MaxCovers > 0 && req.MinCovers > req.MaxCovers {
+		return errors.New("min_covers must be less than or equal to max_covers")
+	}
+	return nil
+}
+
+// ValidateUpdateTable validates the update table request
+func ValidateUpdateTable(req UpdateTableRequest) error {
+	if err := ValidateStruct(req); err != nil {
+		return err
+	}
+	if req.MinCovers != nil && req.MaxCovers != nil && *req.MinCovers > *req.MaxCovers {
+		return errors.New("min_covers must be less than or equal to max_covers")
+	}
+	return nil
+}
+
+// ValidateAvailableTables validates the available tables request
+func ValidateAvailableTables(req AvailableTablesRequest) error {
 	return ValidateStruct(req)
 }
 
@@ -92,3 +216,40 @@ func ValidateTableID(id int) error {
 	}
 	return nil
 }
+
+// ValidateJoinTables validates the join tables request
+func ValidateJoinTables(req JoinTablesRequest) error {
+	if err := ValidateStruct(req); err != nil {
+		return err
+	}
+	seen := make(map[int]bool, len(req.TableIDs))
+	for _, id := range req.TableIDs {
+		if seen[id] {
+			return fmt.Errorf("table %d is listed more than once", id)
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
+// ValidateSplitSession validates the split session request; every table and order may appear only once
+func ValidateSplitSession(req SplitSessionRequest) error {
+	if err := ValidateStruct(req); err != nil {
+		return err
+	}
+	tables := make(map[int]bool, len(req.Parts))
+	orders := make(map[uuid.UUID]bool)
+	for _, part := range req.Parts {
+		if tables[part.TableID] {
+			return fmt.Errorf("table %d is listed more than once", part.TableID)
+		}
+		tables[part.TableID] = true
+		for _, orderID := range part.OrderIDs {
+			if orders[orderID] {
+				return fmt.Errorf("order %s is listed more than once", orderID)
+			}
+			orders[orderID] = true
+		}
+	}
+	return nil
+}
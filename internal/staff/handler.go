@@ -0,0 +1,331 @@
+package staff
+
+import (
+	"time"
+
+	"restaurant/internal/errors"
+	"restaurant/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler handles HTTP requests for staff and their shifts
+type Handler struct {
+	svc StaffService
+}
+
+// NewHandler creates a new staff handler
+func NewHandler(svc StaffService) *Handler {
+	return &Handler{svc: svc}
+}
+
+// staffPermissions declares what each staff route needs: anyone on staff can see the rota, managers change it
+var staffPermissions = middleware.RoutePermissions{
+	"GET /staff":               middleware.PermStaffRead,
+	"POST /staff":              middleware.PermStaffManage,
+	"GET /staff/shifts":        middleware.PermStaffRead,
+	"DELETE /staff/shifts/:id": middleware.PermStaffManage,
+	"GET /staff/:id":           middleware.PermStaffRead,
+	"PUT /staff/:id":           middleware.PermStaffManage,
+	"POST /staff/:id/shifts":   middleware.PermStaffManage,
+	"GET /staff/:id/tables":    middleware.PermStaffRead,
+	"GET /staff/:id/orders":    middleware.PermStaffRead,
+}
+
+// RegisterRoutes registers all staff routes with the Gin router
+func (h *Handler) RegisterRoutes(router *gin.Engine) {
+	staffGroup := router.Group("/staff", staffPermissions.Authorize())
+	{
+		staffGroup.GET("", h.ListStaff)
+		staffGroup.POST("", h.CreateStaff)
+		staffGroup.GET("/shifts", h.ListShifts)
+		staffGroup.DELETE("/shifts/:id", h.DeleteShift)
+		staffGroup.GET("/:id", h.GetStaff)
+		staffGroup.PUT("/:id", h.UpdateStaff)
+		staffGroup.POST("/:id/shifts", h.AddShift)
+		staffGroup.GET("/:id/tables", h.ListServerTables)
+		staffGroup.GET("/:id/orders", h.ListServerOrders)
+	}
+}
+
+// CreateStaff handles POST /staff
+// @Summary Add a staff member
+// @Description Create an active staff member; the role defaults to server
+// @Tags Staff
+// @Accept json
+// @Produce json
+// @Param request body CreateStaffRequest true "Staff member"
+// @Success 201 {object} Staff
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /staff [post]
+func (h *Handler) CreateStaff(c *gin.Context) {
+	var req CreateStaffRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	if err := ValidateCreateStaff(req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	member, err := h.svc.CreateStaff(c.Request.Context(), &req)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.JSON(201, member)
+}
+
+// ListStaff handles GET /staff
+// @Summary List staff
+// @Description List staff members by name
+// @Tags Staff
+// @Accept json
+// @Produce json
+// @Param include_inactive query bool false "Include inactive staff"
+// @Success 200 {array} Staff
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /staff [get]
+func (h *Handler) ListStaff(c *gin.Context) {
+	var req ListStaffRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	members, err := h.svc.ListStaff(c.Request.Context(), req.IncludeInactive)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.JSON(200, members)
+}
+
+// GetStaff handles GET /staff/:id
+// @Summary Get staff member by ID
+// @Description Retrieve a staff member
+// @Tags Staff
+// @Accept json
+// @Produce json
+// @Param id path string true "Staff ID (UUID)"
+// @Success 200 {object} Staff
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /staff/{id} [get]
+func (h *Handler) GetStaff(c *gin.Context) {
+	id, ok := middleware.UUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	member, err := h.svc.GetStaff(c.Request.Context(), id)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.JSON(200, member)
+}
+
+// UpdateStaff handles PUT /staff/:id
+// @Summary Update a staff member
+// @Description Rename a staff member, change their role or (de)activate them; omitted fields are kept
+// @Tags Staff
+// @Accept json
+// @Produce json
+// @Param id path string true "Staff ID (UUID)"
+// @Param request body UpdateStaffRequest true "Fields to change"
+// @Success 200 {object} Staff
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /staff/{id} [put]
+func (h *Handler) UpdateStaff(c *gin.Context) {
+	id, ok := middleware.UUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req UpdateStaffRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	if err := ValidateUpdateStaff(req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	member, err := h.svc.UpdateStaff(c.Request.Context(), id, &req)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.JSON(200, member)
+}
+
+// AddShift handles POST /staff/:id/shifts
+// @Summary Schedule a shift
+// @Description Assign a staff member to a section (table zone) for a shift; sessions started there during it are given to them
+// @Tags Staff
+// @Accept json
+// @Produce json
+// @Param id path string true "Staff ID (UUID)"
+// @Param request body CreateShiftRequest true "Section and shift times"
+// @Success 201 {object} Shift
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 409 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /staff/{id}/shifts [post]
+func (h *Handler) AddShift(c *gin.Context) {
+	id, ok := middleware.UUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req CreateShiftRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	if err := ValidateCreateShift(req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	shift, err := h.svc.AddShift(c.Request.Context(), id, &req)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.JSON(201, shift)
+}
+
+// ListShifts handles GET /staff/shifts
+// @Summary List shifts for a day
+// @Description Section assignments overlapping a day, in start order
+// @Tags Staff
+// @Accept json
+// @Produce json
+// @Param date query string false "Date as YYYY-MM-DD (default today)"
+// @Success 200 {array} Shift
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /staff/shifts [get]
+func (h *Handler) ListShifts(c *gin.Context) {
+	var req ListShiftsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	if err := ValidateListShifts(req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	date := time.Now()
+	if req.Date != "" {
+		date, _ = time.Parse("2006-01-02", req.Date) // format already validated
+	}
+
+	shifts, err := h.svc.ListShifts(c.Request.Context(), date)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.JSON(200, shifts)
+}
+
+// DeleteShift handles DELETE /staff/shifts/:id
+// @Summary Delete a shift
+// @Description Remove a section assignment; sessions already assigned keep their server
+// @Tags Staff
+// @Accept json
+// @Produce json
+// @Param id path string true "Shift ID (UUID)"
+// @Success 204 "No Content"
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /staff/shifts/{id} [delete]
+func (h *Handler) DeleteShift(c *gin.Context) {
+	id, ok := middleware.UUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.svc.DeleteShift(c.Request.Context(), id); err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.Status(204)
+}
+
+// ListServerTables handles GET /staff/:id/tables
+// @Summary A server's tables
+// @Description Active and pending sessions the server is looking after, with their joined tables
+// @Tags Staff
+// @Accept json
+// @Produce json
+// @Param id path string true "Staff ID (UUID)"
+// @Success 200 {array} session.Session
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /staff/{id}/tables [get]
+func (h *Handler) ListServerTables(c *gin.Context) {
+	id, ok := middleware.UUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	sessions, err := h.svc.ListServerTables(c.Request.Context(), id)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.JSON(200, sessions)
+}
+
+// ListServerOrders handles GET /staff/:id/orders
+// @Summary A server's ready orders
+// @Description Orders ready at the pass for the server to take to their tables, oldest first
+// @Tags Staff
+// @Accept json
+// @Produce json
+// @Param id path string true "Staff ID (UUID)"
+// @Success 200 {array} order.Order
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /staff/{id}/orders [get]
+func (h *Handler) ListServerOrders(c *gin.Context) {
+	id, ok := middleware.UUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	orders, err := h.svc.ListServerOrders(c.Request.Context(), id)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.JSON(200, orders)
+}
@@ -0,0 +1,38 @@
+package staff
+
+import (
+	"time"
+
+	"restaurant/internal/middleware"
+
+	"github.com/google/uuid"
+)
+
+// Role represents what a staff member does on the floor; it decides what they are permitted to do
+type Role string
+
+const (
+	RoleServer  Role = middleware.RoleServer
+	RoleHost    Role = middleware.RoleHost
+	RoleKitchen Role = middleware.RoleKitchen
+	RoleManager Role = middleware.RoleManager
+)
+
+// Staff is a member of the floor team
+type Staff struct {
+	ID        uuid.UUID `json:"id"`         // unique staff ID
+	Name      string    `json:"name"`       // display name
+	Role      Role      `json:"role"`       // e.g., RoleServer, RoleHost
+	Active    bool      `json:"active"`     // inactive staff keep their history but get no new shifts or tables
+	CreatedAt time.Time `json:"created_at"` // when the record was created
+}
+
+// Shift assigns a staff member to a section of the floor for a span of time. Sections are table zones.
+type Shift struct {
+	ID        uuid.UUID `json:"id"`         // unique shift ID
+	StaffID   uuid.UUID `json:"staff_id"`   // staff member working the shift
+	Zone      string    `json:"zone"`       // section covered, matching the zone of its tables
+	StartsAt  time.Time `json:"starts_at"`  // start of the shift
+	EndsAt    time.Time `json:"ends_at"`    // end of the shift, exclusive
+	CreatedAt time.Time `json:"created_at"` // when the shift was scheduled
+}
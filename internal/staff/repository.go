@@ -0,0 +1,204 @@
+package staff
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"restaurant/internal/errors"
+
+	"github.com/google/uuid"
+)
+
+// staffColumns is the column list scanned by scanStaff
+const staffColumns = "id, name, role, active, created_at"
+
+// shiftColumns is the column list scanned by scanShift
+const shiftColumns = "id, staff_id, zone, starts_at, ends_at, created_at"
+
+// Repository defines methods for staff database operations
+type Repository interface {
+	// CreateStaff inserts a new staff member
+	CreateStaff(ctx context.Context, s *Staff) error
+
+	// GetStaff retrieves a staff member by ID
+	GetStaff(ctx context.Context, id uuid.UUID) (*Staff, error)
+
+	// ListStaff lists staff members by name, optionally including inactive ones
+	ListStaff(ctx context.Context, includeInactive bool) ([]*Staff, error)
+
+	// UpdateStaff rewrites the name, role and active flag of a staff member
+	UpdateStaff(ctx context.Context, s *Staff) error
+
+	// CreateShift inserts a shift unless it overlaps another shift of the same staff member
+	CreateShift(ctx context.Context, shift *Shift) error
+
+	// GetShift retrieves a shift by ID
+	GetShift(ctx context.Context, id uuid.UUID) (*Shift, error)
+
+	// ListShifts lists shifts overlapping [from, to) in start order
+	ListShifts(ctx context.Context, from time.Time, to time.Time) ([]*Shift, error)
+
+	// DeleteShift removes a shift
+	DeleteShift(ctx context.Context, id uuid.UUID) error
+}
+
+// postgresRepository implements Repository using PostgreSQL
+type postgresRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresRepository creates a new PostgreSQL-based staff repository
+func NewPostgresRepository(db *sql.DB) Repository {
+	return &postgresRepository{db: db}
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanStaff(row rowScanner) (*Staff, error) {
+	var s Staff
+	var role string
+	if err := row.Scan(&s.ID, &s.Name, &role, &s.Active, &s.CreatedAt); err != nil {
+		return nil, err
+	}
+	s.Role = Role(role)
+	return &s, nil
+}
+
+func scanShift(row rowScanner) (*Shift, error) {
+	var shift Shift
+	if err := row.Scan(&shift.ID, &shift.StaffID, &shift.Zone, &shift.StartsAt, &shift.EndsAt, &shift.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &shift, nil
+}
+
+// CreateStaff inserts a new staff member
+func (r *postgresRepository) CreateStaff(ctx context.Context, s *Staff) error {
+	_, err := r.db.ExecContext(ctx, "INSERT INTO staff (id, name, role, active, created_at) VALUES ($1, $2, $3, $4, $5)",
+		s.ID, s.Name, s.Role, s.Active, s.CreatedAt)
+	return err
+}
+
+// GetStaff retrieves a staff member by ID
+func (r *postgresRepository) GetStaff(ctx context.Context, id uuid.UUID) (*Staff, error) {
+	s, err := scanStaff(r.db.QueryRowContext(ctx, "SELECT "+staffColumns+" FROM staff WHERE id = $1", id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ErrStaffNotFound
+		}
+		return nil, errors.NewInternalError("failed to get staff member", err)
+	}
+	return s, nil
+}
+
+// ListStaff lists staff members by name, optionally including inactive ones
+func (r *postgresRepository) ListStaff(ctx context.Context, includeInactive bool) ([]*Staff, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+staffColumns+" FROM staff WHERE $1 OR active ORDER BY name, id", includeInactive)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := []*Staff{}
+	for rows.Next() {
+		s, err := scanStaff(rows)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// UpdateStaff rewrites the name, role and active flag of a staff member
+func (r *postgresRepository) UpdateStaff(ctx context.Context, s *Staff) error {
+	result, err := r.db.ExecContext(ctx, "UPDATE staff SET name = $1, role = $2, active = $3 WHERE id = $4", s.Name, s.Role, s.Active, s.ID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.ErrStaffNotFound
+	}
+	return nil
+}
+
+// CreateShift inserts a shift unless it overlaps another shift of the same staff member
+func (r *postgresRepository) CreateShift(ctx context.Context, shift *Shift) error {
+	result, err := r.db.ExecContext(ctx, `INSERT INTO staff_shifts (id, staff_id, zone, starts_at, ends_at, created_at)
+		SELECT $1, $2, $3, $4::timestamptz, $5::timestamptz, $6::timestamptz
+		WHERE NOT EXISTS (
+			SELECT 1 FROM staff_shifts WHERE staff_id = $2 AND starts_at < $5 AND ends_at > $4
+		)`,
+		shift.ID, shift.StaffID, shift.Zone, shift.StartsAt, shift.EndsAt, shift.CreatedAt)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.NewConflictError("shift overlaps another shift of the same staff member")
+	}
+	return nil
+}
+
+// GetShift retrieves a shift by ID
+func (r *postgresRepository) GetShift(ctx context.Context, id uuid.UUID) (*Shift, error) {
+	shift, err := scanShift(r.db.QueryRowContext(ctx, "SELECT "+shiftColumns+" FROM staff_shifts WHERE id = $1", id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ErrShiftNotFound
+		}
+		return nil, errors.NewInternalError("failed to get shift", err)
+	}
+	return shift, nil
+}
+
+// ListShifts lists shifts overlapping [from, to) in start order
+func (r *postgresRepository) ListShifts(ctx context.Context, from time.Time, to time.Time) ([]*Shift, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+shiftColumns+" FROM staff_shifts WHERE starts_at < $2 AND ends_at > $1 ORDER BY starts_at, zone, id", from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	shifts := []*Shift{}
+	for rows.Next() {
+		shift, err := scanShift(rows)
+		if err != nil {
+			return nil, err
+		}
+		shifts = append(shifts, shift)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return shifts, nil
+}
+
+// DeleteShift removes a shift
+func (r *postgresRepository) DeleteShift(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM staff_shifts WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.ErrShiftNotFound
+	}
+	return nil
+}
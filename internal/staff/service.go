@@ -0,0 +1,160 @@
+package staff
+
+import (
+	"context"
+	apperrors "restaurant/internal/errors"
+	"restaurant/internal/order"
+	"restaurant/internal/session"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StaffService defines business logic for staff, their shifts and their per-server views
+type StaffService interface {
+	CreateStaff(ctx context.Context, req *CreateStaffRequest) (*Staff, error)
+	GetStaff(ctx context.Context, id uuid.UUID) (*Staff, error)
+	ListStaff(ctx context.Context, includeInactive bool) ([]*Staff, error)
+	UpdateStaff(ctx context.Context, id uuid.UUID, req *UpdateStaffRequest) (*Staff, error)
+
+	// Shifts
+	AddShift(ctx context.Context, staffID uuid.UUID, req *CreateShiftRequest) (*Shift, error)
+	ListShifts(ctx context.Context, date time.Time) ([]*Shift, error)
+	DeleteShift(ctx context.Context, id uuid.UUID) error
+
+	// Per-server views
+	ListServerTables(ctx context.Context, id uuid.UUID) ([]*session.Session, error)
+	ListServerOrders(ctx context.Context, id uuid.UUID) ([]*order.Order, error)
+}
+
+// staffService implements StaffService
+type staffService struct {
+	repo           Repository
+	sessionService session.SessionService
+	orderService   order.OrderService
+}
+
+// NewService creates a new staff service
+func NewService(repo Repository, sessionService session.SessionService, orderService order.OrderService) StaffService {
+	return &staffService{repo: repo, sessionService: sessionService, orderService: orderService}
+}
+
+// CreateStaff adds an active staff member; the role defaults to server
+func (s *staffService) CreateStaff(ctx context.Context, req *CreateStaffRequest) (*Staff, error) {
+	// Shape validation (name length, role) already done by handler using ValidateStruct
+	member := &Staff{
+		ID:        uuid.New(),
+		Name:      strings.TrimSpace(req.Name),
+		Role:      req.Role,
+		Active:    true,
+		CreatedAt: time.Now(),
+	}
+	if member.Role == "" {
+		member.Role = RoleServer
+	}
+	if err := s.repo.CreateStaff(ctx, member); err != nil {
+		return nil, apperrors.WrapError(500, "failed to create staff member", err)
+	}
+	return member, nil
+}
+
+// GetStaff retrieves a staff member by ID
+func (s *staffService) GetStaff(ctx context.Context, id uuid.UUID) (*Staff, error) {
+	member, err := s.repo.GetStaff(ctx, id)
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to retrieve staff member", err)
+	}
+	return member, nil
+}
+
+// ListStaff lists staff members by name
+func (s *staffService) ListStaff(ctx context.Context, includeInactive bool) ([]*Staff, error) {
+	members, err := s.repo.ListStaff(ctx, includeInactive)
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to list staff", err)
+	}
+	return members, nil
+}
+
+// UpdateStaff renames a staff member, changes their role or (de)activates them. Sessions they already look
+// after stay assigned until reassigned; deactivated staff and non-servers just stop receiving new ones.
+func (s *staffService) UpdateStaff(ctx context.Context, id uuid.UUID, req *UpdateStaffRequest) (*Staff, error) {
+	member, err := s.GetStaff(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if name := strings.TrimSpace(req.Name); name != "" {
+		member.Name = name
+	}
+	if req.Role != "" {
+		member.Role = req.Role
+	}
+	if req.Active != nil {
+		member.Active = *req.Active
+	}
+	if err := s.repo.UpdateStaff(ctx, member); err != nil {
+		return nil, apperrors.WrapError(500, "failed to update staff member", err)
+	}
+	return member, nil
+}
+
+// AddShift assigns an active staff member to a section for a shift. Sections are table zones, so the zone
+// is normalised the same way table zones are.
+func (s *staffService) AddShift(ctx context.Context, staffID uuid.UUID, req *CreateShiftRequest) (*Shift, error) {
+	// Shape validation (zone, shift bounds) already done by handler using ValidateCreateShift
+	member, err := s.GetStaff(ctx, staffID)
+	if err != nil {
+		return nil, err
+	}
+	if !member.Active {
+		return nil, apperrors.NewConflictError("inactive staff cannot be given shifts")
+	}
+
+	shift := &Shift{
+		ID:        uuid.New(),
+		StaffID:   staffID,
+		Zone:      strings.ToLower(strings.TrimSpace(req.Zone)),
+		StartsAt:  req.StartsAt,
+		EndsAt:    req.EndsAt,
+		CreatedAt: time.Now(),
+	}
+	if err := s.repo.CreateShift(ctx, shift); err != nil {
+		return nil, apperrors.WrapError(500, "failed to create shift", err)
+	}
+	return shift, nil
+}
+
+// ListShifts lists the shifts overlapping the calendar day of date in the server's time zone
+func (s *staffService) ListShifts(ctx context.Context, date time.Time) ([]*Shift, error) {
+	dayStart := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.Local)
+	shifts, err := s.repo.ListShifts(ctx, dayStart, dayStart.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to list shifts", err)
+	}
+	return shifts, nil
+}
+
+// DeleteShift removes a shift. Sessions already assigned during it keep their server.
+func (s *staffService) DeleteShift(ctx context.Context, id uuid.UUID) error {
+	if err := s.repo.DeleteShift(ctx, id); err != nil {
+		return apperrors.WrapError(500, "failed to delete shift", err)
+	}
+	return nil
+}
+
+// ListServerTables lists the live sessions, and so the tables, a server is looking after
+func (s *staffService) ListServerTables(ctx context.Context, id uuid.UUID) ([]*session.Session, error) {
+	if _, err := s.GetStaff(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.sessionService.ListServerSessions(ctx, id)
+}
+
+// ListServerOrders lists the orders ready at the pass for a server to take to their tables
+func (s *staffService) ListServerOrders(ctx context.Context, id uuid.UUID) ([]*order.Order, error) {
+	if _, err := s.GetStaff(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.orderService.ListReadyOrdersByServer(ctx, id)
+}
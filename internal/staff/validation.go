@@ -0,0 +1,68 @@
+package staff
+
+import (
+	"errors"
+	"time"
+)
+
+// CreateStaffRequest represents the request to add a staff member
+type CreateStaffRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=100"`
+	Role Role   `json:"role" validate:"omitempty,oneof=server host kitchen manager"`
+}
+
+// UpdateStaffRequest represents a partial change to a staff member; omitted fields are kept
+type UpdateStaffRequest struct {
+	Name   string `json:"name" validate:"omitempty,min=1,max=100"`
+	Role   Role   `json:"role" validate:"omitempty,oneof=server host kitchen manager"`
+	Active *bool  `json:"active"`
+}
+
+// ListStaffRequest represents the staff list query
+type ListStaffRequest struct {
+	IncludeInactive bool `form:"include_inactive"`
+}
+
+// CreateShiftRequest represents the request to assign a staff member to a section for a shift
+type CreateShiftRequest struct {
+	Zone     string    `json:"zone" validate:"required,min=1,max=50"`
+	StartsAt time.Time `json:"starts_at" validate:"required"`
+	EndsAt   time.Time `json:"ends_at" validate:"required"`
+}
+
+// ListShiftsRequest represents the shift rota query for one day
+type ListShiftsRequest struct {
+	Date string `form:"date" validate:"omitempty,datetime=2006-01-02"`
+}
+
+// maxShiftLength bounds a single shift so a typo in the end date cannot hold a section for days
+const maxShiftLength = 16 * time.Hour
+
+// ValidateCreateStaff validates the create staff request
+func ValidateCreateStaff(req CreateStaffRequest) error {
+	return ValidateStruct(req)
+}
+
+// ValidateUpdateStaff validates the update staff request
+func ValidateUpdateStaff(req UpdateStaffRequest) error {
+	return ValidateStruct(req)
+}
+
+// ValidateCreateShift validates the create shift request
+func ValidateCreateShift(req CreateShiftRequest) error {
+	if err := ValidateStruct(req); err != nil {
+		return err
+	}
+	if !req.EndsAt.After(req.StartsAt) {
+		return errors.New("ends_at must be after starts_at")
+	}
+	if req.EndsAt.Sub(req.StartsAt) > maxShiftLength {
+		return errors.New("shifts can last at most 16 hours")
+	}
+	return nil
+}
+
+// ValidateListShifts validates the list shifts request
+func ValidateListShifts(req ListShiftsRequest) error {
+	return ValidateStruct(req)
+}
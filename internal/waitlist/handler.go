@@ -0,0 +1,249 @@
+package waitlist
+
+import (
+	"restaurant/internal/errors"
+	"restaurant/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler handles HTTP requests for the waitlist
+type Handler struct {
+	svc WaitlistService
+}
+
+// NewHandler creates a new waitlist handler
+func NewHandler(svc WaitlistService) *Handler {
+	return &Handler{svc: svc}
+}
+
+// waitlistPermissions declares what each waitlist route needs
+var waitlistPermissions = middleware.RoutePermissions{
+	"GET /waitlist":             middleware.PermWaitlistManage,
+	"POST /waitlist":            middleware.PermWaitlistManage,
+	"GET /waitlist/estimate":    middleware.PermWaitlistManage,
+	"GET /waitlist/:id":         middleware.PermWaitlistManage,
+	"POST /waitlist/:id/notify": middleware.PermWaitlistManage,
+	"POST /waitlist/:id/seat":   middleware.PermWaitlistManage,
+	"DELETE /waitlist/:id":      middleware.PermWaitlistManage,
+}
+
+// RegisterRoutes registers all waitlist routes with the Gin router
+func (h *Handler) RegisterRoutes(router *gin.Engine) {
+	waitlistGroup := router.Group("/waitlist", waitlistPermissions.Authorize())
+	{
+		waitlistGroup.GET("", h.ListQueue)
+		waitlistGroup.POST("", h.AddParty)
+		waitlistGroup.GET("/estimate", h.EstimateWait)
+		waitlistGroup.GET("/:id", h.GetEntry)
+		waitlistGroup.POST("/:id/notify", h.NotifyParty)
+		waitlistGroup.POST("/:id/seat", h.SeatParty)
+		waitlistGroup.DELETE("/:id", h.RemoveParty)
+	}
+}
+
+// AddParty handles POST /waitlist
+// @Summary Add a walk-in party
+// @Description Put a party at the back of the waitlist with a wait quoted from the tables that seat it
+// @Tags Waitlist
+// @Accept json
+// @Produce json
+// @Param request body CreateEntryRequest true "Waitlist entry"
+// @Success 201 {object} Entry
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /waitlist [post]
+func (h *Handler) AddParty(c *gin.Context) {
+	var req CreateEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	if err := ValidateCreateEntry(req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	entry, err := h.svc.AddParty(c.Request.Context(), &req)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.JSON(201, entry)
+}
+
+// ListQueue handles GET /waitlist
+// @Summary List the waitlist
+// @Description Parties still waiting or notified, in arrival order with their positions
+// @Tags Waitlist
+// @Accept json
+// @Produce json
+// @Success 200 {array} Entry
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /waitlist [get]
+func (h *Handler) ListQueue(c *gin.Context) {
+	queue, err := h.svc.ListQueue(c.Request.Context())
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.JSON(200, queue)
+}
+
+// EstimateWait handles GET /waitlist/estimate
+// @Summary Estimate the wait
+// @Description Quote the wait a party of the given size would get if it joined the waitlist now
+// @Tags Waitlist
+// @Accept json
+// @Produce json
+// @Param party_size query int true "Number of guests"
+// @Success 200 {object} WaitEstimate
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /waitlist/estimate [get]
+func (h *Handler) EstimateWait(c *gin.Context) {
+	var req EstimateRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	if err := ValidateEstimate(req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	estimate, err := h.svc.EstimateWait(c.Request.Context(), req.PartySize)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.JSON(200, estimate)
+}
+
+// GetEntry handles GET /waitlist/:id
+// @Summary Get waitlist entry by ID
+// @Description Retrieve a waitlist entry, with its position while the party is still queued
+// @Tags Waitlist
+// @Accept json
+// @Produce json
+// @Param id path string true "Waitlist entry ID (UUID)"
+// @Success 200 {object} Entry
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /waitlist/{id} [get]
+func (h *Handler) GetEntry(c *gin.Context) {
+	id, ok := middleware.UUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	entry, err := h.svc.GetEntry(c.Request.Context(), id)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.JSON(200, entry)
+}
+
+// NotifyParty handles POST /waitlist/:id/notify
+// @Summary Notify a party
+// @Description Record that the party was told its table is ready
+// @Tags Waitlist
+// @Accept json
+// @Produce json
+// @Param id path string true "Waitlist entry ID (UUID)"
+// @Success 200 {object} Entry
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 409 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /waitlist/{id}/notify [post]
+func (h *Handler) NotifyParty(c *gin.Context) {
+	id, ok := middleware.UUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	entry, err := h.svc.NotifyParty(c.Request.Context(), id)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.JSON(200, entry)
+}
+
+// SeatParty handles POST /waitlist/:id/seat
+// @Summary Seat a party from the waitlist
+// @Description Start a dining session on the given table and record the party's actual wait
+// @Tags Waitlist
+// @Accept json
+// @Produce json
+// @Param id path string true "Waitlist entry ID (UUID)"
+// @Param request body SeatEntryRequest true "Table to seat the party at"
+// @Success 200 {object} Entry
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 409 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /waitlist/{id}/seat [post]
+func (h *Handler) SeatParty(c *gin.Context) {
+	id, ok := middleware.UUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var req SeatEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	if err := ValidateSeatEntry(req); err != nil {
+		middleware.HandleError(c, errors.NewValidationError(err.Error()))
+		return
+	}
+
+	entry, err := h.svc.SeatParty(c.Request.Context(), id, req.TableID)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.JSON(200, entry)
+}
+
+// RemoveParty handles DELETE /waitlist/:id
+// @Summary Remove a party from the waitlist
+// @Description Take a party off the list; the entry is kept for history
+// @Tags Waitlist
+// @Accept json
+// @Produce json
+// @Param id path string true "Waitlist entry ID (UUID)"
+// @Success 200 {object} Entry
+// @Failure 400 {object} middleware.ErrorResponse
+// @Failure 404 {object} middleware.ErrorResponse
+// @Failure 409 {object} middleware.ErrorResponse
+// @Failure 500 {object} middleware.ErrorResponse
+// @Router /waitlist/{id} [delete]
+func (h *Handler) RemoveParty(c *gin.Context) {
+	id, ok := middleware.UUIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	entry, err := h.svc.RemoveParty(c.Request.Context(), id)
+	if err != nil {
+		middleware.HandleError(c, err)
+		return
+	}
+
+	c.JSON(200, entry)
+}
@@ -0,0 +1,61 @@
+package waitlist
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EntryStatus represents the possible states of a waitlist entry
+type EntryStatus string
+
+const (
+	StatusWaiting  EntryStatus = "waiting"
+	StatusNotified EntryStatus = "notified"
+	StatusSeated   EntryStatus = "seated"
+	StatusRemoved  EntryStatus = "removed"
+)
+
+// Entry is a walk-in party waiting for a table
+type Entry struct {
+	ID                uuid.UUID   `json:"id"`                            // unique entry ID
+	PartySize         int         `json:"party_size"`                    // number of guests
+	GuestName         string      `json:"guest_name"`                    // name the party is called by
+	GuestPhone        string      `json:"guest_phone"`                   // contact phone for notifications, may be empty
+	Notes             string      `json:"notes"`                         // free-form notes such as high chairs or seating preferences
+	Status            EntryStatus `json:"status"`                        // e.g., StatusWaiting, StatusNotified
+	Position          int         `json:"position,omitempty"`            // 1-based place in the live queue, 0 once seated or removed
+	QuotedWaitMinutes int         `json:"quoted_wait_minutes"`           // wait quoted to the party when it joined
+	ActualWaitMinutes *int        `json:"actual_wait_minutes,omitempty"` // time from joining to being seated, nil until seated
+	TableID           *int        `json:"table_id,omitempty"`            // table the party was seated at
+	SessionID         *uuid.UUID  `json:"session_id,omitempty"`          // session created when the party was seated
+	CreatedAt         time.Time   `json:"created_at"`                    // when the party joined the waitlist
+	NotifiedAt        *time.Time  `json:"notified_at,omitempty"`         // when the party was told its table is ready
+	SeatedAt          *time.Time  `json:"seated_at,omitempty"`           // when the party was seated
+	RemovedAt         *time.Time  `json:"removed_at,omitempty"`          // when the party left or was taken off the list
+}
+
+// IsQueued reports whether the party is still waiting for a table
+func (e *Entry) IsQueued() bool {
+	return e.Status == StatusWaiting || e.Status == StatusNotified
+}
+
+// WaitEstimate is the quoted wait for a party of a given size joining the queue now
+type WaitEstimate struct {
+	PartySize    int `json:"party_size"`
+	PartiesAhead int `json:"parties_ahead"` // queued parties that compete for the same tables
+	WaitMinutes  int `json:"wait_minutes"`
+}
+
+// TableHold is the start of the live session holding a table
+type TableHold struct {
+	TableID int
+	Since   time.Time
+}
+
+// WaitAccuracy sums the quoted and actual waits of recently seated parties
+type WaitAccuracy struct {
+	Samples       int
+	QuotedMinutes int
+	ActualMinutes int
+}
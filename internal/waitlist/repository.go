@@ -0,0 +1,209 @@
+package waitlist
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"restaurant/internal/errors"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// entryColumns is the column list scanned by scanEntry
+const entryColumns = "id, party_size, guest_name, guest_phone, notes, status, quoted_wait_minutes, actual_wait_minutes, table_id, session_id, created_at, notified_at, seated_at, removed_at"
+
+// Repository defines methods for waitlist database operations
+type Repository interface {
+	// CreateEntry inserts a new waitlist entry
+	CreateEntry(ctx context.Context, e *Entry) error
+
+	// GetEntry retrieves an entry by ID
+	GetEntry(ctx context.Context, id uuid.UUID) (*Entry, error)
+
+	// ListQueue lists waiting and notified entries in arrival order
+	ListQueue(ctx context.Context) ([]*Entry, error)
+
+	// MarkNotified records that a waiting party was told its table is ready
+	MarkNotified(ctx context.Context, id uuid.UUID, at time.Time) error
+
+	// MarkSeated records the table, session and actual wait of a queued party
+	MarkSeated(ctx context.Context, id uuid.UUID, tableID int, sessionID uuid.UUID, at time.Time) error
+
+	// MarkRemoved takes a queued party off the list
+	MarkRemoved(ctx context.Context, id uuid.UUID, at time.Time) error
+
+	// ListTableHolds lists the tables held by active or pending sessions, including joined tables
+	ListTableHolds(ctx context.Context) ([]TableHold, error)
+
+	// AverageTurnTime returns the mean length of sessions completed on the tables since the given time,
+	// or zero if there are none
+	AverageTurnTime(ctx context.Context, tableIDs []int, since time.Time) (time.Duration, error)
+
+	// RecentWaitAccuracy sums quoted and actual waits over the last limit parties seated with a quote
+	RecentWaitAccuracy(ctx context.Context, limit int) (WaitAccuracy, error)
+}
+
+// postgresRepository implements Repository using PostgreSQL
+type postgresRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresRepository creates a new PostgreSQL-based waitlist repository
+func NewPostgresRepository(db *sql.DB) Repository {
+	return &postgresRepository{db: db}
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanEntry(row rowScanner) (*Entry, error) {
+	var e Entry
+	var status string
+	var actualWait, tableID sql.NullInt64
+	err := row.Scan(&e.ID, &e.PartySize, &e.GuestName, &e.GuestPhone, &e.Notes, &status, &e.QuotedWaitMinutes,
+		&actualWait, &tableID, &e.SessionID, &e.CreatedAt, &e.NotifiedAt, &e.SeatedAt, &e.RemovedAt)
+	if err != nil {
+		return nil, err
+	}
+	e.Status = EntryStatus(status)
+	if actualWait.Valid {
+		minutes := int(actualWait.Int64)
+		e.ActualWaitMinutes = &minutes
+	}
+	if tableID.Valid {
+		id := int(tableID.Int64)
+		e.TableID = &id
+	}
+	return &e, nil
+}
+
+// CreateEntry inserts a new waitlist entry
+func (r *postgresRepository) CreateEntry(ctx context.Context, e *Entry) error {
+	_, err := r.db.ExecContext(ctx, `INSERT INTO waitlist_entries (id, party_size, guest_name, guest_phone, notes, status, quoted_wait_minutes, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		e.ID, e.PartySize, e.GuestName, e.GuestPhone, e.Notes, e.Status, e.QuotedWaitMinutes, e.CreatedAt)
+	return err
+}
+
+// GetEntry retrieves an entry by ID
+func (r *postgresRepository) GetEntry(ctx context.Context, id uuid.UUID) (*Entry, error) {
+	e, err := scanEntry(r.db.QueryRowContext(ctx, "SELECT "+entryColumns+" FROM waitlist_entries WHERE id = $1", id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.ErrWaitlistEntryNotFound
+		}
+		return nil, errors.NewInternalError("failed to get waitlist entry", err)
+	}
+	return e, nil
+}
+
+// ListQueue lists waiting and notified entries in arrival order
+func (r *postgresRepository) ListQueue(ctx context.Context) ([]*Entry, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+entryColumns+" FROM waitlist_entries WHERE status IN ('waiting', 'notified') ORDER BY created_at, id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []*Entry{}
+	for rows.Next() {
+		e, err := scanEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// MarkNotified moves a waiting entry to notified; a party can be notified again if the first call went unanswered
+func (r *postgresRepository) MarkNotified(ctx context.Context, id uuid.UUID, at time.Time) error {
+	return r.transition(ctx, "UPDATE waitlist_entries SET status = 'notified', notified_at = $1 WHERE id = $2 AND status IN ('waiting', 'notified')",
+		"only queued parties can be notified", at, id)
+}
+
+// MarkSeated moves a queued entry to seated, storing the actual wait in whole minutes
+func (r *postgresRepository) MarkSeated(ctx context.Context, id uuid.UUID, tableID int, sessionID uuid.UUID, at time.Time) error {
+	return r.transition(ctx, `UPDATE waitlist_entries SET status = 'seated', table_id = $1, session_id = $2, seated_at = $3,
+		actual_wait_minutes = GREATEST(0, FLOOR(EXTRACT(EPOCH FROM $3::timestamptz - created_at) / 60))::INTEGER
+		WHERE id = $4 AND status IN ('waiting', 'notified')`,
+		"only queued parties can be seated", tableID, sessionID, at, id)
+}
+
+// MarkRemoved moves a queued entry to removed
+func (r *postgresRepository) MarkRemoved(ctx context.Context, id uuid.UUID, at time.Time) error {
+	return r.transition(ctx, "UPDATE waitlist_entries SET status = 'removed', removed_at = $1 WHERE id = $2 AND status IN ('waiting', 'notified')",
+		"only queued parties can be removed", at, id)
+}
+
+// transition runs a conditional status update, reporting a conflict if the entry was no longer queued
+func (r *postgresRepository) transition(ctx context.Context, query string, conflictMessage string, args ...interface{}) error {
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.NewConflictError(conflictMessage)
+	}
+	return nil
+}
+
+// ListTableHolds lists the earliest live session start for each occupied table
+func (r *postgresRepository) ListTableHolds(ctx context.Context) ([]TableHold, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT table_id, MIN(created_at) FROM session_table_assignments
+		WHERE status IN ('active', 'pending') GROUP BY table_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var holds []TableHold
+	for rows.Next() {
+		var h TableHold
+		if err := rows.Scan(&h.TableID, &h.Since); err != nil {
+			return nil, err
+		}
+		holds = append(holds, h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return holds, nil
+}
+
+// AverageTurnTime averages completed session lengths on the given tables
+func (r *postgresRepository) AverageTurnTime(ctx context.Context, tableIDs []int, since time.Time) (time.Duration, error) {
+	var seconds sql.NullFloat64
+	err := r.db.QueryRowContext(ctx, `SELECT AVG(EXTRACT(EPOCH FROM completed_at - created_at)) FROM sessions
+		WHERE status = 'completed' AND completed_at IS NOT NULL AND completed_at > created_at
+			AND created_at >= $1 AND table_id = ANY($2)`,
+		since, pq.Array(tableIDs)).Scan(&seconds)
+	if err != nil {
+		return 0, err
+	}
+	if !seconds.Valid {
+		return 0, nil
+	}
+	return time.Duration(seconds.Float64 * float64(time.Second)), nil
+}
+
+// RecentWaitAccuracy sums quoted and actual waits of recently seated parties that were given a quote
+func (r *postgresRepository) RecentWaitAccuracy(ctx context.Context, limit int) (WaitAccuracy, error) {
+	var a WaitAccuracy
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*), COALESCE(SUM(quoted_wait_minutes), 0), COALESCE(SUM(actual_wait_minutes), 0) FROM (
+		SELECT quoted_wait_minutes, actual_wait_minutes FROM waitlist_entries
+		WHERE status = 'seated' AND quoted_wait_minutes > 0 AND actual_wait_minutes IS NOT NULL
+		ORDER BY seated_at DESC LIMIT $1
+	) recent`, limit).Scan(&a.Samples, &a.QuotedMinutes, &a.ActualMinutes)
+	return a, err
+}
@@ -0,0 +1,264 @@
+package waitlist
+
+import (
+	"context"
+	"fmt"
+	"math"
+	apperrors "restaurant/internal/errors"
+	"restaurant/internal/session"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Config holds waitlist estimation configuration
+type Config struct {
+	DefaultTurnTime    time.Duration // assumed session length when no completed sessions are on record
+	TurnTimeWindow     time.Duration // how far back completed sessions are averaged into the turn time
+	CalibrationSamples int           // number of recently seated parties whose actual waits adjust new quotes
+	MinCalibration     int           // seated parties needed before quotes are adjusted at all
+}
+
+// DefaultConfig returns the waitlist configuration used when none is provided
+func DefaultConfig() Config {
+	return Config{
+		DefaultTurnTime:    time.Hour,
+		TurnTimeWindow:     30 * 24 * time.Hour,
+		CalibrationSamples: 50,
+		MinCalibration:     5,
+	}
+}
+
+// WaitlistService defines business logic for the walk-in queue
+type WaitlistService interface {
+	AddParty(ctx context.Context, req *CreateEntryRequest) (*Entry, error)
+	GetEntry(ctx context.Context, id uuid.UUID) (*Entry, error)
+	ListQueue(ctx context.Context) ([]*Entry, error)
+	EstimateWait(ctx context.Context, partySize int) (*WaitEstimate, error)
+	NotifyParty(ctx context.Context, id uuid.UUID) (*Entry, error)
+	SeatParty(ctx context.Context, id uuid.UUID, tableID int) (*Entry, error)
+	RemoveParty(ctx context.Context, id uuid.UUID) (*Entry, error)
+}
+
+// waitlistService implements WaitlistService
+type waitlistService struct {
+	repo           Repository
+	sessionService session.SessionService
+	config         Config
+}
+
+// NewService creates a new waitlist service
+func NewService(repo Repository, sessionService session.SessionService, config Config) WaitlistService {
+	defaults := DefaultConfig()
+	if config.DefaultTurnTime <= 0 {
+		config.DefaultTurnTime = defaults.DefaultTurnTime
+	}
+	if config.TurnTimeWindow <= 0 {
+		config.TurnTimeWindow = defaults.TurnTimeWindow
+	}
+	if config.CalibrationSamples <= 0 {
+		config.CalibrationSamples = defaults.CalibrationSamples
+	}
+	if config.MinCalibration <= 0 {
+		config.MinCalibration = defaults.MinCalibration
+	}
+	return &waitlistService{repo: repo, sessionService: sessionService, config: config}
+}
+
+// AddParty puts a walk-in party at the back of the queue with a quoted wait
+func (s *waitlistService) AddParty(ctx context.Context, req *CreateEntryRequest) (*Entry, error) {
+	// Shape validation (party size, name lengths) already done by handler using ValidateStruct
+	queue, err := s.repo.ListQueue(ctx)
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to load waitlist", err)
+	}
+	estimate, err := s.estimate(ctx, req.PartySize, queue)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &Entry{
+		ID:                uuid.New(),
+		PartySize:         req.PartySize,
+		GuestName:         strings.TrimSpace(req.GuestName),
+		GuestPhone:        strings.TrimSpace(req.GuestPhone),
+		Notes:             strings.TrimSpace(req.Notes),
+		Status:            StatusWaiting,
+		Position:          len(queue) + 1,
+		QuotedWaitMinutes: estimate.WaitMinutes,
+		CreatedAt:         time.Now(),
+	}
+	if err := s.repo.CreateEntry(ctx, entry); err != nil {
+		return nil, apperrors.WrapError(500, "failed to add party to waitlist", err)
+	}
+	return entry, nil
+}
+
+// GetEntry retrieves a waitlist entry, with its queue position while it is still queued
+func (s *waitlistService) GetEntry(ctx context.Context, id uuid.UUID) (*Entry, error) {
+	entry, err := s.repo.GetEntry(ctx, id)
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to retrieve waitlist entry", err)
+	}
+	if entry.IsQueued() {
+		queue, err := s.ListQueue(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, queued := range queue {
+			if queued.ID == entry.ID {
+				entry.Position = queued.Position
+				break
+			}
+		}
+	}
+	return entry, nil
+}
+
+// ListQueue lists the parties still waiting, numbered in arrival order
+func (s *waitlistService) ListQueue(ctx context.Context) ([]*Entry, error) {
+	queue, err := s.repo.ListQueue(ctx)
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to load waitlist", err)
+	}
+	for i, entry := range queue {
+		entry.Position = i + 1
+	}
+	return queue, nil
+}
+
+// EstimateWait quotes the wait a party of the given size would get if it joined the queue now
+func (s *waitlistService) EstimateWait(ctx context.Context, partySize int) (*WaitEstimate, error) {
+	queue, err := s.repo.ListQueue(ctx)
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to load waitlist", err)
+	}
+	return s.estimate(ctx, partySize, queue)
+}
+
+// NotifyParty records that the party was told its table is ready
+func (s *waitlistService) NotifyParty(ctx context.Context, id uuid.UUID) (*Entry, error) {
+	if err := s.repo.MarkNotified(ctx, id, time.Now()); err != nil {
+		return nil, apperrors.WrapError(500, "failed to notify party", err)
+	}
+	return s.GetEntry(ctx, id)
+}
+
+// SeatParty starts a dining session for a queued party and records how long it actually waited
+func (s *waitlistService) SeatParty(ctx context.Context, id uuid.UUID, tableID int) (*Entry, error) {
+	entry, err := s.repo.GetEntry(ctx, id)
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to retrieve waitlist entry", err)
+	}
+	if !entry.IsQueued() {
+		return nil, apperrors.NewValidationError("only queued parties can be seated, party is " + string(entry.Status))
+	}
+
+	table, err := s.sessionService.GetTable(ctx, tableID)
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to retrieve table", err)
+	}
+	if !table.Seats(entry.PartySize) {
+		return nil, apperrors.NewValidationError(fmt.Sprintf("table %d seats %d to %d guests", table.ID, table.MinCovers, table.MaxCovers))
+	}
+
+	// The session service refuses tables that are still occupied by a previous party
+	sess, err := s.sessionService.CreateSession(ctx, tableID, entry.PartySize)
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to start session for waitlist party", err)
+	}
+
+	if err := s.repo.MarkSeated(ctx, id, tableID, sess.ID, time.Now()); err != nil {
+		return nil, apperrors.WrapError(500, "failed to mark party as seated", err)
+	}
+	return s.GetEntry(ctx, id)
+}
+
+// RemoveParty takes a party off the list, e.g. when it leaves before a table frees up
+func (s *waitlistService) RemoveParty(ctx context.Context, id uuid.UUID) (*Entry, error) {
+	if err := s.repo.MarkRemoved(ctx, id, time.Now()); err != nil {
+		return nil, apperrors.WrapError(500, "failed to remove party from waitlist", err)
+	}
+	return s.GetEntry(ctx, id)
+}
+
+// estimate quotes a wait from the tables that seat the party (BUSINESS LOGIC):
+//   - each suitable table frees up one turn time after its live session started, or now if it is free or overdue;
+//   - queued parties that fit any of those tables are seated first, one per table as it frees up, and a table
+//     that has already been handed to a party ahead is free again one turn time later;
+//   - the result is scaled by how recent actual waits compared with their quotes.
+func (s *waitlistService) estimate(ctx context.Context, partySize int, queue []*Entry) (*WaitEstimate, error) {
+	tables, err := s.sessionService.ListTables(ctx, false)
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to list tables", err)
+	}
+	var suitable []*session.Table
+	var tableIDs []int
+	for _, table := range tables {
+		if table.Seats(partySize) {
+			suitable = append(suitable, table)
+			tableIDs = append(tableIDs, table.ID)
+		}
+	}
+	if len(suitable) == 0 {
+		return nil, apperrors.NewValidationError(fmt.Sprintf("no table seats a party of %d", partySize))
+	}
+
+	turnTime, err := s.repo.AverageTurnTime(ctx, tableIDs, time.Now().Add(-s.config.TurnTimeWindow))
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to compute turn time", err)
+	}
+	if turnTime <= 0 {
+		turnTime = s.config.DefaultTurnTime
+	}
+
+	holds, err := s.repo.ListTableHolds(ctx)
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to load table occupancy", err)
+	}
+	since := make(map[int]time.Time, len(holds))
+	for _, h := range holds {
+		since[h.TableID] = h.Since
+	}
+
+	now := time.Now()
+	freeIn := make([]time.Duration, 0, len(suitable))
+	for _, table := range suitable {
+		var remaining time.Duration
+		if start, ok := since[table.ID]; ok {
+			remaining = max(0, start.Add(turnTime).Sub(now))
+		}
+		freeIn = append(freeIn, remaining)
+	}
+	sort.Slice(freeIn, func(i, j int) bool { return freeIn[i] < freeIn[j] })
+
+	ahead := 0
+	for _, entry := range queue {
+		for _, table := range suitable {
+			if table.Seats(entry.PartySize) {
+				ahead++
+				break
+			}
+		}
+	}
+
+	wait := freeIn[ahead%len(freeIn)] + time.Duration(ahead/len(freeIn))*turnTime
+
+	accuracy, err := s.repo.RecentWaitAccuracy(ctx, s.config.CalibrationSamples)
+	if err != nil {
+		return nil, apperrors.WrapError(500, "failed to load recent waits", err)
+	}
+	if accuracy.Samples >= s.config.MinCalibration && accuracy.QuotedMinutes > 0 {
+		factor := float64(accuracy.ActualMinutes) / float64(accuracy.QuotedMinutes)
+		factor = math.Min(2, math.Max(0.5, factor))
+		wait = time.Duration(float64(wait) * factor)
+	}
+
+	return &WaitEstimate{
+		PartySize:    partySize,
+		PartiesAhead: ahead,
+		WaitMinutes:  int(math.Ceil(wait.Minutes())),
+	}, nil
+}
@@ -0,0 +1,34 @@
+package waitlist
+
+// CreateEntryRequest represents the request to add a walk-in party to the waitlist
+type CreateEntryRequest struct {
+	PartySize  int    `json:"party_size" validate:"required,min=1,max=100"`
+	GuestName  string `json:"guest_name" validate:"required,min=1,max=100"`
+	GuestPhone string `json:"guest_phone" validate:"omitempty,max=30"`
+	Notes      string `json:"notes" validate:"max=1000"`
+}
+
+// SeatEntryRequest represents the request to seat a waiting party at a table
+type SeatEntryRequest struct {
+	TableID int `json:"table_id" validate:"required,gt=0"`
+}
+
+// EstimateRequest represents a wait time query for a party size
+type EstimateRequest struct {
+	PartySize int `form:"party_size" validate:"required,min=1,max=100"`
+}
+
+// ValidateCreateEntry validates the create entry request
+func ValidateCreateEntry(req CreateEntryRequest) error {
+	return ValidateStruct(req)
+}
+
+// ValidateSeatEntry validates the seat entry request
+func ValidateSeatEntry(req SeatEntryRequest) error {
+	return ValidateStruct(req)
+}
+
+// ValidateEstimate validates the estimate request
+func ValidateEstimate(req EstimateRequest) error {
+	return ValidateStruct(req)
+}
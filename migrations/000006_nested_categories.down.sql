@@ -0,0 +1,10 @@
+-- Remove nested categories and display ordering
+-- Down migration
+
+DROP INDEX IF EXISTS idx_menu_items_sort_order;
+DROP INDEX IF EXISTS idx_categories_sort_order;
+DROP INDEX IF EXISTS idx_categories_parent_id;
+
+ALTER TABLE menu_items DROP COLUMN sort_order;
+ALTER TABLE categories DROP COLUMN sort_order;
+ALTER TABLE categories DROP COLUMN parent_id;
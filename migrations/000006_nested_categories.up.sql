@@ -0,0 +1,16 @@
+-- Add parent/child categories and explicit display ordering
+-- Up migration
+
+-- Categories can be nested under a parent category
+ALTER TABLE categories ADD COLUMN parent_id UUID REFERENCES categories(id);
+
+-- Explicit display order for categories among their siblings
+ALTER TABLE categories ADD COLUMN sort_order INTEGER NOT NULL DEFAULT 0;
+
+-- Explicit display order for menu items within their category
+ALTER TABLE menu_items ADD COLUMN sort_order INTEGER NOT NULL DEFAULT 0;
+
+-- Indexes for tree building and ordered listings
+CREATE INDEX IF NOT EXISTS idx_categories_parent_id ON categories(parent_id);
+CREATE INDEX IF NOT EXISTS idx_categories_sort_order ON categories(parent_id, sort_order);
+CREATE INDEX IF NOT EXISTS idx_menu_items_sort_order ON menu_items(category, sort_order);
@@ -0,0 +1,26 @@
+-- Remove soft delete columns
+-- Down migration
+
+-- Soft-deleted rows are removed permanently where nothing references them
+DELETE FROM menu_items WHERE deleted_at IS NOT NULL
+    AND NOT EXISTS (SELECT 1 FROM order_items WHERE order_items.menu_item_id = menu_items.id);
+
+DROP INDEX IF EXISTS idx_tables_live;
+DROP INDEX IF EXISTS idx_menu_items_live;
+DROP INDEX IF EXISTS idx_categories_name_live;
+
+-- Names were only unique among live categories; soft-deleted rows that share a name with another row are renamed
+-- instead of dropped, since menu items and child categories may still point at them
+WITH ranked AS (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY name ORDER BY deleted_at IS NOT NULL, deleted_at DESC, id) AS rn
+    FROM categories
+)
+UPDATE categories c SET name = LEFT(c.name, 40) || ' ~' || LEFT(c.id::text, 8)
+FROM ranked r
+WHERE r.id = c.id AND r.rn > 1;
+
+ALTER TABLE categories ADD CONSTRAINT categories_name_key UNIQUE (name);
+
+ALTER TABLE tables DROP COLUMN deleted_at;
+ALTER TABLE categories DROP COLUMN deleted_at;
+ALTER TABLE menu_items DROP COLUMN deleted_at;
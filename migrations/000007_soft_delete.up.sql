@@ -0,0 +1,14 @@
+-- Soft delete for menu items, categories and tables
+-- Up migration
+
+ALTER TABLE menu_items ADD COLUMN deleted_at TIMESTAMP;
+ALTER TABLE categories ADD COLUMN deleted_at TIMESTAMP;
+ALTER TABLE tables ADD COLUMN deleted_at TIMESTAMP;
+
+-- Category names only need to be unique among categories that are not deleted
+ALTER TABLE categories DROP CONSTRAINT IF EXISTS categories_name_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_name_live ON categories(LOWER(name)) WHERE deleted_at IS NULL;
+
+-- Partial indexes keep default listings fast
+CREATE INDEX IF NOT EXISTS idx_menu_items_live ON menu_items(created_at DESC) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_tables_live ON tables(id) WHERE deleted_at IS NULL;
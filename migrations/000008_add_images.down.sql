@@ -0,0 +1,8 @@
+-- Remove image and thumbnail URLs
+-- Down migration
+
+ALTER TABLE categories DROP COLUMN thumbnail_url;
+ALTER TABLE categories DROP COLUMN image_url;
+
+ALTER TABLE menu_items DROP COLUMN thumbnail_url;
+ALTER TABLE menu_items DROP COLUMN image_url;
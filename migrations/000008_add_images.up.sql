@@ -0,0 +1,8 @@
+-- Add image and thumbnail URLs to menu items and categories
+-- Up migration
+
+ALTER TABLE menu_items ADD COLUMN image_url TEXT NOT NULL DEFAULT '';
+ALTER TABLE menu_items ADD COLUMN thumbnail_url TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE categories ADD COLUMN image_url TEXT NOT NULL DEFAULT '';
+ALTER TABLE categories ADD COLUMN thumbnail_url TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,5 @@
+-- Remove menu content translations
+-- Down migration
+
+DROP TABLE IF EXISTS category_translations;
+DROP TABLE IF EXISTS menu_item_translations;
@@ -0,0 +1,24 @@
+-- Translations of menu content keyed by locale
+-- Up migration
+-- Base columns on menu_items and categories hold the default language
+
+CREATE TABLE IF NOT EXISTS menu_item_translations (
+    menu_item_id VARCHAR(36) NOT NULL REFERENCES menu_items(id) ON DELETE CASCADE,
+    locale VARCHAR(35) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (menu_item_id, locale)
+);
+
+CREATE TABLE IF NOT EXISTS category_translations (
+    category_id UUID NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    locale VARCHAR(35) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (category_id, locale)
+);
+
+-- Missing-translation reports scan by locale
+CREATE INDEX IF NOT EXISTS idx_menu_item_translations_locale ON menu_item_translations(locale);
+CREATE INDEX IF NOT EXISTS idx_category_translations_locale ON category_translations(locale);
@@ -0,0 +1,4 @@
+-- Remove menu item price history
+-- Down migration
+
+DROP TABLE IF EXISTS menu_item_prices;
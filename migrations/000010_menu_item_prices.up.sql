@@ -0,0 +1,28 @@
+-- Price history and scheduled price changes for menu items
+-- Up migration
+-- Each row holds the price over [effective_from, effective_to); a NULL effective_to is open-ended.
+-- menu_items.price mirrors the row in effect and is updated by the price scheduler once a change is due.
+
+CREATE TABLE IF NOT EXISTS menu_item_prices (
+    id UUID PRIMARY KEY,
+    menu_item_id VARCHAR(36) NOT NULL REFERENCES menu_items(id) ON DELETE CASCADE,
+    price DECIMAL(10,2) NOT NULL CHECK (price > 0),
+    effective_from TIMESTAMPTZ NOT NULL,
+    effective_to TIMESTAMPTZ,
+    applied_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT menu_item_prices_range_check CHECK (effective_to IS NULL OR effective_to > effective_from),
+    CONSTRAINT menu_item_prices_item_from_key UNIQUE (menu_item_id, effective_from)
+);
+
+-- Only the latest price of an item may be open-ended
+CREATE UNIQUE INDEX IF NOT EXISTS idx_menu_item_prices_open ON menu_item_prices(menu_item_id) WHERE effective_to IS NULL;
+
+-- The scheduler scans changes that have not been applied yet
+CREATE INDEX IF NOT EXISTS idx_menu_item_prices_pending ON menu_item_prices(effective_from) WHERE applied_at IS NULL;
+
+-- Seed the history with the current price of every item
+INSERT INTO menu_item_prices (id, menu_item_id, price, effective_from, applied_at)
+SELECT gen_random_uuid(), id, price, created_at, created_at
+FROM menu_items
+WHERE price > 0;
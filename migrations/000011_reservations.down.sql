@@ -0,0 +1,4 @@
+-- Remove table reservations
+-- Down migration
+
+DROP TABLE IF EXISTS reservations;
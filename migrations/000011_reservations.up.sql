@@ -0,0 +1,26 @@
+-- Table reservations
+-- Up migration
+-- A reservation holds a table over [starts_at, ends_at); booked and seated reservations block the table
+
+CREATE TABLE IF NOT EXISTS reservations (
+    id VARCHAR(36) PRIMARY KEY,
+    table_id INTEGER NOT NULL REFERENCES tables(id),
+    party_size INTEGER NOT NULL CHECK (party_size > 0),
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL,
+    guest_name VARCHAR(100) NOT NULL,
+    guest_phone VARCHAR(30) NOT NULL DEFAULT '',
+    guest_email VARCHAR(255) NOT NULL DEFAULT '',
+    notes TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'booked' CHECK (status IN ('booked', 'seated', 'no_show', 'cancelled')),
+    session_id VARCHAR(36) REFERENCES sessions(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT reservations_slot_check CHECK (ends_at > starts_at)
+);
+
+-- Conflict checks look up blocking reservations of a table by time
+CREATE INDEX IF NOT EXISTS idx_reservations_table_slot ON reservations(table_id, starts_at, ends_at) WHERE status IN ('booked', 'seated');
+
+-- Booking calendar lists reservations by day
+CREATE INDEX IF NOT EXISTS idx_reservations_starts_at ON reservations(starts_at);
@@ -0,0 +1,16 @@
+-- Remove table capacity, zones and floor plan layout
+-- Down migration
+
+DROP INDEX IF EXISTS idx_tables_zone_covers;
+
+ALTER TABLE tables DROP CONSTRAINT IF EXISTS tables_rotation_check;
+ALTER TABLE tables DROP CONSTRAINT IF EXISTS tables_shape_check;
+ALTER TABLE tables DROP CONSTRAINT IF EXISTS tables_covers_check;
+
+ALTER TABLE tables DROP COLUMN rotation;
+ALTER TABLE tables DROP COLUMN pos_y;
+ALTER TABLE tables DROP COLUMN pos_x;
+ALTER TABLE tables DROP COLUMN shape;
+ALTER TABLE tables DROP COLUMN zone;
+ALTER TABLE tables DROP COLUMN max_covers;
+ALTER TABLE tables DROP COLUMN min_covers;
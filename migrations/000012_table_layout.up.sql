@@ -0,0 +1,18 @@
+-- Table capacity, zones and floor plan layout
+-- Up migration
+-- x and y position the table centre on the floor plan in the UI's units; rotation is in degrees
+
+ALTER TABLE tables ADD COLUMN min_covers INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE tables ADD COLUMN max_covers INTEGER NOT NULL DEFAULT 4;
+ALTER TABLE tables ADD COLUMN zone VARCHAR(50) NOT NULL DEFAULT 'main';
+ALTER TABLE tables ADD COLUMN shape VARCHAR(20) NOT NULL DEFAULT 'square';
+ALTER TABLE tables ADD COLUMN pos_x DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE tables ADD COLUMN pos_y DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE tables ADD COLUMN rotation INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE tables ADD CONSTRAINT tables_covers_check CHECK (min_covers >= 1 AND max_covers >= min_covers);
+ALTER TABLE tables ADD CONSTRAINT tables_shape_check CHECK (shape IN ('square', 'round', 'rectangle', 'booth'));
+ALTER TABLE tables ADD CONSTRAINT tables_rotation_check CHECK (rotation >= 0 AND rotation < 360);
+
+-- Availability searches filter live tables by zone and size
+CREATE INDEX IF NOT EXISTS idx_tables_zone_covers ON tables(zone, max_covers) WHERE deleted_at IS NULL;
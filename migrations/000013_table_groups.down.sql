@@ -0,0 +1,5 @@
+-- Remove table groups
+-- Down migration
+
+DROP VIEW IF EXISTS session_table_assignments;
+DROP TABLE IF EXISTS session_tables;
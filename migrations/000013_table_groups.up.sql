@@ -0,0 +1,21 @@
+-- Table groups: several tables pushed together for one session
+-- Up migration
+-- sessions.table_id stays the primary table; session_tables holds the extra tables joined to it
+
+CREATE TABLE IF NOT EXISTS session_tables (
+    session_id VARCHAR(36) NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    table_id INTEGER NOT NULL REFERENCES tables(id),
+    joined_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (session_id, table_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_session_tables_table_id ON session_tables(table_id);
+
+-- Every table a session holds, primary or joined. Occupancy checks read this instead of sessions.table_id,
+-- so joined tables are released together with the session once it completes or is cancelled.
+CREATE OR REPLACE VIEW session_table_assignments AS
+    SELECT id AS session_id, table_id, status, created_at FROM sessions
+    UNION ALL
+    SELECT s.id, st.table_id, s.status, s.created_at
+    FROM session_tables st
+    JOIN sessions s ON s.id = st.session_id;
@@ -0,0 +1,4 @@
+-- Remove walk-in waitlist
+-- Down migration
+
+DROP TABLE IF EXISTS waitlist_entries;
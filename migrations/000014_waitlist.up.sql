@@ -0,0 +1,26 @@
+-- Walk-in waitlist
+-- Up migration
+-- Parties wait in created_at order; quoted and actual waits are kept so future quotes can be calibrated
+
+CREATE TABLE IF NOT EXISTS waitlist_entries (
+    id VARCHAR(36) PRIMARY KEY,
+    party_size INTEGER NOT NULL CHECK (party_size > 0),
+    guest_name VARCHAR(100) NOT NULL,
+    guest_phone VARCHAR(30) NOT NULL DEFAULT '',
+    notes TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'waiting' CHECK (status IN ('waiting', 'notified', 'seated', 'removed')),
+    quoted_wait_minutes INTEGER NOT NULL DEFAULT 0 CHECK (quoted_wait_minutes >= 0),
+    actual_wait_minutes INTEGER CHECK (actual_wait_minutes >= 0),
+    table_id INTEGER REFERENCES tables(id),
+    session_id VARCHAR(36) REFERENCES sessions(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    notified_at TIMESTAMPTZ,
+    seated_at TIMESTAMPTZ,
+    removed_at TIMESTAMPTZ
+);
+
+-- The live queue is read in arrival order
+CREATE INDEX IF NOT EXISTS idx_waitlist_entries_queue ON waitlist_entries(created_at) WHERE status IN ('waiting', 'notified');
+
+-- Quote calibration reads the most recently seated parties
+CREATE INDEX IF NOT EXISTS idx_waitlist_entries_seated_at ON waitlist_entries(seated_at) WHERE status = 'seated';
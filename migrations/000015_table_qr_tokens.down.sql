@@ -0,0 +1,5 @@
+-- Remove QR tokens for tables
+-- Down migration
+
+ALTER TABLE tables DROP COLUMN IF EXISTS qr_token_rotated_at;
+ALTER TABLE tables DROP COLUMN IF EXISTS qr_token_nonce;
@@ -0,0 +1,6 @@
+-- Rotatable QR tokens for tables
+-- Up migration
+-- Each table's QR token embeds this nonce; replacing it revokes every code printed for the table
+
+ALTER TABLE tables ADD COLUMN IF NOT EXISTS qr_token_nonce VARCHAR(32) NOT NULL DEFAULT replace(gen_random_uuid()::text, '-', '');
+ALTER TABLE tables ADD COLUMN IF NOT EXISTS qr_token_rotated_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
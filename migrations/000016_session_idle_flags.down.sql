@@ -0,0 +1,5 @@
+-- Remove idle session sweeper state
+-- Down migration
+
+DROP INDEX IF EXISTS idx_sessions_live;
+ALTER TABLE sessions DROP COLUMN IF EXISTS idle_flagged_at;
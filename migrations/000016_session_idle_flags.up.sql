@@ -0,0 +1,9 @@
+-- Idle session sweeper
+-- Up migration
+-- idle_flagged_at records when the sweeper last warned about an idle session; it only counts while no order
+-- has been placed since
+
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS idle_flagged_at TIMESTAMPTZ;
+
+-- The sweeper scans live sessions by status
+CREATE INDEX IF NOT EXISTS idx_sessions_live ON sessions(status, created_at) WHERE status IN ('active', 'pending');
@@ -0,0 +1,5 @@
+-- Remove guest count from sessions
+-- Down migration
+
+ALTER TABLE sessions DROP CONSTRAINT IF EXISTS sessions_guest_count_check;
+ALTER TABLE sessions DROP COLUMN IF EXISTS guest_count;
@@ -0,0 +1,6 @@
+-- Guest count (covers) on sessions
+-- Up migration
+-- NULL for sessions started before guest counts were recorded
+
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS guest_count INTEGER;
+ALTER TABLE sessions ADD CONSTRAINT sessions_guest_count_check CHECK (guest_count IS NULL OR guest_count > 0);
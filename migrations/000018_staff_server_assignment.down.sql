@@ -0,0 +1,9 @@
+-- Remove staff, shift section assignments and server assignment
+-- Down migration
+
+DROP INDEX IF EXISTS idx_orders_server_id;
+DROP INDEX IF EXISTS idx_sessions_server_id;
+ALTER TABLE orders DROP COLUMN IF EXISTS server_id;
+ALTER TABLE sessions DROP COLUMN IF EXISTS server_id;
+DROP TABLE IF EXISTS staff_shifts;
+DROP TABLE IF EXISTS staff;
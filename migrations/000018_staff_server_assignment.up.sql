@@ -0,0 +1,31 @@
+-- Staff, shift section assignments and the server owning each session
+-- Up migration
+-- A section is a table zone; a server on shift for a zone is assigned to sessions started there
+
+CREATE TABLE IF NOT EXISTS staff (
+    id VARCHAR(36) PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    role VARCHAR(20) NOT NULL DEFAULT 'server' CHECK (role IN ('server', 'host', 'manager')),
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS staff_shifts (
+    id VARCHAR(36) PRIMARY KEY,
+    staff_id VARCHAR(36) NOT NULL REFERENCES staff(id) ON DELETE CASCADE,
+    zone VARCHAR(50) NOT NULL,
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (ends_at > starts_at)
+);
+
+-- Auto-assignment looks up the shifts covering a zone right now
+CREATE INDEX IF NOT EXISTS idx_staff_shifts_zone ON staff_shifts(zone, starts_at, ends_at);
+CREATE INDEX IF NOT EXISTS idx_staff_shifts_staff_id ON staff_shifts(staff_id, starts_at);
+
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS server_id VARCHAR(36) REFERENCES staff(id) ON DELETE SET NULL;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS server_id VARCHAR(36) REFERENCES staff(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_sessions_server_id ON sessions(server_id) WHERE status IN ('active', 'pending');
+CREATE INDEX IF NOT EXISTS idx_orders_server_id ON orders(server_id, status);
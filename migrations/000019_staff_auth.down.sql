@@ -0,0 +1,5 @@
+-- Remove staff login credentials and revoked tokens
+-- Down migration
+
+DROP TABLE IF EXISTS revoked_tokens;
+DROP TABLE IF EXISTS staff_credentials;
@@ -0,0 +1,18 @@
+-- Staff login credentials and revoked tokens
+-- Up migration
+-- Tokens are stateless JWTs; only those revoked before they expire are stored, and can be purged once expired
+
+CREATE TABLE IF NOT EXISTS staff_credentials (
+    staff_id VARCHAR(36) PRIMARY KEY REFERENCES staff(id) ON DELETE CASCADE,
+    username VARCHAR(50) NOT NULL UNIQUE,
+    password_hash VARCHAR(100) NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+    id VARCHAR(36) PRIMARY KEY,
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_revoked_tokens_expires_at ON revoked_tokens(expires_at);
@@ -0,0 +1,7 @@
+-- Remove the kitchen staff role
+-- Down migration
+-- Kitchen staff become servers so the original constraint can be restored
+
+UPDATE staff SET role = 'server' WHERE role = 'kitchen';
+ALTER TABLE staff DROP CONSTRAINT IF EXISTS staff_role_check;
+ALTER TABLE staff ADD CONSTRAINT staff_role_check CHECK (role IN ('server', 'host', 'manager'));
@@ -0,0 +1,6 @@
+-- Kitchen staff role
+-- Up migration
+-- Kitchen staff move orders through preparation; permissions per role are declared in the middleware package
+
+ALTER TABLE staff DROP CONSTRAINT IF EXISTS staff_role_check;
+ALTER TABLE staff ADD CONSTRAINT staff_role_check CHECK (role IN ('server', 'host', 'kitchen', 'manager'));
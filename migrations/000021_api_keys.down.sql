@@ -0,0 +1,4 @@
+-- Remove API keys
+-- Down migration
+
+DROP TABLE IF EXISTS api_keys;
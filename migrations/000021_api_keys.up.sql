@@ -0,0 +1,21 @@
+-- API keys for machine integrations
+-- Up migration
+-- Only a SHA-256 hash of each key is stored; the prefix identifies the key without revealing it
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id VARCHAR(36) PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    prefix VARCHAR(20) NOT NULL UNIQUE,
+    key_hash CHAR(64) NOT NULL,
+    scopes TEXT[] NOT NULL,
+    requests_per_second INTEGER CHECK (requests_per_second > 0),
+    burst_size INTEGER CHECK (burst_size > 0),
+    expires_at TIMESTAMPTZ,
+    last_used_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    rotated_from VARCHAR(36) REFERENCES api_keys(id) ON DELETE SET NULL,
+    created_by VARCHAR(36) REFERENCES staff(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_keys_rotated_from ON api_keys(rotated_from);
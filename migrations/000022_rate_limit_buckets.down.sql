@@ -0,0 +1,4 @@
+-- Remove shared rate limit buckets
+-- Down migration
+
+DROP TABLE IF EXISTS rate_limit_buckets;
@@ -0,0 +1,12 @@
+-- Shared rate limit buckets
+-- Up migration
+-- Unlogged, since buckets only matter for a few minutes and are not worth WAL traffic or surviving a crash
+
+CREATE UNLOGGED TABLE IF NOT EXISTS rate_limit_buckets (
+    bucket_key TEXT PRIMARY KEY,
+    tokens DOUBLE PRECISION NOT NULL,
+    burst DOUBLE PRECISION NOT NULL,
+    rate DOUBLE PRECISION NOT NULL,
+    allowed BOOLEAN NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
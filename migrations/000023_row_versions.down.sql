@@ -0,0 +1,7 @@
+-- Remove row versions
+-- Down migration
+
+ALTER TABLE sessions DROP COLUMN IF EXISTS version;
+ALTER TABLE orders DROP COLUMN IF EXISTS version;
+ALTER TABLE categories DROP COLUMN IF EXISTS version;
+ALTER TABLE menu_items DROP COLUMN IF EXISTS version;
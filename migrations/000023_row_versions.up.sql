@@ -0,0 +1,8 @@
+-- Row versions for optimistic concurrency
+-- Up migration
+-- Every write bumps version; updates name the version they were made against and fail if it has moved on
+
+ALTER TABLE menu_items ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,4 @@
+-- Remove Idempotency-Key records
+-- Down migration
+
+DROP TABLE IF EXISTS idempotency_keys;
@@ -0,0 +1,15 @@
+-- Idempotency-Key records
+-- Up migration
+-- A record holds the response of the first request made with a key, so retries replay it instead of running again
+
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    record_key TEXT PRIMARY KEY,
+    fingerprint TEXT NOT NULL,
+    status_code INTEGER,
+    content_type TEXT,
+    response_body BYTEA,
+    locked_at TIMESTAMPTZ NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
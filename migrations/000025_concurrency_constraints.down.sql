@@ -0,0 +1,6 @@
+-- Remove the unique constraints backing concurrent order item and session writes
+-- Down migration
+-- Merged order lines and cancelled duplicate sessions are not restored
+
+DROP INDEX IF EXISTS idx_sessions_live_table;
+DROP INDEX IF EXISTS idx_order_items_order_menu_item;
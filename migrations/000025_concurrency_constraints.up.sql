@@ -0,0 +1,34 @@
+-- Unique constraints backing concurrent order item and session writes
+-- Up migration
+-- Races before this migration may have left duplicates behind; they are folded together first so the indexes can be built
+
+-- One line per menu item in an order: duplicate lines are merged into the first one
+WITH merged AS (
+    SELECT order_id, menu_item_id, MIN(id) AS keep_id, SUM(quantity) AS quantity
+    FROM order_items
+    GROUP BY order_id, menu_item_id
+    HAVING COUNT(*) > 1
+)
+UPDATE order_items oi SET quantity = m.quantity
+FROM merged m
+WHERE oi.id = m.keep_id;
+
+DELETE FROM order_items oi
+USING order_items keep
+WHERE keep.order_id = oi.order_id
+    AND keep.menu_item_id = oi.menu_item_id
+    AND keep.id < oi.id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_order_items_order_menu_item ON order_items(order_id, menu_item_id);
+
+-- One live session per table: of several active or pending sessions on a table, all but the newest are cancelled
+UPDATE sessions s SET status = 'cancelled', version = s.version + 1
+WHERE s.status IN ('active', 'pending')
+    AND EXISTS (
+        SELECT 1 FROM sessions newer
+        WHERE newer.table_id = s.table_id
+            AND newer.status IN ('active', 'pending')
+            AND (newer.created_at, newer.id) > (s.created_at, s.id)
+    );
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sessions_live_table ON sessions(table_id) WHERE status IN ('active', 'pending');